@@ -1,9 +1,13 @@
 package main
 
 import (
+	"flag"
 	_ "github.com/tclutin/classflow-api/docs"
 	"github.com/tclutin/classflow-api/internal/app"
+	"github.com/tclutin/classflow-api/internal/config"
 	"golang.org/x/net/context"
+	"log"
+	"os"
 )
 
 //	@title			ClassFlow API
@@ -19,5 +23,25 @@ import (
 //	@description				Use "Bearer <token>" to authenticate
 
 func main() {
-	app.NewApp().Run(context.Background())
+	configPath := flag.String("config", os.Getenv("CONFIG_PATH"), "path to an optional YAML config file, environment variables override it")
+	printConfig := flag.Bool("print-config", false, "print the effective config with secrets redacted and exit")
+	skipMigrations := flag.Bool("skip-migrations", false, "do not apply migrations and seeds on start, use cmd/migrate instead")
+	flag.Parse()
+
+	cfg := config.MustLoad(*configPath)
+
+	if *skipMigrations {
+		cfg.Migrations.OnStart = false
+	}
+
+	if *printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	if err := app.NewApp(cfg).Run(context.Background()); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/tclutin/classflow-api/internal/config"
+	"github.com/tclutin/classflow-api/internal/migrator"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `Usage: migrate [flags] <command>
+
+Commands:
+  up             apply all pending migrations and seeds
+  down           roll back the latest migration
+  redo           roll back the latest migration and apply it again
+  status         print the state of every migration
+  seed           apply seeds only
+  create <name>  add an empty SQL migration
+
+Flags:
+`
+
+func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_PATH"), "path to an optional YAML config file, environment variables override it")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	if err := run(*configPath, flag.Args(), logger); err != nil {
+		logger.Error("Migration command failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, args []string, logger *slog.Logger) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("command is required")
+	}
+
+	command := args[0]
+
+	if command == "create" {
+		if len(args) != 2 {
+			return fmt.Errorf("create requires a migration name")
+		}
+
+		return migrator.Create(migrator.MigrationsDir, args[1])
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := postgresql.NewPool(ctx, cfg.Postgres.PrimaryPool(), logger)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migr, err := migrator.New(pool, logger)
+	if err != nil {
+		return err
+	}
+	defer migr.Close()
+
+	switch command {
+	case "up":
+		if err = migr.Up(ctx); err != nil {
+			return err
+		}
+		return migr.Seed(ctx)
+	case "down":
+		return migr.Down(ctx)
+	case "redo":
+		return migr.Redo(ctx)
+	case "seed":
+		return migr.Seed(ctx)
+	case "status":
+		return printStatus(ctx, migr)
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+func printStatus(ctx context.Context, migr *migrator.Migration) error {
+	statuses, err := migr.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Source.Version, status.State, appliedAt, status.Source.Path)
+	}
+
+	return w.Flush()
+}
@@ -15,9 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Аутентификация админ пользователя",
+        "/admins/{user_id}/faculties": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить факультеты администратора",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,56 +30,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admins"
                 ],
-                "summary": "LogIn",
+                "summary": "GetFaculties",
                 "parameters": [
                     {
-                        "description": "Аутентификация пользователя",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.LogInRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.TokenResponse"
+                            "$ref": "#/definitions/admin.FacultiesResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/auth/signup": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Создание нового админ пользователя",
+                "description": "Назначить администратора факультета, глобального администратора нужно явно понизить через demote",
                 "consumes": [
                     "application/json"
                 ],
@@ -82,51 +83,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admins"
                 ],
-                "summary": "SignUp",
+                "summary": "AssignFaculty",
                 "parameters": [
                     {
-                        "description": "Создать пользователя",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Назначить факультет",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.SignUpRequest"
+                            "$ref": "#/definitions/admin.AssignFacultyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.TokenResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/telegram/login": {
-            "post": {
-                "description": "Аутентификация студента",
+        "/admins/{user_id}/faculties/{faculty_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снять администратора с факультета, без последнего факультета он теряет роль администратора",
                 "consumes": [
                     "application/json"
                 ],
@@ -134,45 +153,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admins"
                 ],
-                "summary": "LogIn with telegram chat id",
+                "summary": "RevokeFaculty",
                 "parameters": [
                     {
-                        "description": "Аутентификация студента",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.LogInWithTelegramRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Faculty ID",
+                        "name": "faculty_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.TokenResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/telegram/signup": {
+        "/attendance/checkin": {
             "post": {
-                "description": "Создание студента с telegram chat id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отметиться на занятии кодом из открытой отметки группы, геопозиция нужна, если отметка ограничена расстоянием до корпуса. Уже выставленная отметка не меняется",
                 "consumes": [
                     "application/json"
                 ],
@@ -180,56 +215,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "attendance"
                 ],
-                "summary": "SignUp with telegram chat id",
+                "summary": "CheckIn",
                 "parameters": [
                     {
-                        "description": "Создать студента",
+                        "description": "Код",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.SignUpWithTelegramRequest"
+                            "$ref": "#/definitions/attendance.CheckInRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.TokenResponse"
+                            "$ref": "#/definitions/attendance.CheckInResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/who": {
+        "/attendance/me": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получение информации о пользователе",
+                "description": "Получить свою посещаемость за период",
                 "consumes": [
                     "application/json"
                 ],
@@ -237,45 +290,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "attendance"
+                ],
+                "summary": "GetMine",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-09-01",
+                        "description": "From",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-12-31",
+                        "description": "To",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Who",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.UserDetailsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/attendance.RecordResponse"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/edu/buildings": {
+        "/audit": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получить список корпусов",
+                "description": "Получить журнал аудита",
                 "consumes": [
                     "application/json"
                 ],
@@ -283,36 +361,87 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "edu"
+                    "audit"
+                ],
+                "summary": "GetAll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Actor ID",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "group",
+                            "user",
+                            "announcement",
+                            "homework"
+                        ],
+                        "type": "string",
+                        "description": "Target type",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "GetAllBuildings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/edu.BuildingResponse"
+                                "$ref": "#/definitions/audit.RecordResponse"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/edu/faculties": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Получить список всех факультетов",
+        "/auth/login": {
+            "post": {
+                "description": "Аутентификация админ пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -320,36 +449,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "edu"
+                    "auth"
+                ],
+                "summary": "LogIn",
+                "parameters": [
+                    {
+                        "description": "Аутентификация пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LogInRequest"
+                        }
+                    }
                 ],
-                "summary": "GetAllFaculties",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/edu.FacultyResponse"
-                            }
+                            "$ref": "#/definitions/auth.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/edu/faculties/{faculty_id}/programs": {
-            "get": {
+        "/auth/signup": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получить всех программ факультета",
+                "description": "Создание нового админ пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -357,51 +512,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "edu"
+                    "auth"
                 ],
-                "summary": "GetProgramsByFacultyId",
+                "summary": "SignUp",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "faculty ID",
-                        "name": "faculty_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Создать пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.SignUpRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/edu.ProgramResponse"
-                            }
+                            "$ref": "#/definitions/auth.TokenResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/edu/types_of_subject": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Получить список типов всех предметов",
+        "/auth/telegram/login": {
+            "post": {
+                "description": "Аутентификация студента",
                 "consumes": [
                     "application/json"
                 ],
@@ -409,85 +564,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "edu"
+                    "auth"
+                ],
+                "summary": "LogIn with telegram chat id",
+                "parameters": [
+                    {
+                        "description": "Аутентификация студента",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LogInWithTelegramRequest"
+                        }
+                    }
                 ],
-                "summary": "GetAllTypesOfSubject",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/edu.TypeOfSubjectResponse"
-                            }
+                            "$ref": "#/definitions/auth.TokenResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
-                    }
-                }
-            }
-        },
-        "/groups": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Получить список групп",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "groups"
-                ],
-                "summary": "GetAllGroupsSummary",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Faculty name",
-                        "name": "faculty",
-                        "in": "query"
                     },
-                    {
-                        "type": "string",
-                        "description": "Program name",
-                        "name": "program",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/group.SummaryGroupResponse"
-                            }
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/telegram/signup": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Создать группу",
+                "description": "Создание студента с telegram chat id",
                 "consumes": [
                     "application/json"
                 ],
@@ -495,17 +616,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "auth"
                 ],
-                "summary": "Create",
+                "summary": "SignUp with telegram chat id",
                 "parameters": [
                     {
-                        "description": "Create a new group",
+                        "description": "Создать студента",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/group.CreateGroupRequest"
+                            "$ref": "#/definitions/auth.SignUpWithTelegramRequest"
                         }
                     }
                 ],
@@ -513,44 +634,38 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/auth.TokenResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/groups/leave": {
-            "post": {
+        "/auth/who": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Покинуть группу",
+                "description": "Получение информации о пользователе",
                 "consumes": [
                     "application/json"
                 ],
@@ -558,45 +673,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "auth"
                 ],
-                "summary": "LeaveFromGroup",
+                "summary": "Who",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/auth.UserDetailsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/groups/me": {
+        "/edu/buildings": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получить текущую группу",
+                "description": "Получить список корпусов",
                 "consumes": [
                     "application/json"
                 ],
@@ -604,51 +719,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "edu"
                 ],
-                "summary": "GetCurrentGroup",
+                "summary": "GetAllBuildings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/group.DetailsGroupResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/edu.BuildingResponse"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/groups/{group_id}": {
-            "delete": {
+        "/edu/faculties": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удалить группу",
+                "description": "Получить список всех факультетов",
                 "consumes": [
                     "application/json"
                 ],
@@ -656,54 +756,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
-                ],
-                "summary": "Delete",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Group ID",
-                        "name": "group_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "edu"
                 ],
+                "summary": "GetAllFaculties",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/edu.FacultyResponse"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/groups/{group_id}/join": {
-            "post": {
+        "/edu/faculties/{faculty_id}/programs": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Присоединиться к группе",
+                "description": "Получить всех программ факультета",
                 "consumes": [
                     "application/json"
                 ],
@@ -711,14 +793,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "edu"
                 ],
-                "summary": "JoinToGroup",
+                "summary": "GetProgramsByFacultyId",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
-                        "name": "group_id",
+                        "description": "faculty ID",
+                        "name": "faculty_id",
                         "in": "path",
                         "required": true
                     }
@@ -727,44 +809,35 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/edu.ProgramResponse"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/groups/{group_id}/schedule": {
+        "/edu/types_of_subject": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получить расписание",
+                "description": "Получить список типов всех предметов",
                 "consumes": [
                     "application/json"
                 ],
@@ -772,65 +845,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
-                ],
-                "summary": "GetScheduleByGroupId",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Group ID",
-                        "name": "group_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            "true",
-                            "false"
-                        ],
-                        "type": "string",
-                        "description": "Even of week",
-                        "name": "week_even",
-                        "in": "query"
-                    }
+                    "edu"
                 ],
+                "summary": "GetAllTypesOfSubject",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/group.DetailsScheduleResponse"
+                                "$ref": "#/definitions/edu.TypeOfSubjectResponse"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIError"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/groups": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Загрузить расписание",
+                "description": "Получить список групп с пагинацией, сортировкой и поиском",
                 "consumes": [
                     "application/json"
                 ],
@@ -840,67 +884,183 @@ const docTemplate = `{
                 "tags": [
                     "groups"
                 ],
-                "summary": "UploadSchedule",
+                "summary": "GetAllGroupsSummary",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
-                        "name": "group_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Faculty name",
+                        "name": "faculty",
+                        "in": "query"
                     },
                     {
-                        "description": "Загрузить расписание",
+                        "type": "string",
+                        "description": "Program name",
+                        "name": "program",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fuzzy search by short name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Faculty IDs",
+                        "name": "faculty_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Program IDs",
+                        "name": "program_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Exists schedule",
+                        "name": "exists_schedule",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "size",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort by",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/group.SummaryGroupsPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создать группу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create",
+                "parameters": [
+                    {
+                        "description": "Create a new group",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/group.UploadScheduleRequest"
+                            "$ref": "#/definitions/group.CreateGroupRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "type": "integer"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/users/settings": {
-            "patch": {
+        "/groups/leave": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Обновление настроек студента",
+                "description": "Покинуть группу",
                 "consumes": [
                     "application/json"
                 ],
@@ -908,289 +1068,3401 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "groups"
                 ],
-                "summary": "UpdateSettings",
-                "parameters": [
-                    {
-                        "description": "Update a user's account",
-                        "name": "input",
-                        "in": "body",
+                "summary": "LeaveFromGroup",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.UpdateUserSettingsRequest"
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
+                }
+            }
+        },
+        "/groups/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить текущую группу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "GetCurrentGroup",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/group.DetailsGroupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.APIError"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.LogInRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "maxLength": 40
-                },
-                "password": {
-                    "type": "string",
-                    "maxLength": 40,
-                    "minLength": 8
-                }
-            }
-        },
-        "auth.LogInWithTelegramRequest": {
-            "type": "object",
-            "required": [
-                "telegram_chat_id"
-            ],
-            "properties": {
-                "telegram_chat_id": {
-                    "type": "integer",
-                    "minimum": 1
-                }
-            }
         },
-        "auth.SignUpRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "maxLength": 40
-                },
-                "password": {
-                    "type": "string",
-                    "maxLength": 40,
-                    "minLength": 8
+        "/groups/waitlist": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Покинуть лист ожидания",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "LeaveWaitlist",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
                 }
             }
         },
-        "auth.SignUpWithTelegramRequest": {
-            "type": "object",
-            "required": [
-                "full_name",
-                "telegram_chat_id",
-                "telegram_username"
-            ],
-            "properties": {
-                "full_name": {
-                    "type": "string",
-                    "maxLength": 40
-                },
-                "telegram_chat_id": {
+        "/groups/{group_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удалить группу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/agenda": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить расписание по датам вместе с сессией и сроками сдачи домашних заданий, по умолчанию на ближайшую неделю",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homework"
+                ],
+                "summary": "GetAgenda",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-19",
+                        "description": "From",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-25",
+                        "description": "To",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/homework.AgendaDayResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/announcements": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить объявления группы, закреплённые идут первыми",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "announcements"
+                ],
+                "summary": "GetAllByGroupId",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include expired",
+                        "name": "include_expired",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/announcement.AnnouncementsPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Опубликовать объявление группы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "announcements"
+                ],
+                "summary": "Create",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое объявление",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/announcement.CreateAnnouncementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/announcements/{announcement_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменить объявление группы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "announcements"
+                ],
+                "summary": "Update",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Announcement ID",
+                        "name": "announcement_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Объявление",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/announcement.UpdateAnnouncementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удалить объявление группы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "announcements"
+                ],
+                "summary": "Delete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Announcement ID",
+                        "name": "announcement_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/announcements/{announcement_id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отметить объявление прочитанным",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "announcements"
+                ],
+                "summary": "MarkRead",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Announcement ID",
+                        "name": "announcement_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/attendance": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить список группы с отметками посещаемости на занятии в указанный день",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "GetSheet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "schedule_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-19",
+                        "description": "Date",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/attendance.SheetEntryResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отметить посещаемость на прошедшем занятии, повторная отметка заменяет статус",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Mark",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Отметки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/attendance.MarkLessonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/attendance/checkin": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Открыть отметку на сегодняшнем занятии, студенты отмечаются кодом, который меняется каждые 30 секунд. Повторное открытие заменяет код",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "OpenWindow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Отметка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/attendance.OpenWindowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/attendance.CodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/attendance/checkin/{window_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить текущий код открытой отметки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "GetCode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window ID",
+                        "name": "window_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/attendance.CodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Закрыть отметку досрочно",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "CloseWindow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window ID",
+                        "name": "window_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/attendance/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Экспорт посещаемости группы в CSV, по строке на студента и предмет",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-09-01",
+                        "description": "From",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-12-31",
+                        "description": "To",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/calendar.ics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Экспорт занятий и сессии в формате iCalendar, по умолчанию на 31 день начиная с сегодняшнего",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "GetCalendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-19",
+                        "description": "From",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-11-18",
+                        "description": "To",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/capacity": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменить вместимость группы, null снимает ограничение",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "SetCapacity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Вместимость",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/group.SetCapacityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/exams": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить расписание сессии",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "GetExamsByGroupId",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Term",
+                        "name": "term",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-12-20",
+                        "description": "From",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2027-01-31",
+                        "description": "To",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/group.ExamResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Загрузить расписание сессии, повторная загрузка семестра заменяет его расписание",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "UploadExams",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Расписание сессии",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/group.UploadExamsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/exams/{exam_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменить экзамен, консультацию или пересдачу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "UpdateExam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "exam_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Экзамен",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/group.UpdateExamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удалить экзамен, консультацию или пересдачу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "DeleteExam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "exam_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/homework": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить домашние задания группы за период, по умолчанию на ближайшую неделю",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homework"
+                ],
+                "summary": "GetAllByGroupId",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-19",
+                        "description": "From",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-25",
+                        "description": "To",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/homework.HomeworkResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Задать домашнее задание группе, при указании занятия срок сдачи должен совпадать с днём занятия",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homework"
+                ],
+                "summary": "Create",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Домашнее задание",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/homework.CreateHomeworkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/homework/{homework_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменить домашнее задание",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homework"
+                ],
+                "summary": "Update",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Homework ID",
+                        "name": "homework_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Домашнее задание",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/homework.UpdateHomeworkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удалить домашнее задание",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homework"
+                ],
+                "summary": "Delete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Homework ID",
+                        "name": "homework_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/homework/{homework_id}/done": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отметить домашнее задание выполненным",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homework"
+                ],
+                "summary": "MarkDone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Homework ID",
+                        "name": "homework_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снять отметку о выполнении домашнего задания",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homework"
+                ],
+                "summary": "UnmarkDone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Homework ID",
+                        "name": "homework_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/join": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Присоединиться к группе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "JoinToGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/schedule": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить расписание",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "GetScheduleByGroupId",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "true",
+                            "false"
+                        ],
+                        "type": "string",
+                        "description": "Even of week",
+                        "name": "week_even",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/group.DetailsScheduleResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Загрузить расписание",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "UploadSchedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Загрузить расписание",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/group.UploadScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{group_id}/waitlist": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Встать в лист ожидания заполненной группы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "JoinWaitlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/group.WaitlistPositionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "description": "Процесс запущен и обрабатывает запросы",
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/outbox/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получить неподтверждённые события для уведомлений",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outbox"
+                ],
+                "summary": "GetPending",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/outbox.EventResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/outbox/events/ack": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подтвердить доставку событий",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outbox"
+                ],
+                "summary": "Acknowledge",
+                "parameters": [
+                    {
+                        "description": "Подтвердить события",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/outbox.AcknowledgeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Проверка зависимостей: база данных и версия миграций",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/settings": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Обновление настроек студента",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "UpdateSettings",
+                "parameters": [
+                    {
+                        "description": "Update a user's account",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateUserSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.AssignFacultyRequest": {
+            "type": "object",
+            "required": [
+                "faculty_id"
+            ],
+            "properties": {
+                "demote": {
+                    "description": "Demote has to be set to limit a global administrator to the faculty",
+                    "type": "boolean"
+                },
+                "faculty_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "admin.FacultiesResponse": {
+            "type": "object",
+            "properties": {
+                "faculty_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "announcement.AnnouncementResponse": {
+            "type": "object",
+            "properties": {
+                "announcement_id": {
+                    "type": "integer"
+                },
+                "attachment_url": {
+                    "type": "string"
+                },
+                "author_id": {
+                    "type": "integer"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "pinned": {
+                    "type": "boolean"
+                },
+                "read": {
+                    "type": "boolean"
+                },
+                "read_count": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "announcement.AnnouncementsPageResponse": {
+            "type": "object",
+            "properties": {
+                "announcements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/announcement.AnnouncementResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "announcement.CreateAnnouncementRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "title"
+            ],
+            "properties": {
+                "attachment_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "body": {
+                    "type": "string",
+                    "maxLength": 4000
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "pinned": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "announcement.UpdateAnnouncementRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "title"
+            ],
+            "properties": {
+                "attachment_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "body": {
+                    "type": "string",
+                    "maxLength": 4000
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "pinned": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "attendance.CheckInRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "window_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "attendance.CheckInResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-19"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "subject_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "attendance.CodeResponse": {
+            "type": "object",
+            "properties": {
+                "closes_at": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "042917"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-19"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string",
+                    "example": "classflow://checkin?code=042917\u0026window_id=1"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "window_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "attendance.MarkLessonRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "marks",
+                "schedule_id"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "marks": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/attendance.MarkRequest"
+                    }
+                },
+                "schedule_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "attendance.MarkRequest": {
+            "type": "object",
+            "required": [
+                "status",
+                "user_id"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "present",
+                        "absent",
+                        "excused"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "attendance.OpenWindowRequest": {
+            "type": "object",
+            "required": [
+                "schedule_id"
+            ],
+            "properties": {
+                "minutes": {
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 1
+                },
+                "radius": {
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 10
+                },
+                "schedule_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "attendance.RecordResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-19"
+                },
+                "marked_by": {
+                    "type": "integer"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "manual"
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "08:30:00"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "present"
+                },
+                "subject_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "attendance.SheetEntryResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "present"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "audit.RecordResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "record_id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "integer"
+                },
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.LogInRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 40
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 8
+                }
+            }
+        },
+        "auth.LogInWithTelegramRequest": {
+            "type": "object",
+            "required": [
+                "telegram_chat_id"
+            ],
+            "properties": {
+                "telegram_chat_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "auth.SignUpRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 40
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 8
+                }
+            }
+        },
+        "auth.SignUpWithTelegramRequest": {
+            "type": "object",
+            "required": [
+                "full_name",
+                "telegram_chat_id",
+                "telegram_username"
+            ],
+            "properties": {
+                "full_name": {
+                    "type": "string",
+                    "maxLength": 40
+                },
+                "telegram_chat_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "telegram_username": {
+                    "type": "string",
+                    "maxLength": 40
+                }
+            }
+        },
+        "auth.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.UserDetailsResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "notification_delay": {
+                    "type": "integer"
+                },
+                "notifications_enabled": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "telegram": {
+                    "type": "integer"
+                },
+                "telegram_username": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "edu.BuildingResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "building_id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "edu.FacultyResponse": {
+            "type": "object",
+            "properties": {
+                "faculty_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "edu.ProgramResponse": {
+            "type": "object",
+            "properties": {
+                "faculty_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "program_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "edu.TypeOfSubjectResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "type_of_subject_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "group.CreateGroupRequest": {
+            "type": "object",
+            "required": [
+                "faculty_id",
+                "program_id",
+                "short_name"
+            ],
+            "properties": {
+                "capacity": {
                     "type": "integer",
                     "minimum": 1
                 },
-                "telegram_username": {
+                "faculty_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "program_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "short_name": {
                     "type": "string",
-                    "maxLength": 40
+                    "maxLength": 12,
+                    "minLength": 4
                 }
             }
         },
-        "auth.TokenResponse": {
+        "group.DaysRequest": {
+            "type": "object",
+            "required": [
+                "day_number",
+                "subjects"
+            ],
+            "properties": {
+                "day_number": {
+                    "type": "integer"
+                },
+                "subjects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.SubjectRequest"
+                    }
+                }
+            }
+        },
+        "group.DetailsGroupResponse": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "refresh_token": {
+                "exists_schedule": {
+                    "type": "boolean"
+                },
+                "faculty": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "leader_id": {
+                    "type": "integer"
+                },
+                "number_of_people": {
+                    "type": "integer"
+                },
+                "program": {
+                    "type": "string"
+                },
+                "short_name": {
                     "type": "string"
                 }
             }
         },
-        "auth.UserDetailsResponse": {
+        "group.DetailsScheduleResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "building": {
+                    "$ref": "#/definitions/edu.BuildingResponse"
+                },
+                "day_of_week": {
+                    "type": "integer"
+                },
+                "end_time": {
                     "type": "string"
                 },
-                "email": {
+                "every_week": {
+                    "type": "boolean"
+                },
+                "is_even": {
+                    "type": "boolean"
+                },
+                "room": {
                     "type": "string"
                 },
-                "full_name": {
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "start_time": {
                     "type": "string"
                 },
-                "notification_delay": {
+                "subject_name": {
+                    "type": "string"
+                },
+                "teacher": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "group.ExamRequest": {
+            "type": "object",
+            "required": [
+                "building_id",
+                "date",
+                "examiner",
+                "kind",
+                "room",
+                "start_time",
+                "subject_name"
+            ],
+            "properties": {
+                "building_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "date": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "examiner": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "exam",
+                        "consultation",
+                        "retake"
+                    ]
+                },
+                "room": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "subject_name": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "group.ExamResponse": {
+            "type": "object",
+            "properties": {
+                "building": {
+                    "$ref": "#/definitions/edu.BuildingResponse"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-19"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "exam_id": {
                     "type": "integer"
                 },
-                "notifications_enabled": {
+                "examiner": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "exam",
+                        "consultation",
+                        "retake"
+                    ]
+                },
+                "room": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "subject_name": {
+                    "type": "string"
+                },
+                "term": {
+                    "type": "string"
+                }
+            }
+        },
+        "group.SetCapacityRequest": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "Capacity is null to remove the limit",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "group.SubjectRequest": {
+            "type": "object",
+            "required": [
+                "building_id",
+                "end_time",
+                "name",
+                "room",
+                "start_time",
+                "teacher",
+                "type_id"
+            ],
+            "properties": {
+                "building_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "room": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "teacher": {
+                    "type": "string"
+                },
+                "type_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "group.SummaryGroupResponse": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "exists_schedule": {
                     "type": "boolean"
                 },
-                "role": {
+                "faculty": {
                     "type": "string"
                 },
-                "telegram": {
+                "group_id": {
                     "type": "integer"
                 },
-                "telegram_username": {
+                "number_of_people": {
+                    "type": "integer"
+                },
+                "program": {
+                    "type": "string"
+                },
+                "short_name": {
                     "type": "string"
+                }
+            }
+        },
+        "group.SummaryGroupsPageResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.SummaryGroupResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "edu.BuildingResponse": {
+        "group.UpdateExamRequest": {
             "type": "object",
+            "required": [
+                "building_id",
+                "date",
+                "examiner",
+                "kind",
+                "room",
+                "start_time",
+                "subject_name"
+            ],
             "properties": {
-                "address": {
+                "building_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "date": {
                     "type": "string"
                 },
-                "building_id": {
-                    "type": "integer"
+                "end_time": {
+                    "type": "string"
                 },
-                "latitude": {
-                    "type": "number"
+                "examiner": {
+                    "type": "string",
+                    "maxLength": 200
                 },
-                "longitude": {
-                    "type": "number"
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "exam",
+                        "consultation",
+                        "retake"
+                    ]
                 },
-                "name": {
+                "room": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "start_time": {
                     "type": "string"
+                },
+                "subject_name": {
+                    "type": "string",
+                    "maxLength": 200
                 }
             }
         },
-        "edu.FacultyResponse": {
+        "group.UploadExamsRequest": {
             "type": "object",
+            "required": [
+                "exams",
+                "term"
+            ],
             "properties": {
-                "faculty_id": {
-                    "type": "integer"
+                "exams": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/group.ExamRequest"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "term": {
+                    "description": "Term names the exam session, uploading the same term again replaces it",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "2026-winter"
                 }
             }
         },
-        "edu.ProgramResponse": {
+        "group.UploadScheduleRequest": {
             "type": "object",
+            "required": [
+                "weeks"
+            ],
             "properties": {
-                "faculty_id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "program_id": {
-                    "type": "integer"
+                "weeks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.WeekRequest"
+                    }
                 }
             }
         },
-        "edu.TypeOfSubjectResponse": {
+        "group.WaitlistPositionResponse": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "type_of_subject_id": {
+                "position": {
                     "type": "integer"
                 }
             }
         },
-        "group.CreateGroupRequest": {
+        "group.WeekRequest": {
             "type": "object",
             "required": [
-                "faculty_id",
-                "program_id",
-                "short_name"
+                "days"
             ],
             "properties": {
-                "faculty_id": {
-                    "type": "integer",
-                    "minimum": 1
-                },
-                "program_id": {
-                    "type": "integer",
-                    "minimum": 1
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.DaysRequest"
+                    }
                 },
-                "short_name": {
-                    "type": "string",
-                    "maxLength": 12,
-                    "minLength": 4
+                "is_even": {
+                    "type": "boolean"
                 }
             }
         },
-        "group.DaysRequest": {
+        "health.CheckResult": {
             "type": "object",
-            "required": [
-                "day_number",
-                "subjects"
-            ],
             "properties": {
-                "day_number": {
-                    "type": "integer"
+                "duration": {
+                    "type": "string"
                 },
-                "subjects": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/group.SubjectRequest"
-                    }
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "group.DetailsGroupResponse": {
+        "health.Report": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "exists_schedule": {
-                    "type": "boolean"
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
                 },
-                "faculty": {
+                "status": {
                     "type": "string"
+                }
+            }
+        },
+        "homework.AgendaDayResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-19"
                 },
-                "group_id": {
-                    "type": "integer"
-                },
-                "leader_id": {
-                    "type": "integer"
+                "exams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.ExamResponse"
+                    }
                 },
-                "number_of_people": {
-                    "type": "integer"
+                "homework": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/homework.HomeworkResponse"
+                    }
                 },
-                "program": {
-                    "type": "string"
+                "is_even": {
+                    "type": "boolean"
                 },
-                "short_name": {
-                    "type": "string"
+                "lessons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/homework.AgendaLessonResponse"
+                    }
                 }
             }
         },
-        "group.DetailsScheduleResponse": {
+        "homework.AgendaLessonResponse": {
             "type": "object",
             "properties": {
                 "building": {
@@ -1202,12 +4474,24 @@ const docTemplate = `{
                 "end_time": {
                     "type": "string"
                 },
+                "every_week": {
+                    "type": "boolean"
+                },
+                "homework": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/homework.HomeworkResponse"
+                    }
+                },
                 "is_even": {
                     "type": "boolean"
                 },
                 "room": {
                     "type": "string"
                 },
+                "schedule_id": {
+                    "type": "integer"
+                },
                 "start_time": {
                     "type": "string"
                 },
@@ -1222,102 +4506,165 @@ const docTemplate = `{
                 }
             }
         },
-        "group.SubjectRequest": {
+        "homework.CreateHomeworkRequest": {
             "type": "object",
             "required": [
-                "building_id",
-                "end_time",
-                "name",
-                "room",
-                "start_time",
-                "teacher",
-                "type_id"
+                "description",
+                "due_date"
             ],
             "properties": {
-                "building_id": {
-                    "type": "integer",
-                    "minimum": 1
-                },
-                "end_time": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "room": {
-                    "type": "string"
-                },
-                "start_time": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "maxLength": 4000
                 },
-                "teacher": {
+                "due_date": {
                     "type": "string"
                 },
-                "type_id": {
+                "schedule_id": {
                     "type": "integer",
                     "minimum": 1
+                },
+                "subject_name": {
+                    "type": "string",
+                    "maxLength": 200
                 }
             }
         },
-        "group.SummaryGroupResponse": {
+        "homework.HomeworkResponse": {
             "type": "object",
             "properties": {
-                "exists_schedule": {
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string"
                 },
-                "faculty": {
+                "created_by": {
+                    "type": "integer"
+                },
+                "description": {
                     "type": "string"
                 },
+                "done": {
+                    "type": "boolean"
+                },
+                "due_date": {
+                    "type": "string",
+                    "example": "2026-10-19"
+                },
                 "group_id": {
                     "type": "integer"
                 },
-                "number_of_people": {
+                "homework_id": {
                     "type": "integer"
                 },
-                "program": {
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "subject_name": {
                     "type": "string"
                 },
-                "short_name": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "group.UploadScheduleRequest": {
+        "homework.UpdateHomeworkRequest": {
             "type": "object",
             "required": [
-                "weeks"
+                "description",
+                "due_date"
             ],
             "properties": {
-                "weeks": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/group.WeekRequest"
-                    }
+                "description": {
+                    "type": "string",
+                    "maxLength": 4000
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "subject_name": {
+                    "type": "string",
+                    "maxLength": 200
                 }
             }
         },
-        "group.WeekRequest": {
+        "outbox.AcknowledgeRequest": {
             "type": "object",
             "required": [
-                "days",
-                "is_even"
+                "event_ids"
             ],
             "properties": {
-                "days": {
+                "event_ids": {
                     "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/group.DaysRequest"
+                        "type": "integer"
                     }
+                }
+            }
+        },
+        "outbox.EventResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "is_even": {
-                    "type": "boolean"
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
                 }
             }
         },
-        "response.APIError": {
+        "response.FieldError": {
             "type": "object",
             "properties": {
-                "error": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
@@ -0,0 +1,219 @@
+//go:build integration
+
+package api_test
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/auth"
+	"github.com/tclutin/classflow-api/internal/testutil"
+	"net/http"
+	"testing"
+)
+
+func TestAuth_Telegram(t *testing.T) {
+	server := newServer(t)
+
+	signup := map[string]any{
+		"telegram_chat_id":  42,
+		"telegram_username": "ivanov",
+		"full_name":         "Иванов Иван",
+	}
+
+	var tokens auth.TokenResponse
+	call(t, server, http.MethodPost, "/api/v1/auth/telegram/signup", "", signup, http.StatusCreated, &tokens)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   any
+		want   int
+		code   string
+	}{
+		{
+			name:   "sign up twice",
+			method: http.MethodPost,
+			path:   "/api/v1/auth/telegram/signup",
+			body:   signup,
+			want:   http.StatusConflict,
+			code:   "user_already_exists",
+		},
+		{
+			name:   "log in",
+			method: http.MethodPost,
+			path:   "/api/v1/auth/telegram/login",
+			body:   map[string]any{"telegram_chat_id": 42},
+			want:   http.StatusOK,
+		},
+		{
+			name:   "log in to an unknown chat",
+			method: http.MethodPost,
+			path:   "/api/v1/auth/telegram/login",
+			body:   map[string]any{"telegram_chat_id": 43},
+			want:   http.StatusNotFound,
+			code:   "user_not_found",
+		},
+		{
+			name:   "invalid body",
+			method: http.MethodPost,
+			path:   "/api/v1/auth/telegram/signup",
+			body:   map[string]any{"telegram_chat_id": 0},
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "who with the issued token",
+			method: http.MethodGet,
+			path:   "/api/v1/auth/who",
+			token:  tokens.AccessToken,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "who without a token",
+			method: http.MethodGet,
+			path:   "/api/v1/auth/who",
+			want:   http.StatusUnauthorized,
+			code:   "unauthorized",
+		},
+		{
+			name:   "who with a forged token",
+			method: http.MethodGet,
+			path:   "/api/v1/auth/who",
+			token:  tokens.AccessToken + "x",
+			want:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got problem
+			call(t, server, tt.method, tt.path, tt.token, tt.body, tt.want, &got)
+
+			if tt.code != "" && got.Code != tt.code {
+				t.Fatalf("got code %q, want %q", got.Code, tt.code)
+			}
+		})
+	}
+
+	var who auth.UserDetailsResponse
+	call(t, server, http.MethodGet, "/api/v1/auth/who", tokens.AccessToken, nil, http.StatusOK, &who)
+
+	if who.Role != "student" || who.TelegramChatID == nil || *who.TelegramChatID != 42 {
+		t.Fatalf("got %+v, want the signed up student", who)
+	}
+}
+
+func TestAuth_Email(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	admin, err := server.Fixtures.Admin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login := func(password string) map[string]any {
+		return map[string]any{"email": *admin.Email, "password": password}
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		body  any
+		want  int
+		code  string
+	}{
+		{
+			name: "log in",
+			path: "/api/v1/auth/login",
+			body: login(testutil.DefaultPassword),
+			want: http.StatusOK,
+		},
+		{
+			name: "wrong password",
+			path: "/api/v1/auth/login",
+			body: login("wrong password"),
+			want: http.StatusBadRequest,
+			code: "wrong_password",
+		},
+		{
+			name: "unknown email",
+			path: "/api/v1/auth/login",
+			body: map[string]any{"email": "nobody@classflow.test", "password": testutil.DefaultPassword},
+			want: http.StatusNotFound,
+			code: "user_not_found",
+		},
+		{
+			name:  "admin creates an admin",
+			token: token(t, server, admin.UserID),
+			path:  "/api/v1/auth/signup",
+			body:  map[string]any{"email": "second@classflow.test", "password": testutil.DefaultPassword},
+			want:  http.StatusCreated,
+		},
+		{
+			name:  "admin creates the same admin again",
+			token: token(t, server, admin.UserID),
+			path:  "/api/v1/auth/signup",
+			body:  map[string]any{"email": "second@classflow.test", "password": testutil.DefaultPassword},
+			want:  http.StatusConflict,
+			code:  "user_already_exists",
+		},
+		{
+			name: "anonymous creates an admin",
+			path: "/api/v1/auth/signup",
+			body: map[string]any{"email": "third@classflow.test", "password": testutil.DefaultPassword},
+			want: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got problem
+			call(t, server, http.MethodPost, tt.path, tt.token, tt.body, tt.want, &got)
+
+			if tt.code != "" && got.Code != tt.code {
+				t.Fatalf("got code %q, want %q", got.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestAuth_Lockout(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	admin, err := server.Fixtures.Admin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := map[string]any{"email": *admin.Email, "password": "wrong password"}
+
+	for attempt := 1; attempt <= server.Config.Lockout.MaxAttempts+1; attempt++ {
+		resp, err := server.Do(ctx, http.MethodPost, "/api/v1/auth/login", "", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if resp.Header.Get("Retry-After") == "" {
+				t.Fatal("locked response has no Retry-After")
+			}
+
+			// the right password does not help while the email is locked
+			call(t, server, http.MethodPost, "/api/v1/auth/login", "",
+				map[string]any{"email": *admin.Email, "password": testutil.DefaultPassword},
+				http.StatusTooManyRequests, nil)
+
+			return
+		}
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("attempt %d: got status %d, want %d", attempt, resp.StatusCode, http.StatusBadRequest)
+		}
+	}
+
+	t.Fatalf("email was not locked after %d failures", server.Config.Lockout.MaxAttempts+1)
+}
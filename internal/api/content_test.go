@@ -0,0 +1,254 @@
+//go:build integration
+
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/announcement"
+	groupHandler "github.com/tclutin/classflow-api/internal/api/http/v1/group"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/homework"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"net/http"
+	"testing"
+)
+
+func TestGroupContent(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	admin, err := server.Fixtures.Admin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leader, err := server.Fixtures.Student(ctx, func(u *user.User) { u.Role = user.Leader })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	member, err := server.Fixtures.Student(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outsider, err := server.Fixtures.Student(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := server.Fixtures.Group(ctx, func(g *group.Group) { g.LeaderID = &leader.UserID })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, userID := range []uint64{leader.UserID, member.UserID} {
+		if err = server.Fixtures.Member(ctx, userID, created.GroupID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buildings, err := server.Repositories.Edu.GetAllBuildings(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types, err := server.Repositories.Edu.GetAllTypesOfSubject(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminToken := token(t, server, admin.UserID)
+	leaderToken := token(t, server, leader.UserID)
+	memberToken := token(t, server, member.UserID)
+	outsiderToken := token(t, server, outsider.UserID)
+
+	groupPath := fmt.Sprintf("/api/v1/groups/%d", created.GroupID)
+
+	schedule := map[string]any{
+		"weeks": []map[string]any{{
+			"is_even": true,
+			"days": []map[string]any{{
+				"day_number": 1,
+				"subjects": []map[string]any{{
+					"name":        "Математический анализ",
+					"room":        "101",
+					"teacher":     "Иванов И. И.",
+					"type_id":     types[0].TypeOfSubjectID,
+					"building_id": buildings[0].BuildingID,
+					"start_time":  "09:00",
+					"end_time":    "10:30",
+				}},
+			}},
+		}},
+	}
+
+	announcementBody := map[string]any{"title": "Перенос пары", "body": "Пара переносится на среду", "pinned": true}
+	homeworkBody := map[string]any{"description": "№ 1-10", "due_date": "2026-10-19"}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   any
+		want   int
+		code   string
+	}{
+		{
+			name:   "leader cannot upload the schedule",
+			method: http.MethodPost,
+			path:   groupPath + "/schedule",
+			token:  leaderToken,
+			body:   schedule,
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "admin uploads the schedule",
+			method: http.MethodPost,
+			path:   groupPath + "/schedule",
+			token:  adminToken,
+			body:   schedule,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "schedule is uploaded once",
+			method: http.MethodPost,
+			path:   groupPath + "/schedule",
+			token:  adminToken,
+			body:   schedule,
+			want:   http.StatusConflict,
+			code:   "group_already_has_schedule",
+		},
+		{
+			name:   "leader posts an announcement",
+			method: http.MethodPost,
+			path:   groupPath + "/announcements",
+			token:  leaderToken,
+			body:   announcementBody,
+			want:   http.StatusCreated,
+		},
+		{
+			name:   "member cannot post an announcement",
+			method: http.MethodPost,
+			path:   groupPath + "/announcements",
+			token:  memberToken,
+			body:   announcementBody,
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "outsider cannot read announcements",
+			method: http.MethodGet,
+			path:   groupPath + "/announcements",
+			token:  outsiderToken,
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "leader assigns homework",
+			method: http.MethodPost,
+			path:   groupPath + "/homework",
+			token:  leaderToken,
+			body:   homeworkBody,
+			want:   http.StatusCreated,
+		},
+		{
+			name:   "homework needs a due date",
+			method: http.MethodPost,
+			path:   groupPath + "/homework",
+			token:  leaderToken,
+			body:   map[string]any{"description": "№ 1-10", "due_date": "19.10.2026"},
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "outsider cannot read homework",
+			method: http.MethodGet,
+			path:   groupPath + "/homework?from=2026-10-19&to=2026-10-25",
+			token:  outsiderToken,
+			want:   http.StatusForbidden,
+		},
+	}
+
+	// the steps share the group, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		var got problem
+		call(t, server, tt.method, tt.path, tt.token, tt.body, tt.want, &got)
+
+		if tt.code != "" && got.Code != tt.code {
+			t.Fatalf("%s: got code %q, want %q", tt.name, got.Code, tt.code)
+		}
+	}
+
+	var lessons []groupHandler.DetailsScheduleResponse
+	call(t, server, http.MethodGet, groupPath+"/schedule?week_even=true", memberToken, nil, http.StatusOK, &lessons)
+
+	if len(lessons) != 1 || lessons[0].SubjectName != "Математический анализ" {
+		t.Fatalf("got lessons %+v, want the uploaded one", lessons)
+	}
+
+	var board announcement.AnnouncementsPageResponse
+	call(t, server, http.MethodGet, groupPath+"/announcements", memberToken, nil, http.StatusOK, &board)
+
+	if board.Total != 1 || board.Announcements[0].Read {
+		t.Fatalf("got board %+v, want one unread announcement", board)
+	}
+
+	readPath := fmt.Sprintf("%s/announcements/%d/read", groupPath, board.Announcements[0].AnnouncementID)
+	call(t, server, http.MethodPost, readPath, memberToken, nil, http.StatusOK, nil)
+
+	call(t, server, http.MethodGet, groupPath+"/announcements", memberToken, nil, http.StatusOK, &board)
+
+	if !board.Announcements[0].Read || board.Announcements[0].ReadCount != 1 {
+		t.Fatalf("got %+v, want the announcement read once", board.Announcements[0])
+	}
+
+	var items []homework.HomeworkResponse
+	call(t, server, http.MethodGet, groupPath+"/homework?from=2026-10-19&to=2026-10-25", memberToken, nil, http.StatusOK, &items)
+
+	if len(items) != 1 || items[0].Done {
+		t.Fatalf("got homework %+v, want one not done", items)
+	}
+
+	donePath := fmt.Sprintf("%s/homework/%d/done", groupPath, items[0].HomeworkID)
+	call(t, server, http.MethodPost, donePath, memberToken, nil, http.StatusOK, nil)
+
+	call(t, server, http.MethodGet, groupPath+"/homework?from=2026-10-19&to=2026-10-25", memberToken, nil, http.StatusOK, &items)
+
+	if !items[0].Done {
+		t.Fatal("homework was not marked done")
+	}
+}
+
+func TestReferenceData(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	student, err := server.Fixtures.Student(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	faculties, err := server.Repositories.Edu.GetAllFaculty(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/api/v1/edu/faculties", want: http.StatusOK},
+		{path: fmt.Sprintf("/api/v1/edu/faculties/%d/programs", faculties[0].FacultyID), want: http.StatusOK},
+		{path: "/api/v1/edu/buildings", want: http.StatusOK},
+		{path: "/api/v1/edu/types_of_subject", want: http.StatusOK},
+		{path: "/readyz", want: http.StatusOK},
+		{path: "/livez", want: http.StatusOK},
+		{path: "/api/v1/unknown", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			call(t, server, http.MethodGet, tt.path, token(t, server, student.UserID), nil, tt.want, nil)
+		})
+	}
+}
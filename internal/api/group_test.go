@@ -0,0 +1,256 @@
+//go:build integration
+
+package api_test
+
+import (
+	"context"
+	"fmt"
+	groupHandler "github.com/tclutin/classflow-api/internal/api/http/v1/group"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"net/http"
+	"testing"
+)
+
+func TestGroups_Membership(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	admin, err := server.Fixtures.Admin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var students []string
+	for range 3 {
+		student, err := server.Fixtures.Student(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		students = append(students, token(t, server, student.UserID))
+	}
+
+	first, second, third := students[0], students[1], students[2]
+	adminToken := token(t, server, admin.UserID)
+
+	faculties, err := server.Repositories.Edu.GetAllFaculty(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	programs, err := server.Repositories.Edu.GetAllProgramsByFacultyId(ctx, faculties[0].FacultyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var created struct {
+		GroupID uint64 `json:"group_id"`
+	}
+
+	call(t, server, http.MethodPost, "/api/v1/groups", adminToken, map[string]any{
+		"faculty_id": faculties[0].FacultyID,
+		"program_id": programs[0].ProgramID,
+		"short_name": "PI-101",
+		"capacity":   1,
+	}, http.StatusCreated, &created)
+
+	groupPath := fmt.Sprintf("/api/v1/groups/%d", created.GroupID)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   any
+		want   int
+		code   string
+	}{
+		{
+			name:   "student cannot create a group",
+			method: http.MethodPost,
+			path:   "/api/v1/groups",
+			token:  first,
+			body:   map[string]any{"faculty_id": faculties[0].FacultyID, "program_id": programs[0].ProgramID, "short_name": "PI-102"},
+			want:   http.StatusForbidden,
+			code:   "forbidden",
+		},
+		{
+			name:   "duplicate short name",
+			method: http.MethodPost,
+			path:   "/api/v1/groups",
+			token:  adminToken,
+			body:   map[string]any{"faculty_id": faculties[0].FacultyID, "program_id": programs[0].ProgramID, "short_name": "PI-101"},
+			want:   http.StatusConflict,
+			code:   "group_already_exists",
+		},
+		{
+			name:   "no current group",
+			method: http.MethodGet,
+			path:   "/api/v1/groups/me",
+			token:  first,
+			want:   http.StatusNotFound,
+			code:   "member_not_found",
+		},
+		{
+			name:   "join",
+			method: http.MethodPost,
+			path:   groupPath + "/join",
+			token:  first,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "join twice",
+			method: http.MethodPost,
+			path:   groupPath + "/join",
+			token:  first,
+			want:   http.StatusConflict,
+			code:   "already_in_group",
+		},
+		{
+			name:   "join a full group",
+			method: http.MethodPost,
+			path:   groupPath + "/join",
+			token:  second,
+			want:   http.StatusConflict,
+			code:   "group_full",
+		},
+		{
+			name:   "wait for the full group",
+			method: http.MethodPost,
+			path:   groupPath + "/waitlist",
+			token:  second,
+			want:   http.StatusCreated,
+		},
+		{
+			name:   "wait twice",
+			method: http.MethodPost,
+			path:   groupPath + "/waitlist",
+			token:  second,
+			want:   http.StatusConflict,
+			code:   "already_in_waitlist",
+		},
+		{
+			name:   "wait behind",
+			method: http.MethodPost,
+			path:   groupPath + "/waitlist",
+			token:  third,
+			want:   http.StatusCreated,
+		},
+		{
+			name:   "leave",
+			method: http.MethodPost,
+			path:   "/api/v1/groups/leave",
+			token:  first,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "first in the waitlist got the place",
+			method: http.MethodGet,
+			path:   "/api/v1/groups/me",
+			token:  second,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "leave the waitlist",
+			method: http.MethodDelete,
+			path:   "/api/v1/groups/waitlist",
+			token:  third,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "leave without a group",
+			method: http.MethodPost,
+			path:   "/api/v1/groups/leave",
+			token:  first,
+			want:   http.StatusNotFound,
+			code:   "member_not_found",
+		},
+		{
+			name:   "unknown group",
+			method: http.MethodPost,
+			path:   "/api/v1/groups/1000000/join",
+			token:  first,
+			want:   http.StatusNotFound,
+			code:   "group_not_found",
+		},
+		{
+			name:   "student cannot change the capacity",
+			method: http.MethodPut,
+			path:   groupPath + "/capacity",
+			token:  first,
+			body:   map[string]any{"capacity": 10},
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "admin raises the capacity",
+			method: http.MethodPut,
+			path:   groupPath + "/capacity",
+			token:  adminToken,
+			body:   map[string]any{"capacity": 10},
+			want:   http.StatusOK,
+		},
+		{
+			name:   "join after the capacity was raised",
+			method: http.MethodPost,
+			path:   groupPath + "/join",
+			token:  first,
+			want:   http.StatusOK,
+		},
+	}
+
+	// the steps share the group, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		var got problem
+		call(t, server, tt.method, tt.path, tt.token, tt.body, tt.want, &got)
+
+		if tt.code != "" && got.Code != tt.code {
+			t.Fatalf("%s: got code %q, want %q", tt.name, got.Code, tt.code)
+		}
+	}
+
+	var current groupHandler.DetailsGroupResponse
+	call(t, server, http.MethodGet, "/api/v1/groups/me", second, nil, http.StatusOK, &current)
+
+	if current.GroupID != created.GroupID || current.NumberOfPeople != 2 || *current.Capacity != 10 {
+		t.Fatalf("got %+v, want two members of %d with capacity 10", current, created.GroupID)
+	}
+}
+
+func TestGroups_Summary(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	student, err := server.Fixtures.Student(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"PI-101", "PI-102", "PM-201"} {
+		if _, err = server.Fixtures.Group(ctx, func(g *group.Group) { g.ShortName = name }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  int
+		total int
+	}{
+		{query: "", want: 3, total: 3},
+		{query: "?search=pi-10", want: 2, total: 2},
+		{query: "?limit=1&offset=2", want: 1, total: 3},
+		{query: "?exists_schedule=true", want: 0, total: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var page groupHandler.SummaryGroupsPageResponse
+			call(t, server, http.MethodGet, "/api/v1/groups"+tt.query, token(t, server, student.UserID), nil, http.StatusOK, &page)
+
+			if len(page.Groups) != tt.want || page.Total != tt.total {
+				t.Fatalf("got %d groups of %d, want %d of %d", len(page.Groups), page.Total, tt.want, tt.total)
+			}
+		})
+	}
+
+	call(t, server, http.MethodGet, "/api/v1/groups?sort_by=members", token(t, server, student.UserID), nil, http.StatusBadRequest, nil)
+}
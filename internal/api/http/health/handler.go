@@ -0,0 +1,50 @@
+package health
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/classflow-api/pkg/health"
+	"net/http"
+)
+
+type Handler struct {
+	health *health.Health
+}
+
+func NewHandler(health *health.Health) *Handler {
+	return &Handler{
+		health: health,
+	}
+}
+
+func (h *Handler) Bind(router *gin.Engine) {
+	router.GET("/livez", h.Live)
+	router.GET("/readyz", h.Ready)
+	router.GET("/health", h.Ready)
+}
+
+// @Summary		Liveness probe
+// @Description	Процесс запущен и обрабатывает запросы
+// @Tags			health
+// @Success		200
+// @Router			/livez [get]
+func (h *Handler) Live(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+// @Summary		Readiness probe
+// @Description	Проверка зависимостей: база данных и версия миграций
+// @Tags			health
+// @Produce		json
+// @Success		200	{object}	health.Report
+// @Failure		503	{object}	health.Report
+// @Router			/readyz [get]
+func (h *Handler) Ready(c *gin.Context) {
+	report := h.health.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
@@ -0,0 +1,168 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	English = "en"
+	Russian = "ru"
+)
+
+// DefaultLanguage is used when Accept-Language is missing or lists no supported language
+const DefaultLanguage = English
+
+// messages holds translations by error code, English texts fall back to the domain error message
+var messages = map[string]map[string]string{
+	Russian: {
+		"internal_error":             "На сервере произошла ошибка. Попробуйте позже.",
+		"invalid_request":            "Некорректный запрос",
+		"validation_failed":          "Ошибка валидации запроса",
+		"route_not_found":            "Маршрут не найден",
+		"unauthorized":               "Требуется аутентификация",
+		"rate_limit_exceeded":        "Превышен лимит запросов, попробуйте позже",
+		"user_not_found":             "Пользователь не найден",
+		"user_already_exists":        "Пользователь уже существует",
+		"wrong_password":             "Неверный пароль",
+		"invalid_token":              "Токен недействителен или истёк",
+		"too_many_attempts":          "Слишком много неудачных попыток входа, попробуйте позже",
+		"program_not_found":          "Направление не найдено",
+		"faculty_not_found":          "Факультет не найден",
+		"type_of_subject_not_found":  "Тип предмета не найден",
+		"building_not_found":         "Корпус не найден",
+		"group_not_found":            "Группа не найдена",
+		"group_already_exists":       "Группа с таким названием уже существует",
+		"faculty_program_mismatch":   "Направление не относится к факультету",
+		"already_in_group":           "Вы уже состоите в группе",
+		"group_already_has_schedule": "У группы уже есть расписание",
+		"member_not_found":           "Участник не найден",
+		"exam_not_found":             "Экзамен не найден",
+		"group_full":                 "В группе нет свободных мест, встаньте в лист ожидания",
+		"group_not_full":             "В группе есть свободные места, присоединитесь к ней",
+		"capacity_below_members":     "Вместимость меньше текущего числа участников",
+		"already_in_waitlist":        "Вы уже стоите в листе ожидания",
+		"waitlist_entry_not_found":   "Запись в листе ожидания не найдена",
+		"announcement_not_found":     "Объявление не найдено",
+		"invalid_expiry":             "Срок действия должен быть в будущем",
+		"lesson_not_found":           "Занятие не найдено",
+		"invalid_date_range":         "Период должен начинаться раньше, чем заканчивается, и быть не длиннее 31 дня",
+		"homework_not_found":         "Домашнее задание не найдено",
+		"lesson_not_on_date":         "Занятие не проходит в указанный день",
+		"lesson_not_held":            "Посещаемость нельзя отметить до дня занятия",
+		"lesson_not_today":           "Отметку можно открыть только в день занятия",
+		"checkin_not_found":          "Отметка не найдена",
+		"checkin_closed":             "Отметка закрыта",
+		"invalid_checkin_code":       "Код отметки неверный или устарел",
+		"location_required":          "Для отметки нужна ваша геопозиция",
+		"checkin_too_far":            "Вы слишком далеко от корпуса, в котором проходит занятие",
+		"forbidden":                  "Недостаточно прав для доступа к ресурсу",
+		"not_admin":                  "Пользователь не является администратором",
+		"admin_demotion":             "Глобального администратора можно ограничить факультетом только с явным понижением",
+		"self_demotion":              "Нельзя понизить самого себя",
+	},
+}
+
+// fieldMessages holds templates by validation tag, %s is replaced with the tag parameter
+var fieldMessages = map[string]map[string]string{
+	English: {
+		"required": "is required",
+		"email":    "must be a valid email",
+		"min":      "must be at least %s",
+		"max":      "must be at most %s",
+		"gte":      "must be greater than or equal to %s",
+		"lte":      "must be less than or equal to %s",
+		"len":      "must have length %s",
+		"oneof":    "must be one of: %s",
+		"numeric":  "must be a positive integer",
+		"datetime": "must be a date in format %s",
+		"url":      "must be a valid URL",
+
+		"required_with": "is required together with %s",
+	},
+	Russian: {
+		"required": "обязательное поле",
+		"email":    "некорректный email",
+		"min":      "должно быть не меньше %s",
+		"max":      "должно быть не больше %s",
+		"gte":      "должно быть больше или равно %s",
+		"lte":      "должно быть меньше или равно %s",
+		"len":      "длина должна быть %s",
+		"oneof":    "допустимые значения: %s",
+		"numeric":  "должно быть положительным целым числом",
+		"datetime": "дата должна быть в формате %s",
+		"url":      "некорректная ссылка",
+
+		"required_with": "обязательно вместе с полем %s",
+
+		"weeks_length": "расписание должно содержать одну или две недели",
+		"days_length":  "неделя должна содержать от 1 до 7 дней",
+		"weeks_parity": "недели должны иметь разную чётность",
+
+		"end_before_start": "время окончания должно быть позже времени начала",
+	},
+}
+
+// Negotiate picks the supported language with the highest weight from an Accept-Language header
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang   string
+		weight float64
+	}
+
+	var candidates []candidate
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang != English && lang != Russian {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+
+		if weight > 0 {
+			candidates = append(candidates, candidate{lang: lang, weight: weight})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].weight > candidates[j].weight
+	})
+
+	return candidates[0].lang
+}
+
+func Message(lang, code, fallback string) string {
+	if message, ok := messages[lang][code]; ok {
+		return message
+	}
+
+	return fallback
+}
+
+func FieldMessage(lang, tag, param, fallback string) string {
+	template, ok := fieldMessages[lang][tag]
+	if !ok {
+		return fallback
+	}
+
+	if !strings.Contains(template, "%s") {
+		return template
+	}
+
+	return strings.ReplaceAll(template, "%s", param)
+}
@@ -1,92 +1,421 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/tclutin/classflow-api/internal/api/http/i18n"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
 	"github.com/tclutin/classflow-api/internal/domain/auth"
-	"github.com/tclutin/classflow-api/internal/domain/group"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/lockout"
 	"github.com/tclutin/classflow-api/internal/metric"
+	"github.com/tclutin/classflow-api/pkg/logger"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
 	"github.com/tclutin/classflow-api/pkg/response"
+	"math"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
+const requestIDHeader = "X-Request-ID"
+
+const (
+	DefaultPolicy  = "default"
+	AuthPolicy     = "auth"
+	SchedulePolicy = "schedule"
+	IPPolicy       = "ip"
+)
+
+// ErrorMiddleware renders the last error attached with c.Error, it must be registered before any other middleware.
+// Domain errors carry their own status and code, binding errors become validation errors, anything else is a 500
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		var lockedErr *lockout.LockedError
+		if errors.As(c.Errors.Last().Err, &lockedErr) {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(lockedErr.RetryAfter)))
+		}
+
+		err := toDomainError(c.Errors.Last())
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+
+		var fields []response.FieldError
+		for _, field := range err.Fields {
+			fields = append(fields, response.FieldError{
+				Field:   field.Field,
+				Code:    field.Code,
+				Message: i18n.FieldMessage(lang, field.Code, field.Param, field.Message),
+			})
+		}
+
+		problem := response.NewProblem(err.Status, err.Code, i18n.Message(lang, err.Code, err.Message), fields...)
+		problem.Instance = c.Request.URL.Path
+		problem.RequestID = logger.RequestIDFromContext(c.Request.Context())
+
+		body, marshalErr := json.Marshal(problem)
+		if marshalErr != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.Abort()
+		c.Data(err.Status, response.ProblemContentType, body)
+	}
+}
+
+// RequestIDMiddleware accepts the caller's X-Request-ID or generates a new one, echoes it back
+// and puts it into the context so every log record of the request carries it
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Header(requestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// LoggerMiddleware is gin's access log with the request ID appended, it must run after RequestIDMiddleware
+func LoggerMiddleware() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | %s\n%s",
+			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			param.StatusCode,
+			param.Latency,
+			param.ClientIP,
+			param.Method,
+			param.Path,
+			logger.RequestIDFromContext(param.Request.Context()),
+			param.ErrorMessage,
+		)
+	})
+}
+
+// RecoveryMiddleware turns a panic into an internal error so it is rendered as a problem by ErrorMiddleware
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, _ any) {
+		abortWithError(c, domainErr.ErrInternal)
+	})
+}
+
+// isValidRequestID rejects ids that are too long or could break log lines and headers
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > 128 {
+		return false
+	}
+
+	for _, r := range requestID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+func toDomainError(ginErr *gin.Error) *domainErr.Error {
+	var err *domainErr.Error
+	if errors.As(ginErr.Err, &err) {
+		return err
+	}
+
+	if !ginErr.IsType(gin.ErrorTypeBind) {
+		return domainErr.ErrInternal
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(ginErr.Err, &validationErrs) {
+		return domainErr.ErrInvalidRequest
+	}
+
+	fields := make([]domainErr.FieldError, 0, len(validationErrs))
+	for _, validationErr := range validationErrs {
+		fields = append(fields, domainErr.FieldError{
+			Field:   fieldPath(validationErr),
+			Code:    validationErr.Tag(),
+			Param:   validationErr.Param(),
+			Message: "is invalid",
+		})
+	}
+
+	return domainErr.ErrValidation.WithFields(fields...)
+}
+
+// fieldPath drops the root struct name from the namespace, e.g. UploadScheduleRequest.weeks[0].days -> weeks[0].days
+func fieldPath(err validator.FieldError) string {
+	_, path, ok := strings.Cut(err.Namespace(), ".")
+	if !ok {
+		return err.Field()
+	}
+
+	return path
+}
+
+// RegisterValidatorTagNames makes validation errors report the json or form name of a field instead of the Go one
+func RegisterValidatorTagNames() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+
+			if name != "" {
+				return name
+			}
+		}
+
+		return field.Name
+	})
+}
+
+// ParamUint parses a numeric path parameter, returning a validation error naming the parameter
+func ParamUint(c *gin.Context, name string) (uint64, error) {
+	value, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, domainErr.ErrValidation.WithFields(domainErr.FieldError{
+			Field:   name,
+			Code:    "numeric",
+			Message: "must be a positive integer",
+		})
+	}
+
+	return value, nil
+}
+
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// RequestMetadataMiddleware attaches the request id and client IP to the context for the audit log,
+// it must run after RequestIDMiddleware
+func RequestMetadataMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(audit.WithMetadata(c.Request.Context(), audit.Metadata{
+			RequestID: logger.RequestIDFromContext(c.Request.Context()),
+			IP:        c.ClientIP(),
+		}))
+		c.Next()
+	}
+}
+
 func JWTMiddleware(authService *auth.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("Authorization")
 		if token == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewAPIError("authorization header is required"))
+			abortWithError(c, domainErr.ErrUnauthorized)
 			return
 		}
 
 		parts := strings.Split(token, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewAPIError("authorization header must be in the format 'Bearer <token>'"))
+			abortWithError(c, domainErr.ErrInvalidToken)
 			return
 		}
 
 		user, err := authService.VerifyAndGetCredentials(c.Request.Context(), parts[1])
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewAPIError(err.Error()))
+			if errors.Is(err, domainErr.ErrUserNotFound) {
+				err = domainErr.ErrInvalidToken
+			}
+
+			abortWithError(c, err)
 			return
 		}
 
 		c.Set("userID", user.UserID)
 		c.Set("role", user.Role)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), user.UserID))
 		c.Next()
 	}
 }
 
-func RoleMiddleware(roles ...string) gin.HandlerFunc {
+// GetPrincipal returns the caller authenticated by JWTMiddleware
+func GetPrincipal(c *gin.Context) (access.Principal, bool) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		return access.Principal{}, false
+	}
+
+	role, ok := c.Get("role")
+	if !ok {
+		return access.Principal{}, false
+	}
+
+	extractRole, ok := role.(string)
+	if !ok {
+		return access.Principal{}, false
+	}
+
+	return access.Principal{UserID: userID.(uint64), Role: extractRole}, true
+}
+
+// PermissionMiddleware lets the request through if the caller holds the permission in any scope,
+// services narrow it down to the concrete resource using the grants it keeps in the request context
+func PermissionMiddleware(accessService *access.Service, permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, ok := c.Get("role")
+		principal, ok := GetPrincipal(c)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, response.NewAPIError("role not found in context"))
+			abortWithError(c, domainErr.ErrUnauthorized)
 			return
 		}
 
-		extractRole, ok := role.(string)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, response.NewAPIError("role type is invalid in context"))
+		grants, err := accessService.Load(c.Request.Context(), principal)
+		if err != nil {
+			abortWithError(c, err)
 			return
 		}
 
-		for _, r := range roles {
-			if extractRole == r {
-				c.Next()
-				return
-			}
+		c.Request = c.Request.WithContext(access.WithGrants(c.Request.Context(), grants))
+
+		allowed, err := accessService.HasPermission(c.Request.Context(), principal, permission)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		if !allowed {
+			abortWithError(c, domainErr.ErrForbidden)
+			return
 		}
 
-		c.AbortWithStatusJSON(http.StatusForbidden, response.NewAPIError("you do not have permission to access this resource"))
+		c.Next()
 	}
 }
 
-func CounterRequestMiddleware() gin.HandlerFunc {
+// MetricsMiddleware records request count, latency and in-flight requests, labeled by the route template
+// rather than the raw path to keep the number of series bounded
+func MetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		start := time.Now()
+
+		metric.IncRequestsInFlight()
+		defer metric.DecRequestsInFlight()
+
 		c.Next()
-		metric.IncRequestCounter(c.FullPath())
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metric.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
 	}
 }
 
-func ScheduleRequestCounterMiddleware(groupService *group.Service) gin.HandlerFunc {
+// ScheduleMetricsMiddleware counts successful schedule requests per group from the path parameter,
+// only served schedules are counted so unknown ids never become label values
+func ScheduleMetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		groupId := c.Param("group_id")
+		c.Next()
+
+		if c.Writer.Status() != http.StatusOK {
+			return
+		}
 
-		id, err := strconv.ParseUint(groupId, 10, 64)
+		groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
 		if err != nil {
+			return
+		}
+
+		metric.IncScheduleRequestCounter(groupID)
+	}
+}
+
+// RateLimitMiddleware must be placed after JWTMiddleware on protected routes to limit per user instead of per IP
+func RateLimitMiddleware(limiter *ratelimit.Limiter, policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
 			c.Next()
 			return
 		}
 
-		group, err := groupService.GetById(c.Request.Context(), id)
-		if err != nil {
+		var userID string
+		if value, ok := c.Get("userID"); ok {
+			userID = strconv.FormatUint(value.(uint64), 10)
+		}
+
+		principal := limiter.Resolve(c.GetHeader("X-API-Key"), userID, c.ClientIP())
+
+		result, err := limiter.Take(c.Request.Context(), policy, principal)
+		if err != nil || result.Limit == 0 {
 			c.Next()
 			return
 		}
 
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			metric.IncRateLimitRejectedCounter(policy, principal.Kind)
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			abortWithError(c, domainErr.ErrRateLimitExceeded)
+			return
+		}
+
 		c.Next()
+	}
+}
 
-		metric.IncScheduleRequestCounter(c.FullPath(), group.ShortName)
+// IPRateLimitMiddleware goes before JWTMiddleware and limits every client address, requests with a known
+// API key are left to RateLimitMiddleware
+func IPRateLimitMiddleware(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		principal := limiter.Resolve(c.GetHeader("X-API-Key"), "", c.ClientIP())
+		if principal.Kind != ratelimit.PrincipalAnonymous {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Take(c.Request.Context(), IPPolicy, principal)
+		if err != nil || result.Allowed {
+			c.Next()
+			return
+		}
+
+		metric.IncRateLimitRejectedCounter(IPPolicy, principal.Kind)
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		abortWithError(c, domainErr.ErrRateLimitExceeded)
 	}
 }
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/auth"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
+	"net/http"
+)
+
+type Service interface {
+	GetFacultyIdsByUserId(ctx context.Context, userID uint64) ([]uint64, error)
+	AssignFaculty(ctx context.Context, principal access.Principal, userID uint64, facultyID uint64, demote bool) error
+	RevokeFaculty(ctx context.Context, userID uint64, facultyID uint64) error
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	adminsGroup := router.Group("/admins",
+		middleware.IPRateLimitMiddleware(limiter),
+		middleware.JWTMiddleware(authService),
+		middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy),
+		middleware.PermissionMiddleware(accessService, access.AdminsManage))
+	{
+		adminsGroup.GET("/:user_id/faculties", h.GetFaculties)
+		adminsGroup.POST("/:user_id/faculties", h.AssignFaculty)
+		adminsGroup.DELETE("/:user_id/faculties/:faculty_id", h.RevokeFaculty)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetFaculties
+// @Description	Получить факультеты администратора
+// @Tags			admins
+// @Accept			json
+// @Produce		json
+// @Param			user_id	path		string	true	"User ID"
+// @Success		200		{object}	FacultiesResponse
+// @Failure		400		{object}	response.Problem
+// @Failure		404		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
+// @Router			/admins/{user_id}/faculties [get]
+func (h *Handler) GetFaculties(c *gin.Context) {
+	userID, err := middleware.ParamUint(c, "user_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	facultyIDs, err := h.service.GetFacultyIdsByUserId(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, FacultiesResponse{FacultyIDs: facultyIDs})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		AssignFaculty
+// @Description	Назначить администратора факультета, глобального администратора нужно явно понизить через demote
+// @Tags			admins
+// @Accept			json
+// @Produce		json
+// @Param			user_id	path		string					true	"User ID"
+// @Param			input	body		AssignFacultyRequest	true	"Назначить факультет"
+// @Success		200		{string}	string
+// @Failure		400		{object}	response.Problem
+// @Failure		404		{object}	response.Problem
+// @Failure		409		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
+// @Router			/admins/{user_id}/faculties [post]
+func (h *Handler) AssignFaculty(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	userID, err := middleware.ParamUint(c, "user_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request AssignFacultyRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err = h.service.AssignFaculty(c.Request.Context(), principal, userID, request.FacultyID, request.Demote); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		RevokeFaculty
+// @Description	Снять администратора с факультета, без последнего факультета он теряет роль администратора
+// @Tags			admins
+// @Accept			json
+// @Produce		json
+// @Param			user_id		path		string	true	"User ID"
+// @Param			faculty_id	path		string	true	"Faculty ID"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/admins/{user_id}/faculties/{faculty_id} [delete]
+func (h *Handler) RevokeFaculty(c *gin.Context) {
+	userID, err := middleware.ParamUint(c, "user_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	facultyID, err := middleware.ParamUint(c, "faculty_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.service.RevokeFaculty(c.Request.Context(), userID, facultyID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
@@ -0,0 +1,7 @@
+package admin
+
+type AssignFacultyRequest struct {
+	FacultyID uint64 `json:"faculty_id" binding:"required,gte=1"`
+	// Demote has to be set to limit a global administrator to the faculty
+	Demote bool `json:"demote"`
+}
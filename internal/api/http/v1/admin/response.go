@@ -0,0 +1,5 @@
+package admin
+
+type FacultiesResponse struct {
+	FacultyIDs []uint64 `json:"faculty_ids"`
+}
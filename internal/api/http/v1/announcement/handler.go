@@ -0,0 +1,262 @@
+package announcement
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"github.com/tclutin/classflow-api/internal/domain/auth"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
+	"net/http"
+)
+
+type Service interface {
+	Create(ctx context.Context, principal access.Principal, dto announcement.CreateAnnouncementDTO) (uint64, error)
+	Update(ctx context.Context, principal access.Principal, groupID, announcementID uint64, dto announcement.UpdateAnnouncementDTO) error
+	Delete(ctx context.Context, principal access.Principal, groupID, announcementID uint64) error
+	GetAllByGroupId(ctx context.Context, principal access.Principal, groupID uint64, filter announcement.FilterDTO) (announcement.AnnouncementsPageDTO, error)
+	MarkRead(ctx context.Context, principal access.Principal, groupID, announcementID uint64) error
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	announcementsGroup := router.Group("/groups/:group_id/announcements",
+		middleware.IPRateLimitMiddleware(limiter),
+		middleware.JWTMiddleware(authService),
+		middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy))
+	{
+		announcementsGroup.POST("", middleware.PermissionMiddleware(accessService, access.AnnouncementsWrite), h.Create)
+		announcementsGroup.GET("", h.GetAllByGroupId)
+		announcementsGroup.PUT("/:announcement_id", middleware.PermissionMiddleware(accessService, access.AnnouncementsWrite), h.Update)
+		announcementsGroup.DELETE("/:announcement_id", middleware.PermissionMiddleware(accessService, access.AnnouncementsWrite), h.Delete)
+		announcementsGroup.POST("/:announcement_id/read", h.MarkRead)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Create
+// @Description	Опубликовать объявление группы
+// @Tags			announcements
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string						true	"Group ID"
+// @Param			input		body		CreateAnnouncementRequest	true	"Новое объявление"
+// @Success		201			{integer}	integer						1
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/announcements [post]
+func (h *Handler) Create(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request CreateAnnouncementRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	announcementID, err := h.service.Create(c.Request.Context(), principal, request.ToDTO(groupID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"announcement_id": announcementID,
+	})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetAllByGroupId
+// @Description	Получить объявления группы, закреплённые идут первыми
+// @Tags			announcements
+// @Accept			json
+// @Produce		json
+// @Param			group_id		path		string	true	"Group ID"
+// @Param			include_expired	query		bool	false	"Include expired"
+// @Param			limit			query		int		false	"Limit"
+// @Param			offset			query		int		false	"Offset"
+// @Success		200				{object}	AnnouncementsPageResponse
+// @Failure		400				{object}	response.Problem
+// @Failure		403				{object}	response.Problem
+// @Failure		404				{object}	response.Problem
+// @Failure		500				{object}	response.Problem
+// @Router			/groups/{group_id}/announcements [get]
+func (h *Handler) GetAllByGroupId(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request FilterAnnouncementsRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	page, err := h.service.GetAllByGroupId(c.Request.Context(), principal, groupID, request.ToDTO())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PageToAnnouncementsPageResponse(page))
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Update
+// @Description	Изменить объявление группы
+// @Tags			announcements
+// @Accept			json
+// @Produce		json
+// @Param			group_id		path		string						true	"Group ID"
+// @Param			announcement_id	path		string						true	"Announcement ID"
+// @Param			input			body		UpdateAnnouncementRequest	true	"Объявление"
+// @Success		200				{string}	string
+// @Failure		400				{object}	response.Problem
+// @Failure		403				{object}	response.Problem
+// @Failure		404				{object}	response.Problem
+// @Failure		500				{object}	response.Problem
+// @Router			/groups/{group_id}/announcements/{announcement_id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, announcementID, err := params(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request UpdateAnnouncementRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err = h.service.Update(c.Request.Context(), principal, groupID, announcementID, request.ToDTO()); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Delete
+// @Description	Удалить объявление группы
+// @Tags			announcements
+// @Accept			json
+// @Produce		json
+// @Param			group_id		path		string	true	"Group ID"
+// @Param			announcement_id	path		string	true	"Announcement ID"
+// @Success		200				{string}	string
+// @Failure		400				{object}	response.Problem
+// @Failure		403				{object}	response.Problem
+// @Failure		404				{object}	response.Problem
+// @Failure		500				{object}	response.Problem
+// @Router			/groups/{group_id}/announcements/{announcement_id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, announcementID, err := params(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.service.Delete(c.Request.Context(), principal, groupID, announcementID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		MarkRead
+// @Description	Отметить объявление прочитанным
+// @Tags			announcements
+// @Accept			json
+// @Produce		json
+// @Param			group_id		path		string	true	"Group ID"
+// @Param			announcement_id	path		string	true	"Announcement ID"
+// @Success		200				{string}	string
+// @Failure		400				{object}	response.Problem
+// @Failure		403				{object}	response.Problem
+// @Failure		404				{object}	response.Problem
+// @Failure		500				{object}	response.Problem
+// @Router			/groups/{group_id}/announcements/{announcement_id}/read [post]
+func (h *Handler) MarkRead(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, announcementID, err := params(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.service.MarkRead(c.Request.Context(), principal, groupID, announcementID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+func params(c *gin.Context) (uint64, uint64, error) {
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	announcementID, err := middleware.ParamUint(c, "announcement_id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return groupID, announcementID, nil
+}
@@ -0,0 +1,57 @@
+package announcement
+
+import (
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"time"
+)
+
+type CreateAnnouncementRequest struct {
+	Title         string     `json:"title" binding:"required,max=200"`
+	Body          string     `json:"body" binding:"required,max=4000"`
+	AttachmentURL *string    `json:"attachment_url" binding:"omitempty,url,max=2048"`
+	Pinned        bool       `json:"pinned"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+func (r CreateAnnouncementRequest) ToDTO(groupID uint64) announcement.CreateAnnouncementDTO {
+	return announcement.CreateAnnouncementDTO{
+		GroupID:       groupID,
+		Title:         r.Title,
+		Body:          r.Body,
+		AttachmentURL: r.AttachmentURL,
+		Pinned:        r.Pinned,
+		ExpiresAt:     r.ExpiresAt,
+	}
+}
+
+type UpdateAnnouncementRequest struct {
+	Title         string     `json:"title" binding:"required,max=200"`
+	Body          string     `json:"body" binding:"required,max=4000"`
+	AttachmentURL *string    `json:"attachment_url" binding:"omitempty,url,max=2048"`
+	Pinned        bool       `json:"pinned"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+func (r UpdateAnnouncementRequest) ToDTO() announcement.UpdateAnnouncementDTO {
+	return announcement.UpdateAnnouncementDTO{
+		Title:         r.Title,
+		Body:          r.Body,
+		AttachmentURL: r.AttachmentURL,
+		Pinned:        r.Pinned,
+		ExpiresAt:     r.ExpiresAt,
+	}
+}
+
+type FilterAnnouncementsRequest struct {
+	IncludeExpired bool `form:"include_expired"`
+	Limit          int  `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset         int  `form:"offset" binding:"omitempty,min=0"`
+}
+
+func (f FilterAnnouncementsRequest) ToDTO() announcement.FilterDTO {
+	return announcement.FilterDTO{
+		IncludeExpired: f.IncludeExpired,
+		Limit:          f.Limit,
+		Offset:         f.Offset,
+	}
+}
@@ -0,0 +1,56 @@
+package announcement
+
+import (
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"time"
+)
+
+type AnnouncementResponse struct {
+	AnnouncementID uint64     `json:"announcement_id"`
+	GroupID        uint64     `json:"group_id"`
+	AuthorID       *uint64    `json:"author_id"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body"`
+	AttachmentURL  *string    `json:"attachment_url"`
+	Pinned         bool       `json:"pinned"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	ReadCount      int        `json:"read_count"`
+	Read           bool       `json:"read"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type AnnouncementsPageResponse struct {
+	Announcements []AnnouncementResponse `json:"announcements"`
+	Total         int                    `json:"total"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+}
+
+func PageToAnnouncementsPageResponse(page announcement.AnnouncementsPageDTO) AnnouncementsPageResponse {
+	announcements := []AnnouncementResponse{}
+
+	for _, entity := range page.Announcements {
+		announcements = append(announcements, AnnouncementResponse{
+			AnnouncementID: entity.AnnouncementID,
+			GroupID:        entity.GroupID,
+			AuthorID:       entity.AuthorID,
+			Title:          entity.Title,
+			Body:           entity.Body,
+			AttachmentURL:  entity.AttachmentURL,
+			Pinned:         entity.Pinned,
+			ExpiresAt:      entity.ExpiresAt,
+			ReadCount:      entity.ReadCount,
+			Read:           entity.Read,
+			CreatedAt:      entity.CreatedAt,
+			UpdatedAt:      entity.UpdatedAt,
+		})
+	}
+
+	return AnnouncementsPageResponse{
+		Announcements: announcements,
+		Total:         page.Total,
+		Limit:         page.Limit,
+		Offset:        page.Offset,
+	}
+}
@@ -0,0 +1,403 @@
+package attendance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"github.com/tclutin/classflow-api/internal/domain/auth"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
+	"net/http"
+	"time"
+)
+
+type Service interface {
+	Mark(ctx context.Context, principal access.Principal, dto attendance.MarkLessonDTO) error
+	GetSheet(ctx context.Context, principal access.Principal, groupID, scheduleID uint64, lessonDate time.Time) ([]attendance.SheetEntryDTO, error)
+	GetAllByUserId(ctx context.Context, userID uint64, filter attendance.FilterDTO) ([]attendance.DetailsRecordDTO, error)
+	GetSummary(ctx context.Context, principal access.Principal, groupID uint64, filter attendance.FilterDTO) ([]attendance.SummaryDTO, error)
+	OpenWindow(ctx context.Context, principal access.Principal, dto attendance.OpenWindowDTO) (attendance.CodeDTO, error)
+	GetCode(ctx context.Context, principal access.Principal, groupID, windowID uint64) (attendance.CodeDTO, error)
+	CloseWindow(ctx context.Context, principal access.Principal, groupID, windowID uint64) error
+	CheckIn(ctx context.Context, principal access.Principal, dto attendance.CheckInDTO) (attendance.CheckInResultDTO, error)
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	groupGroup := router.Group("/groups/:group_id/attendance",
+		middleware.IPRateLimitMiddleware(limiter),
+		middleware.JWTMiddleware(authService),
+		middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy))
+	{
+		groupGroup.GET("", middleware.PermissionMiddleware(accessService, access.AttendanceWrite), h.GetSheet)
+		groupGroup.PUT("", middleware.PermissionMiddleware(accessService, access.AttendanceWrite), h.Mark)
+		groupGroup.GET("/export", middleware.PermissionMiddleware(accessService, access.AttendanceExport), h.Export)
+		groupGroup.POST("/checkin", middleware.PermissionMiddleware(accessService, access.AttendanceWrite), h.OpenWindow)
+		groupGroup.GET("/checkin/:window_id", middleware.PermissionMiddleware(accessService, access.AttendanceWrite), h.GetCode)
+		groupGroup.DELETE("/checkin/:window_id", middleware.PermissionMiddleware(accessService, access.AttendanceWrite), h.CloseWindow)
+	}
+
+	attendanceGroup := router.Group("/attendance", middleware.IPRateLimitMiddleware(limiter), middleware.JWTMiddleware(authService))
+	{
+		attendanceGroup.GET("/me", middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy), h.GetMine)
+		// codes are short, submissions share the strict policy of the login endpoints
+		attendanceGroup.POST("/checkin", middleware.RateLimitMiddleware(limiter, middleware.AuthPolicy), h.CheckIn)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetSheet
+// @Description	Получить список группы с отметками посещаемости на занятии в указанный день
+// @Tags			attendance
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			schedule_id	query		string	true	"Schedule ID"
+// @Param			date		query		string	true	"Date"	example(2026-10-19)
+// @Success		200			{array}		SheetEntryResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/attendance [get]
+func (h *Handler) GetSheet(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request SheetRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	sheet, err := h.service.GetSheet(c.Request.Context(), principal, groupID, request.ScheduleID, request.LessonDate())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EntitiesToSheetResponse(sheet))
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Mark
+// @Description	Отметить посещаемость на прошедшем занятии, повторная отметка заменяет статус
+// @Tags			attendance
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string				true	"Group ID"
+// @Param			input		body		MarkLessonRequest	true	"Отметки"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/attendance [put]
+func (h *Handler) Mark(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request MarkLessonRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err = h.service.Mark(c.Request.Context(), principal, request.ToDTO(groupID)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Export
+// @Description	Экспорт посещаемости группы в CSV, по строке на студента и предмет
+// @Tags			attendance
+// @Produce		text/csv
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			from		query		string	false	"From"	example(2026-09-01)
+// @Param			to			query		string	false	"To"	example(2026-12-31)
+// @Param			subject		query		string	false	"Subject"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/attendance/export [get]
+func (h *Handler) Export(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request FilterRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	summary, err := h.service.GetSummary(c.Request.Context(), principal, groupID, request.ToDTO())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// the file is built before anything is sent, so a failure still gets an error response
+	var file bytes.Buffer
+
+	if err = WriteSummaryCSV(&file, summary); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="attendance-%d.csv"`, groupID))
+	c.Data(http.StatusOK, CSVContentType, file.Bytes())
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetMine
+// @Description	Получить свою посещаемость за период
+// @Tags			attendance
+// @Accept			json
+// @Produce		json
+// @Param			from		query		string	false	"From"	example(2026-09-01)
+// @Param			to			query		string	false	"To"	example(2026-12-31)
+// @Param			subject		query		string	false	"Subject"
+// @Success		200			{array}		RecordResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		401			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/attendance/me [get]
+func (h *Handler) GetMine(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	var request FilterRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	records, err := h.service.GetAllByUserId(c.Request.Context(), principal.UserID, request.ToDTO())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EntitiesToRecordsResponse(records))
+}
+
+// @Security		ApiKeyAuth
+// @Summary		OpenWindow
+// @Description	Открыть отметку на сегодняшнем занятии, студенты отмечаются кодом, который меняется каждые 30 секунд. Повторное открытие заменяет код
+// @Tags			attendance
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string				true	"Group ID"
+// @Param			input		body		OpenWindowRequest	true	"Отметка"
+// @Success		201			{object}	CodeResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/attendance/checkin [post]
+func (h *Handler) OpenWindow(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request OpenWindowRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	code, err := h.service.OpenWindow(c.Request.Context(), principal, request.ToDTO(groupID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, DTOToCodeResponse(code))
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetCode
+// @Description	Получить текущий код открытой отметки
+// @Tags			attendance
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			window_id	path		string	true	"Window ID"
+// @Success		200			{object}	CodeResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		409			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/attendance/checkin/{window_id} [get]
+func (h *Handler) GetCode(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, windowID, err := params(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	code, err := h.service.GetCode(c.Request.Context(), principal, groupID, windowID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, DTOToCodeResponse(code))
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CloseWindow
+// @Description	Закрыть отметку досрочно
+// @Tags			attendance
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			window_id	path		string	true	"Window ID"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		409			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/attendance/checkin/{window_id} [delete]
+func (h *Handler) CloseWindow(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, windowID, err := params(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.service.CloseWindow(c.Request.Context(), principal, groupID, windowID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CheckIn
+// @Description	Отметиться на занятии кодом из открытой отметки группы, геопозиция нужна, если отметка ограничена расстоянием до корпуса. Уже выставленная отметка не меняется
+// @Tags			attendance
+// @Accept			json
+// @Produce		json
+// @Param			input	body		CheckInRequest	true	"Код"
+// @Success		200		{object}	CheckInResponse
+// @Failure		400		{object}	response.Problem
+// @Failure		403		{object}	response.Problem
+// @Failure		404		{object}	response.Problem
+// @Failure		409		{object}	response.Problem
+// @Failure		429		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
+// @Router			/attendance/checkin [post]
+func (h *Handler) CheckIn(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	var request CheckInRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.service.CheckIn(c.Request.Context(), principal, request.ToDTO())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, DTOToCheckInResponse(result))
+}
+
+func params(c *gin.Context) (uint64, uint64, error) {
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	windowID, err := middleware.ParamUint(c, "window_id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return groupID, windowID, nil
+}
@@ -0,0 +1,107 @@
+package attendance
+
+import (
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"time"
+)
+
+// dateLayout is the format of lesson dates and periods, dates carry no time zone
+const dateLayout = time.DateOnly
+
+type SheetRequest struct {
+	ScheduleID uint64 `form:"schedule_id" binding:"required,gte=1"`
+	Date       string `form:"date" binding:"required,datetime=2006-01-02"`
+}
+
+func (r SheetRequest) LessonDate() time.Time {
+	lessonDate, _ := time.Parse(dateLayout, r.Date)
+	return lessonDate
+}
+
+type MarkRequest struct {
+	UserID uint64 `json:"user_id" binding:"required,gte=1"`
+	Status string `json:"status" binding:"required,oneof=present absent excused"`
+}
+
+type MarkLessonRequest struct {
+	ScheduleID uint64        `json:"schedule_id" binding:"required,gte=1"`
+	Date       string        `json:"date" binding:"required,datetime=2006-01-02"`
+	Marks      []MarkRequest `json:"marks" binding:"required,min=1,max=200,dive"`
+}
+
+func (r MarkLessonRequest) ToDTO(groupID uint64) attendance.MarkLessonDTO {
+	lessonDate, _ := time.Parse(dateLayout, r.Date)
+
+	marks := make([]attendance.MarkDTO, 0, len(r.Marks))
+	for _, mark := range r.Marks {
+		marks = append(marks, attendance.MarkDTO{
+			UserID: mark.UserID,
+			Status: mark.Status,
+		})
+	}
+
+	return attendance.MarkLessonDTO{
+		GroupID:    groupID,
+		ScheduleID: r.ScheduleID,
+		LessonDate: lessonDate,
+		Marks:      marks,
+	}
+}
+
+// FilterRequest is a range of lesson dates, both ends are optional
+type FilterRequest struct {
+	From    string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To      string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	Subject string `form:"subject" binding:"omitempty,max=200"`
+}
+
+func (f FilterRequest) ToDTO() attendance.FilterDTO {
+	filter := attendance.FilterDTO{
+		SubjectName: f.Subject,
+	}
+
+	if f.From != "" {
+		from, _ := time.Parse(dateLayout, f.From)
+		filter.From = &from
+	}
+
+	if f.To != "" {
+		to, _ := time.Parse(dateLayout, f.To)
+		filter.To = &to
+	}
+
+	return filter
+}
+
+// OpenWindowRequest opens check-in for Minutes, the lesson date is today
+type OpenWindowRequest struct {
+	ScheduleID uint64 `json:"schedule_id" binding:"required,gte=1"`
+	Minutes    int    `json:"minutes" binding:"omitempty,gte=1,lte=120"`
+	Radius     *int   `json:"radius" binding:"omitempty,gte=10,lte=5000"`
+}
+
+func (r OpenWindowRequest) ToDTO(groupID uint64) attendance.OpenWindowDTO {
+	return attendance.OpenWindowDTO{
+		GroupID:    groupID,
+		ScheduleID: r.ScheduleID,
+		LessonDate: time.Now(),
+		Duration:   time.Duration(r.Minutes) * time.Minute,
+		Radius:     r.Radius,
+	}
+}
+
+type CheckInRequest struct {
+	WindowID  *uint64  `json:"window_id" binding:"omitempty,gte=1"`
+	Code      string   `json:"code" binding:"required,len=6,numeric"`
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+}
+
+func (r CheckInRequest) ToDTO() attendance.CheckInDTO {
+	return attendance.CheckInDTO{
+		WindowID:  r.WindowID,
+		Code:      r.Code,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+	}
+}
@@ -0,0 +1,146 @@
+package attendance
+
+import (
+	"encoding/csv"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVContentType is the media type of the attendance export
+const CSVContentType = "text/csv; charset=utf-8"
+
+type SheetEntryResponse struct {
+	UserID   uint64  `json:"user_id"`
+	FullName *string `json:"full_name"`
+	Email    *string `json:"email"`
+	Status   *string `json:"status" example:"present"`
+}
+
+type RecordResponse struct {
+	ScheduleID  uint64  `json:"schedule_id"`
+	SubjectName string  `json:"subject_name"`
+	Date        string  `json:"date" example:"2026-10-19"`
+	StartTime   string  `json:"start_time" example:"08:30:00"`
+	Status      string  `json:"status" example:"present"`
+	Source      string  `json:"source" example:"manual"`
+	MarkedBy    *uint64 `json:"marked_by"`
+}
+
+type CodeResponse struct {
+	WindowID   uint64    `json:"window_id"`
+	ScheduleID uint64    `json:"schedule_id"`
+	Date       string    `json:"date" example:"2026-10-19"`
+	Code       string    `json:"code" example:"042917"`
+	Payload    string    `json:"payload" example:"classflow://checkin?code=042917&window_id=1"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	ClosesAt   time.Time `json:"closes_at"`
+}
+
+type CheckInResponse struct {
+	ScheduleID  uint64 `json:"schedule_id"`
+	SubjectName string `json:"subject_name"`
+	Date        string `json:"date" example:"2026-10-19"`
+}
+
+func EntitiesToSheetResponse(entities []attendance.SheetEntryDTO) []SheetEntryResponse {
+	sheet := []SheetEntryResponse{}
+
+	for _, entity := range entities {
+		sheet = append(sheet, SheetEntryResponse{
+			UserID:   entity.UserID,
+			FullName: entity.FullName,
+			Email:    entity.Email,
+			Status:   entity.Status,
+		})
+	}
+
+	return sheet
+}
+
+func EntitiesToRecordsResponse(entities []attendance.DetailsRecordDTO) []RecordResponse {
+	records := []RecordResponse{}
+
+	for _, entity := range entities {
+		records = append(records, RecordResponse{
+			ScheduleID:  entity.ScheduleID,
+			SubjectName: entity.SubjectName,
+			Date:        entity.LessonDate.Format(dateLayout),
+			StartTime:   entity.StartTime,
+			Status:      entity.Status,
+			Source:      entity.Source,
+			MarkedBy:    entity.MarkedBy,
+		})
+	}
+
+	return records
+}
+
+func DTOToCodeResponse(dto attendance.CodeDTO) CodeResponse {
+	return CodeResponse{
+		WindowID:   dto.WindowID,
+		ScheduleID: dto.ScheduleID,
+		Date:       dto.LessonDate.Format(dateLayout),
+		Code:       dto.Code,
+		Payload:    dto.Payload,
+		ExpiresAt:  dto.ExpiresAt,
+		ClosesAt:   dto.ClosesAt,
+	}
+}
+
+func DTOToCheckInResponse(dto attendance.CheckInResultDTO) CheckInResponse {
+	return CheckInResponse{
+		ScheduleID:  dto.ScheduleID,
+		SubjectName: dto.SubjectName,
+		Date:        dto.LessonDate.Format(dateLayout),
+	}
+}
+
+// WriteSummaryCSV writes one row per member and subject after a header row
+func WriteSummaryCSV(w io.Writer, summary []attendance.SummaryDTO) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"user_id", "full_name", "email", "subject_name", "present", "absent", "excused", "total"}); err != nil {
+		return err
+	}
+
+	for _, item := range summary {
+		err := writer.Write([]string{
+			strconv.FormatUint(item.UserID, 10),
+			csvCell(deref(item.FullName)),
+			csvCell(deref(item.Email)),
+			csvCell(item.SubjectName),
+			strconv.Itoa(item.Present),
+			strconv.Itoa(item.Absent),
+			strconv.Itoa(item.Excused),
+			strconv.Itoa(item.Total()),
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// csvCell prefixes user input that a spreadsheet would run as a formula with a quote
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
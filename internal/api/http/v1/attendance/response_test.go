@@ -0,0 +1,49 @@
+package attendance
+
+import (
+	"bytes"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"testing"
+)
+
+func TestWriteSummaryCSV(t *testing.T) {
+	name := func(value string) *string { return &value }
+
+	tests := []struct {
+		name    string
+		summary attendance.SummaryDTO
+		want    string
+	}{
+		{
+			name:    "plain cells",
+			summary: attendance.SummaryDTO{UserID: 1, FullName: name("Иванов"), SubjectName: "Алгебра", Present: 2, Absent: 1},
+			want:    "1,Иванов,,Алгебра,2,1,0,3\n",
+		},
+		{
+			name:    "formulas are quoted",
+			summary: attendance.SummaryDTO{UserID: 2, FullName: name("=HYPERLINK(1)"), Email: name("@evil"), SubjectName: "+1-2"},
+			want:    "2,'=HYPERLINK(1),'@evil,'+1-2,0,0,0,0\n",
+		},
+		{
+			name:    "leading tab and minus",
+			summary: attendance.SummaryDTO{UserID: 3, FullName: name("\tname"), SubjectName: "-x"},
+			want:    "3,'\tname,,'-x,0,0,0,0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var file bytes.Buffer
+
+			if err := WriteSummaryCSV(&file, []attendance.SummaryDTO{tt.summary}); err != nil {
+				t.Fatal(err)
+			}
+
+			want := "user_id,full_name,email,subject_name,present,absent,excused,total\n" + tt.want
+
+			if got := file.String(); got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
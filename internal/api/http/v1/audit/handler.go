@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/domain/auth"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
+	"net/http"
+)
+
+type Service interface {
+	GetAll(ctx context.Context, filter audit.FilterDTO) ([]audit.Record, error)
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	auditGroup := router.Group("/audit",
+		middleware.IPRateLimitMiddleware(limiter),
+		middleware.JWTMiddleware(authService),
+		middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy),
+		middleware.PermissionMiddleware(accessService, access.AuditRead))
+	{
+		auditGroup.GET("", h.GetAll)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetAll
+// @Description	Получить журнал аудита
+// @Tags			audit
+// @Accept			json
+// @Produce		json
+// @Param			actor_id	query		int		false	"Actor ID"
+// @Param			target_type	query		string	false	"Target type"	Enums(group, user, announcement, homework)
+// @Param			target_id	query		int		false	"Target ID"
+// @Param			from		query		string	false	"From, RFC 3339"
+// @Param			to			query		string	false	"To, RFC 3339"
+// @Param			limit		query		int		false	"Limit"
+// @Success		200			{array}		RecordResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/audit [get]
+func (h *Handler) GetAll(c *gin.Context) {
+	var request FilterRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	records, err := h.service.GetAll(c.Request.Context(), request.ToDTO())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EntitiesToRecordsResponse(records))
+}
@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"time"
+)
+
+type FilterRequest struct {
+	ActorID    *uint64    `form:"actor_id" binding:"omitempty,gte=1"`
+	TargetType string     `form:"target_type" binding:"omitempty,oneof=group user announcement homework"`
+	TargetID   *uint64    `form:"target_id" binding:"omitempty,gte=1"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int        `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+func (f FilterRequest) ToDTO() audit.FilterDTO {
+	return audit.FilterDTO{
+		ActorID:    f.ActorID,
+		TargetType: f.TargetType,
+		TargetID:   f.TargetID,
+		From:       f.From,
+		To:         f.To,
+		Limit:      f.Limit,
+	}
+}
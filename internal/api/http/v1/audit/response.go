@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"encoding/json"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"time"
+)
+
+type RecordResponse struct {
+	RecordID   uint64          `json:"record_id"`
+	ActorID    *uint64         `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   uint64          `json:"target_id"`
+	RequestID  *string         `json:"request_id"`
+	IP         *string         `json:"ip"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func EntitiesToRecordsResponse(entities []audit.Record) []RecordResponse {
+	var records []RecordResponse
+
+	for _, entity := range entities {
+		record := RecordResponse{
+			RecordID:   entity.RecordID,
+			ActorID:    entity.ActorID,
+			Action:     entity.Action,
+			TargetType: entity.TargetType,
+			TargetID:   entity.TargetID,
+			RequestID:  entity.RequestID,
+			IP:         entity.IP,
+			Before:     entity.Before,
+			After:      entity.After,
+			CreatedAt:  entity.CreatedAt,
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
@@ -2,13 +2,13 @@ package auth
 
 import (
 	"context"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
 	"github.com/tclutin/classflow-api/internal/domain/auth"
 	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
 	"github.com/tclutin/classflow-api/internal/domain/user"
-	"github.com/tclutin/classflow-api/pkg/response"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
 	"net/http"
 )
 
@@ -30,13 +30,13 @@ func NewHandler(service Service) *Handler {
 	}
 }
 
-func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service) {
-	authGroup := router.Group("/auth")
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	authGroup := router.Group("/auth", middleware.IPRateLimitMiddleware(limiter), middleware.RateLimitMiddleware(limiter, middleware.AuthPolicy))
 	{
-		authGroup.POST("/signup", middleware.JWTMiddleware(authService), middleware.RoleMiddleware(user.Admin), h.SignUp)
+		authGroup.POST("/signup", middleware.JWTMiddleware(authService), middleware.PermissionMiddleware(accessService, access.UsersCreate), h.SignUp)
 		authGroup.POST("/login", h.LogIn)
 		authGroup.POST("/telegram/login", h.LogInWithTelegram)
-		authGroup.POST("/telegram/signup", middleware.CounterRequestMiddleware(), h.SignUpWithTelegram)
+		authGroup.POST("/telegram/signup", h.SignUpWithTelegram)
 		authGroup.GET("/who", middleware.JWTMiddleware(authService), h.Who)
 	}
 }
@@ -48,15 +48,15 @@ func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service) {
 // @Produce		json
 // @Param			input	body		SignUpWithTelegramRequest	true	"Создать студента"
 // @Success		201		{object}	TokenResponse
-// @Failure		400		{object}	response.APIError
-// @Failure		409		{object}	response.APIError
-// @Failure		500		{object}	response.APIError
+// @Failure		400		{object}	response.Problem
+// @Failure		409		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
 // @Router			/auth/telegram/signup [post]
 func (h *Handler) SignUpWithTelegram(c *gin.Context) {
 	var request SignUpWithTelegramRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -67,12 +67,7 @@ func (h *Handler) SignUpWithTelegram(c *gin.Context) {
 	})
 
 	if err != nil {
-		if errors.Is(err, domainErr.ErrUserAlreadyExists) {
-			c.AbortWithStatusJSON(http.StatusConflict, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -89,28 +84,25 @@ func (h *Handler) SignUpWithTelegram(c *gin.Context) {
 // @Produce		json
 // @Param			input	body		LogInWithTelegramRequest	true	"Аутентификация студента"
 // @Success		200		{object}	TokenResponse
-// @Failure		400		{object}	response.APIError
-// @Failure		500		{object}	response.APIError
+// @Failure		400		{object}	response.Problem
+// @Failure		429		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
 // @Router			/auth/telegram/login [post]
 func (h *Handler) LogInWithTelegram(c *gin.Context) {
 	var request LogInWithTelegramRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	tokens, err := h.service.LogInWithTelegramRequest(c.Request.Context(), auth.LogInWithTelegramDTO{
 		TelegramChatID: request.TelegramChatID,
+		IP:             c.ClientIP(),
 	})
 
 	if err != nil {
-		if errors.Is(err, domainErr.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -128,15 +120,15 @@ func (h *Handler) LogInWithTelegram(c *gin.Context) {
 // @Produce		json
 // @Param			input	body		SignUpRequest	true	"Создать пользователя"
 // @Success		201		{object}	TokenResponse
-// @Failure		400		{object}	response.APIError
-// @Failure		409		{object}	response.APIError
-// @Failure		500		{object}	response.APIError
+// @Failure		400		{object}	response.Problem
+// @Failure		409		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
 // @Router			/auth/signup [post]
 func (h *Handler) SignUp(c *gin.Context) {
 	var request SignUpRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -146,13 +138,7 @@ func (h *Handler) SignUp(c *gin.Context) {
 	})
 
 	if err != nil {
-		if errors.Is(err, domainErr.ErrUserAlreadyExists) {
-			c.AbortWithStatusJSON(http.StatusConflict, response.NewAPIError(err.Error()))
-			return
-
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -169,35 +155,27 @@ func (h *Handler) SignUp(c *gin.Context) {
 // @Produce		json
 // @Param			input	body		LogInRequest	true	"Аутентификация пользователя"
 // @Success		200		{object}	TokenResponse
-// @Failure		400		{object}	response.APIError
-// @Failure		404		{object}	response.APIError
-// @Failure		500		{object}	response.APIError
+// @Failure		400		{object}	response.Problem
+// @Failure		404		{object}	response.Problem
+// @Failure		429		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
 // @Router			/auth/login [post]
 func (h *Handler) LogIn(c *gin.Context) {
 	var request LogInRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	tokens, err := h.service.LogIn(c.Request.Context(), auth.LogInDTO{
 		Email:    request.Email,
 		Password: request.Password,
+		IP:       c.ClientIP(),
 	})
 
 	if err != nil {
-		if errors.Is(err, domainErr.ErrWrongPassword) {
-			c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -214,24 +192,20 @@ func (h *Handler) LogIn(c *gin.Context) {
 // @Accept			json
 // @Produce		json
 // @Success		200	{object}	UserDetailsResponse
-// @Failure		401	{object}	response.APIError
-// @Failure		404	{object}	response.APIError
-// @Failure		500	{object}	response.APIError
+// @Failure		401	{object}	response.Problem
+// @Failure		404	{object}	response.Problem
+// @Failure		500	{object}	response.Problem
 // @Router			/auth/who [get]
 func (h *Handler) Who(c *gin.Context) {
 	value, ok := c.Get("userID")
 	if !ok {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewAPIError("userID not found in context"))
+		_ = c.Error(domainErr.ErrUnauthorized)
 		return
 	}
 
 	who, err := h.service.Who(c.Request.Context(), value.(uint64))
 	if err != nil {
-		if errors.Is(err, domainErr.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -6,9 +6,8 @@ import (
 	"github.com/tclutin/classflow-api/internal/api/http/middleware"
 	"github.com/tclutin/classflow-api/internal/domain/auth"
 	"github.com/tclutin/classflow-api/internal/domain/edu"
-	"github.com/tclutin/classflow-api/pkg/response"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
 	"net/http"
-	"strconv"
 )
 
 type Service interface {
@@ -26,8 +25,8 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service}
 }
 
-func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service) {
-	eduGroup := router.Group("/edu", middleware.JWTMiddleware(authService))
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, limiter *ratelimit.Limiter) {
+	eduGroup := router.Group("/edu", middleware.IPRateLimitMiddleware(limiter), middleware.JWTMiddleware(authService), middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy))
 	{
 		eduGroup.GET("/buildings", h.GetAllBuildings)
 		eduGroup.GET("/types_of_subject", h.GetAllTypesOfSubject)
@@ -43,12 +42,12 @@ func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service) {
 // @Accept			json
 // @Produce		json
 // @Success		200	{array}		BuildingResponse
-// @Failure		500	{object}	response.APIError
+// @Failure		500	{object}	response.Problem
 // @Router			/edu/buildings [get]
 func (h *Handler) GetAllBuildings(c *gin.Context) {
 	buildings, err := h.service.GetAllBuildings(c)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -62,12 +61,12 @@ func (h *Handler) GetAllBuildings(c *gin.Context) {
 // @Accept			json
 // @Produce		json
 // @Success		200	{array}		TypeOfSubjectResponse
-// @Failure		500	{object}	response.APIError
+// @Failure		500	{object}	response.Problem
 // @Router			/edu/types_of_subject [get]
 func (h *Handler) GetAllTypesOfSubject(c *gin.Context) {
 	types, err := h.service.GetAllTypesOfSubject(c)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -81,12 +80,12 @@ func (h *Handler) GetAllTypesOfSubject(c *gin.Context) {
 // @Accept			json
 // @Produce		json
 // @Success		200	{array}		FacultyResponse
-// @Failure		500	{object}	response.APIError
+// @Failure		500	{object}	response.Problem
 // @Router			/edu/faculties [get]
 func (h *Handler) GetAllFaculties(c *gin.Context) {
 	faculties, err := h.service.GetAllFaculties(c.Request.Context())
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -101,21 +100,19 @@ func (h *Handler) GetAllFaculties(c *gin.Context) {
 // @Produce		json
 // @Param			faculty_id	path		string	true	"faculty ID"
 // @Success		200			{array}		ProgramResponse
-// @Failure		400			{object}	response.APIError
-// @Failure		500			{object}	response.APIError
+// @Failure		400			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
 // @Router			/edu/faculties/{faculty_id}/programs [get]
 func (h *Handler) GetProgramsByFacultyId(c *gin.Context) {
-	facultyID := c.Param("faculty_id")
-
-	parseUint, err := strconv.ParseUint(facultyID, 10, 64)
+	facultyID, err := middleware.ParamUint(c, "faculty_id")
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
-	programs, err := h.service.GetAllProgramsByFacultyId(c.Request.Context(), parseUint)
+	programs, err := h.service.GetAllProgramsByFacultyId(c.Request.Context(), facultyID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -0,0 +1,76 @@
+package group
+
+import (
+	"fmt"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/pkg/ical"
+	"strings"
+	"time"
+)
+
+const calendarProdID = "-//classflow//schedule//RU"
+
+// DaysToCalendar turns every lesson occurrence and exam of the days into a calendar event
+func DaysToCalendar(groupID uint64, days []schedule.DayDTO) ical.Calendar {
+	calendar := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   fmt.Sprintf("classflow %d", groupID),
+	}
+
+	for _, day := range days {
+		for _, lesson := range day.Lessons {
+			start, err := schedule.At(day.Date, lesson.StartTime)
+			if err != nil {
+				continue
+			}
+
+			end, _ := schedule.At(day.Date, lesson.EndTime)
+
+			calendar.Events = append(calendar.Events, ical.Event{
+				UID:         fmt.Sprintf("lesson-%d-%s@classflow", lesson.ScheduleID, day.Date.Format("20060102")),
+				Start:       start,
+				End:         end,
+				Summary:     lesson.SubjectName,
+				Location:    location(lesson.Room, lesson.Building.Name, lesson.Building.Address),
+				Description: strings.TrimSpace(lesson.Type + "\n" + lesson.Teacher),
+				Categories:  []string{lesson.Type},
+			})
+		}
+
+		for _, exam := range day.Exams {
+			start, err := schedule.At(day.Date, exam.StartTime)
+			if err != nil {
+				continue
+			}
+
+			var end time.Time
+			if exam.EndTime != nil {
+				end, _ = schedule.At(day.Date, *exam.EndTime)
+			}
+
+			calendar.Events = append(calendar.Events, ical.Event{
+				UID:         fmt.Sprintf("exam-%d@classflow", exam.ExamID),
+				Start:       start,
+				End:         end,
+				Summary:     exam.SubjectName,
+				Location:    location(exam.Room, exam.Building.Name, exam.Building.Address),
+				Description: exam.Examiner,
+				Categories:  []string{exam.Kind},
+			})
+		}
+	}
+
+	return calendar
+}
+
+func location(parts ...string) string {
+	var nonEmpty []string
+
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+
+	return strings.Join(nonEmpty, ", ")
+}
@@ -2,28 +2,36 @@ package group
 
 import (
 	"context"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
 	"github.com/tclutin/classflow-api/internal/domain/auth"
 	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
 	"github.com/tclutin/classflow-api/internal/domain/group"
 	"github.com/tclutin/classflow-api/internal/domain/schedule"
-	"github.com/tclutin/classflow-api/internal/domain/user"
-	"github.com/tclutin/classflow-api/pkg/response"
+	"github.com/tclutin/classflow-api/pkg/ical"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
 	"net/http"
-	"strconv"
+	"time"
 )
 
 type Service interface {
-	Create(ctx context.Context, dto group.CreateGroupDTO) (uint64, error)
-	Delete(ctx context.Context, groupID uint64) error
-	GetAllGroupsSummary(ctx context.Context, filter group.FilterDTO) ([]group.SummaryGroupDTO, error)
+	Create(ctx context.Context, principal access.Principal, dto group.CreateGroupDTO) (uint64, error)
+	Delete(ctx context.Context, principal access.Principal, groupID uint64) error
+	GetAllGroupsSummary(ctx context.Context, principal access.Principal, filter group.FilterDTO) (group.SummaryGroupsPageDTO, error)
 	GetCurrentGroupByUserID(ctx context.Context, userID uint64) (group.DetailsGroupDTO, error)
 	JoinToGroup(ctx context.Context, userID, groupID uint64) error
 	LeaveFromGroup(ctx context.Context, userID uint64) error
-	UploadSchedule(ctx context.Context, schedule []schedule.Schedule, groupID uint64) error
+	SetCapacity(ctx context.Context, principal access.Principal, groupID uint64, capacity *int) error
+	JoinWaitlist(ctx context.Context, userID, groupID uint64) (int, error)
+	LeaveWaitlist(ctx context.Context, userID uint64) error
+	UploadSchedule(ctx context.Context, principal access.Principal, schedule []schedule.Schedule, groupID uint64) error
 	GetSchedulesByGroupId(ctx context.Context, filter schedule.FilterDTO, groupID uint64) ([]schedule.DetailsScheduleDTO, error)
+	UploadExams(ctx context.Context, principal access.Principal, groupID uint64, term string, exams []schedule.Exam) error
+	UpdateExam(ctx context.Context, principal access.Principal, exam schedule.Exam) error
+	DeleteExam(ctx context.Context, principal access.Principal, groupID, examID uint64) error
+	GetExamsByGroupId(ctx context.Context, filter schedule.ExamFilterDTO, groupID uint64) ([]schedule.DetailsExamDTO, error)
+	GetDaysByGroupId(ctx context.Context, groupID uint64, from, to time.Time) ([]schedule.DayDTO, error)
 }
 
 type Handler struct {
@@ -34,19 +42,36 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
-func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, groupService *group.Service) {
-	groupsGroup := router.Group("/groups", middleware.JWTMiddleware(authService))
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	groupsGroup := router.Group("/groups", middleware.IPRateLimitMiddleware(limiter), middleware.JWTMiddleware(authService))
+
+	// every route is charged to exactly one rate limit policy, schedule reads have their own larger one
+	defaultGroup := groupsGroup.Group("", middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy))
 	{
-		groupsGroup.POST("", middleware.RoleMiddleware(user.Admin), h.Create)
-		groupsGroup.DELETE("/:group_id", middleware.RoleMiddleware(user.Admin), h.Delete)
-		groupsGroup.GET("", h.GetAllGroupsSummary)
-		groupsGroup.GET("/me", middleware.RoleMiddleware(user.Student, user.Leader), h.GetCurrentGroup)
+		defaultGroup.POST("", middleware.PermissionMiddleware(accessService, access.GroupCreate), h.Create)
+		defaultGroup.DELETE("/:group_id", middleware.PermissionMiddleware(accessService, access.GroupDelete), h.Delete)
+		defaultGroup.GET("", h.GetAllGroupsSummary)
+		defaultGroup.GET("/me", middleware.PermissionMiddleware(accessService, access.MembershipRead), h.GetCurrentGroup)
+
+		defaultGroup.POST("/:group_id/join", middleware.PermissionMiddleware(accessService, access.GroupJoin), h.JoinToGroup)
+		defaultGroup.POST("/leave", middleware.PermissionMiddleware(accessService, access.GroupLeave), h.LeaveFromGroup)
+		defaultGroup.PUT("/:group_id/capacity", middleware.PermissionMiddleware(accessService, access.MembersManage), h.SetCapacity)
 
-		groupsGroup.POST("/:group_id/join", middleware.RoleMiddleware(user.Student), h.JoinToGroup)
-		groupsGroup.POST("/leave", middleware.RoleMiddleware(user.Student, user.Leader), h.LeaveFromGroup)
+		defaultGroup.POST("/:group_id/waitlist", middleware.PermissionMiddleware(accessService, access.GroupJoin), h.JoinWaitlist)
+		defaultGroup.DELETE("/waitlist", middleware.PermissionMiddleware(accessService, access.GroupJoin), h.LeaveWaitlist)
 
-		groupsGroup.POST("/:group_id/schedule", middleware.RoleMiddleware(user.Admin), h.UploadSchedule)
-		groupsGroup.GET("/:group_id/schedule", middleware.CounterRequestMiddleware(), middleware.ScheduleRequestCounterMiddleware(groupService), h.GetScheduleByGroupId)
+		defaultGroup.POST("/:group_id/schedule", middleware.PermissionMiddleware(accessService, access.ScheduleWrite), h.UploadSchedule)
+
+		defaultGroup.POST("/:group_id/exams", middleware.PermissionMiddleware(accessService, access.ScheduleWrite), h.UploadExams)
+		defaultGroup.PUT("/:group_id/exams/:exam_id", middleware.PermissionMiddleware(accessService, access.ScheduleWrite), h.UpdateExam)
+		defaultGroup.DELETE("/:group_id/exams/:exam_id", middleware.PermissionMiddleware(accessService, access.ScheduleWrite), h.DeleteExam)
+		defaultGroup.GET("/:group_id/exams", h.GetExamsByGroupId)
+	}
+
+	scheduleGroup := groupsGroup.Group("", middleware.RateLimitMiddleware(limiter, middleware.SchedulePolicy))
+	{
+		scheduleGroup.GET("/:group_id/schedule", middleware.ScheduleMetricsMiddleware(), h.GetScheduleByGroupId)
+		scheduleGroup.GET("/:group_id/calendar.ics", h.GetCalendar)
 	}
 }
 
@@ -58,47 +83,35 @@ func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, group
 // @Produce		json
 // @Param			input	body		CreateGroupRequest	true	"Create a new group"
 // @Success		201		{integer}	integer				1
-// @Failure		400		{object}	response.APIError
-// @Failure		409		{object}	response.APIError
-// @Failure		404		{object}	response.APIError
-// @Failure		500		{object}	response.APIError
+// @Failure		400		{object}	response.Problem
+// @Failure		403		{object}	response.Problem
+// @Failure		409		{object}	response.Problem
+// @Failure		404		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
 // @Router			/groups [post]
 func (h *Handler) Create(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
 	var request CreateGroupRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	groupID, err := h.service.Create(c.Request.Context(), group.CreateGroupDTO{
+	groupID, err := h.service.Create(c.Request.Context(), principal, group.CreateGroupDTO{
 		FacultyID: request.FacultyID,
 		ProgramID: request.ProgramID,
 		ShortName: request.ShortName,
+		Capacity:  request.Capacity,
 	})
 
 	if err != nil {
-		if errors.Is(err, domainErr.ErrGroupAlreadyExists) {
-			c.AbortWithStatusJSON(http.StatusConflict, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrFacultyProgramIdMismatch) {
-			c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrProgramNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrFacultyNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -115,28 +128,26 @@ func (h *Handler) Create(c *gin.Context) {
 // @Produce		json
 // @Param			group_id	path		string	true	"Group ID"
 // @Success		200			{string}	string
-// @Failure		400			{object}	response.APIError
-// @Failure		404			{object}	response.APIError
-// @Failure		500			{object}	response.APIError
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
 // @Router			/groups/{group_id} [delete]
 func (h *Handler) Delete(c *gin.Context) {
-	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
 		return
 	}
 
-	if err = h.service.Delete(c.Request.Context(), groupID); err != nil {
-		if errors.Is(err, domainErr.ErrGroupNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
 
-		if errors.Is(err, domainErr.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+	if err = h.service.Delete(c.Request.Context(), principal, groupID); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -145,31 +156,45 @@ func (h *Handler) Delete(c *gin.Context) {
 
 // @Security		ApiKeyAuth
 // @Summary		GetAllGroupsSummary
-// @Description	Получить список групп
+// @Description	Получить список групп с пагинацией, сортировкой и поиском
 // @Tags			groups
 // @Accept			json
 // @Produce		json
-// @Param			faculty	query		string	false	"Faculty name"
-// @Param			program	query		string	false	"Program name"
-// @Success		200		{array}		SummaryGroupResponse
-// @Failure		500		{object}	response.APIError
+// @Param			faculty			query		string	false	"Faculty name"
+// @Param			program			query		string	false	"Program name"
+// @Param			search			query		string	false	"Fuzzy search by short name"
+// @Param			faculty_id		query		[]int	false	"Faculty IDs"	collectionFormat(multi)
+// @Param			program_id		query		[]int	false	"Program IDs"	collectionFormat(multi)
+// @Param			exists_schedule	query		bool	false	"Exists schedule"
+// @Param			sort_by			query		string	false	"Sort by"	Enums(name, size, created_at)
+// @Param			order			query		string	false	"Order"		Enums(asc, desc)
+// @Param			limit			query		int		false	"Limit"
+// @Param			offset			query		int		false	"Offset"
+// @Success		200				{object}	SummaryGroupsPageResponse
+// @Failure		400				{object}	response.Problem
+// @Failure		500				{object}	response.Problem
 // @Router			/groups [get]
 func (h *Handler) GetAllGroupsSummary(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
 
-	program := c.DefaultQuery("program", "")
-	faculty := c.DefaultQuery("faculty", "")
+	var request FilterGroupsRequest
 
-	groups, err := h.service.GetAllGroupsSummary(c.Request.Context(), group.FilterDTO{
-		Faculty: faculty,
-		Program: program,
-	})
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
 
+	page, err := h.service.GetAllGroupsSummary(c.Request.Context(), principal, request.ToDTO())
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, EntitiesToSummaryGroupsResponse(groups))
+	c.JSON(http.StatusOK, PageToSummaryGroupsPageResponse(page))
 }
 
 // @Security		ApiKeyAuth
@@ -179,31 +204,21 @@ func (h *Handler) GetAllGroupsSummary(c *gin.Context) {
 // @Accept			json
 // @Produce		json
 // @Success		200	{object}	DetailsGroupResponse
-// @Failure		400	{object}	response.APIError
-// @Failure		401	{object}	response.APIError
-// @Failure		404	{object}	response.APIError
-// @Failure		500	{object}	response.APIError
+// @Failure		400	{object}	response.Problem
+// @Failure		401	{object}	response.Problem
+// @Failure		404	{object}	response.Problem
+// @Failure		500	{object}	response.Problem
 // @Router			/groups/me [get]
 func (h *Handler) GetCurrentGroup(c *gin.Context) {
 	value, ok := c.Get("userID")
 	if !ok {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewAPIError("userID not found in context"))
+		_ = c.Error(domainErr.ErrUnauthorized)
 		return
 	}
 
 	currentGroup, err := h.service.GetCurrentGroupByUserID(c.Request.Context(), value.(uint64))
 	if err != nil {
-		if errors.Is(err, domainErr.ErrGroupNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrMemberNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -217,34 +232,19 @@ func (h *Handler) GetCurrentGroup(c *gin.Context) {
 // @Accept			json
 // @Produce		json
 // @Success		200	{string}	string
-// @Failure		400	{object}	response.APIError
-// @Failure		404	{object}	response.APIError
-// @Failure		500	{object}	response.APIError
+// @Failure		400	{object}	response.Problem
+// @Failure		404	{object}	response.Problem
+// @Failure		500	{object}	response.Problem
 // @Router			/groups/leave [post]
 func (h *Handler) LeaveFromGroup(c *gin.Context) {
 	userID, ok := c.Get("userID")
 	if !ok {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError("userID not found in context"))
+		_ = c.Error(domainErr.ErrUnauthorized)
 		return
 	}
 
 	if err := h.service.LeaveFromGroup(c.Request.Context(), userID.(uint64)); err != nil {
-		if errors.Is(err, domainErr.ErrGroupNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrMemberNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -259,37 +259,131 @@ func (h *Handler) LeaveFromGroup(c *gin.Context) {
 // @Produce		json
 // @Param			group_id	path		string	true	"Group ID"
 // @Success		200			{string}	string
-// @Failure		400			{object}	response.APIError
-// @Failure		404			{object}	response.APIError
-// @Failure		409			{object}	response.APIError
-// @Failure		500			{object}	response.APIError
+// @Failure		400			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		409			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
 // @Router			/groups/{group_id}/join [post]
 func (h *Handler) JoinToGroup(c *gin.Context) {
-	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	groupID, err := middleware.ParamUint(c, "group_id")
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
 	userID, ok := c.Get("userID")
 	if !ok {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError("userID not found in context"))
+		_ = c.Error(domainErr.ErrUnauthorized)
 		return
 	}
 
 	err = h.service.JoinToGroup(c.Request.Context(), userID.(uint64), groupID)
 	if err != nil {
-		if errors.Is(err, domainErr.ErrGroupNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		SetCapacity
+// @Description	Изменить вместимость группы, null снимает ограничение
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string				true	"Group ID"
+// @Param			input		body		SetCapacityRequest	true	"Вместимость"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		409			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/capacity [put]
+func (h *Handler) SetCapacity(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	var request SetCapacityRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.service.SetCapacity(c.Request.Context(), principal, groupID, request.Capacity); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		JoinWaitlist
+// @Description	Встать в лист ожидания заполненной группы
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Success		201			{object}	WaitlistPositionResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		409			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/waitlist [post]
+func (h *Handler) JoinWaitlist(c *gin.Context) {
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	userID, ok := c.Get("userID")
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	position, err := h.service.JoinWaitlist(c.Request.Context(), userID.(uint64), groupID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
 
-		if errors.Is(err, domainErr.ErrAlreadyInGroup) {
-			c.AbortWithStatusJSON(http.StatusConflict, response.NewAPIError(err.Error()))
-			return
-		}
+	c.JSON(http.StatusCreated, WaitlistPositionResponse{Position: position})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		LeaveWaitlist
+// @Description	Покинуть лист ожидания
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Success		200	{string}	string
+// @Failure		400	{object}	response.Problem
+// @Failure		404	{object}	response.Problem
+// @Failure		500	{object}	response.Problem
+// @Router			/groups/waitlist [delete]
+func (h *Handler) LeaveWaitlist(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
 
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+	if err := h.service.LeaveWaitlist(c.Request.Context(), userID.(uint64)); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -305,57 +399,39 @@ func (h *Handler) JoinToGroup(c *gin.Context) {
 // @Param			group_id	path		string					true	"Group ID"
 // @Param			input		body		UploadScheduleRequest	true	"Загрузить расписание"
 // @Success		200			{string}	string
-// @Failure		400			{object}	response.APIError
-// @Failure		404			{object}	response.APIError
-// @Failure		409			{object}	response.APIError
-// @Failure		500			{object}	response.APIError
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		409			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
 // @Router			/groups/{group_id}/schedule [post]
 func (h *Handler) UploadSchedule(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
 	var request UploadScheduleRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	if err := request.Validate(); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
-	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	groupID, err := middleware.ParamUint(c, "group_id")
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
-	if err = h.service.UploadSchedule(c.Request.Context(), request.TransformToEntities(groupID), groupID); err != nil {
-		if errors.Is(err, domainErr.ErrGroupAlreadyHasSchedule) {
-			c.AbortWithStatusJSON(http.StatusConflict, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrTypeOfSubjectNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrFacultyNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrGroupNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		if errors.Is(err, domainErr.ErrProgramNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+	if err = h.service.UploadSchedule(c.Request.Context(), principal, request.TransformToEntities(groupID), groupID); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -371,30 +447,250 @@ func (h *Handler) UploadSchedule(c *gin.Context) {
 // @Param			group_id	path		string	true	"Group ID"
 // @Param			week_even	query		string	false	"Even of week"	Enums(true, false)
 // @Success		200			{array}		DetailsScheduleResponse
-// @Failure		400			{object}	response.APIError
-// @Failure		404			{object}	response.APIError
-// @Failure		500			{object}	response.APIError
+// @Failure		400			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
 // @Router			/groups/{group_id}/schedule [get]
 func (h *Handler) GetScheduleByGroupId(c *gin.Context) {
 
 	isEven := c.DefaultQuery("week_even", "")
 
-	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	groupID, err := middleware.ParamUint(c, "group_id")
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
 	schedules, err := h.service.GetSchedulesByGroupId(c.Request.Context(), schedule.FilterDTO{IsEven: isEven}, groupID)
 	if err != nil {
-		if errors.Is(err, domainErr.ErrGroupNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, EntitiesToSchedulesResponse(schedules))
 }
+
+// @Security		ApiKeyAuth
+// @Summary		UploadExams
+// @Description	Загрузить расписание сессии, повторная загрузка семестра заменяет его расписание
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string				true	"Group ID"
+// @Param			input		body		UploadExamsRequest	true	"Расписание сессии"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/exams [post]
+func (h *Handler) UploadExams(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	var request UploadExamsRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = h.service.UploadExams(c.Request.Context(), principal, groupID, request.Term, request.TransformToEntities(groupID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateExam
+// @Description	Изменить экзамен, консультацию или пересдачу
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string				true	"Group ID"
+// @Param			exam_id		path		string				true	"Exam ID"
+// @Param			input		body		UpdateExamRequest	true	"Экзамен"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/exams/{exam_id} [put]
+func (h *Handler) UpdateExam(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	var request UpdateExamRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	examID, err := middleware.ParamUint(c, "exam_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	exam := request.ToEntity(groupID, "")
+	exam.ExamID = examID
+
+	if err = h.service.UpdateExam(c.Request.Context(), principal, exam); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		DeleteExam
+// @Description	Удалить экзамен, консультацию или пересдачу
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			exam_id		path		string	true	"Exam ID"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/exams/{exam_id} [delete]
+func (h *Handler) DeleteExam(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	examID, err := middleware.ParamUint(c, "exam_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.service.DeleteExam(c.Request.Context(), principal, groupID, examID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetExamsByGroupId
+// @Description	Получить расписание сессии
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			term		query		string	false	"Term"
+// @Param			from		query		string	false	"From"	example(2026-12-20)
+// @Param			to			query		string	false	"To"	example(2027-01-31)
+// @Success		200			{array}		ExamResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/exams [get]
+func (h *Handler) GetExamsByGroupId(c *gin.Context) {
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request FilterExamsRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	exams, err := h.service.GetExamsByGroupId(c.Request.Context(), request.ToDTO(), groupID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EntitiesToExamsResponse(exams))
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetCalendar
+// @Description	Экспорт занятий и сессии в формате iCalendar, по умолчанию на 31 день начиная с сегодняшнего
+// @Tags			groups
+// @Produce		text/calendar
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			from		query		string	false	"From"	example(2026-10-19)
+// @Param			to			query		string	false	"To"	example(2026-11-18)
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/calendar.ics [get]
+func (h *Handler) GetCalendar(c *gin.Context) {
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request CalendarRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	from, to := request.Period()
+
+	days, err := h.service.GetDaysByGroupId(c.Request.Context(), groupID, from, to)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", ical.ContentType)
+	c.Status(http.StatusOK)
+
+	_ = DaysToCalendar(groupID, days).Encode(c.Writer, time.Now())
+}
@@ -1,7 +1,9 @@
 package group
 
 import (
-	"errors"
+	"fmt"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/group"
 	"github.com/tclutin/classflow-api/internal/domain/schedule"
 	"time"
 )
@@ -10,6 +12,40 @@ type CreateGroupRequest struct {
 	FacultyID uint64 `json:"faculty_id" binding:"required,gte=1"`
 	ProgramID uint64 `json:"program_id" binding:"required,gte=1"`
 	ShortName string `json:"short_name" binding:"required,min=4,max=12"`
+	Capacity  *int   `json:"capacity" binding:"omitempty,min=1"`
+}
+
+type SetCapacityRequest struct {
+	// Capacity is null to remove the limit
+	Capacity *int `json:"capacity" binding:"omitempty,min=1"`
+}
+
+type FilterGroupsRequest struct {
+	Faculty        string   `form:"faculty"`
+	Program        string   `form:"program"`
+	Search         string   `form:"search" binding:"omitempty,max=64"`
+	FacultyIDs     []uint64 `form:"faculty_id" binding:"omitempty,dive,gte=1"`
+	ProgramIDs     []uint64 `form:"program_id" binding:"omitempty,dive,gte=1"`
+	ExistsSchedule *bool    `form:"exists_schedule"`
+	SortBy         string   `form:"sort_by" binding:"omitempty,oneof=name size created_at"`
+	Order          string   `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit          int      `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset         int      `form:"offset" binding:"omitempty,min=0"`
+}
+
+func (f FilterGroupsRequest) ToDTO() group.FilterDTO {
+	return group.FilterDTO{
+		Faculty:        f.Faculty,
+		Program:        f.Program,
+		Search:         f.Search,
+		FacultyIDs:     f.FacultyIDs,
+		ProgramIDs:     f.ProgramIDs,
+		ExistsSchedule: f.ExistsSchedule,
+		SortBy:         f.SortBy,
+		Desc:           f.Order == "desc",
+		Limit:          f.Limit,
+		Offset:         f.Offset,
+	}
 }
 
 type SubjectRequest struct {
@@ -28,7 +64,7 @@ type DaysRequest struct {
 }
 
 type WeekRequest struct {
-	IsEven bool          `json:"is_even" binding:"required"`
+	IsEven bool          `json:"is_even"`
 	Days   []DaysRequest `json:"days" binding:"required"`
 }
 
@@ -39,36 +75,37 @@ type UploadScheduleRequest struct {
 // TODO: need to add validate of numbers of days
 func (u UploadScheduleRequest) Validate() error {
 	if len(u.Weeks) != 1 && len(u.Weeks) != 2 {
-		return errors.New("UploadScheduleRequest wrong length of weeks ")
+		return invalidSchedule("weeks", "weeks_length", "must contain one or two weeks")
 	}
 
-	if len(u.Weeks) == 1 {
-		if len(u.Weeks[0].Days) > 7 || len(u.Weeks[0].Days) < 1 {
-			return errors.New("UploadScheduleRequest wrong length of days ")
+	for i, week := range u.Weeks {
+		if len(week.Days) > 7 || len(week.Days) < 1 {
+			return invalidSchedule(fmt.Sprintf("weeks[%d].days", i), "days_length", "must contain from 1 to 7 days")
 		}
 	}
 
-	if len(u.Weeks) == 2 {
-		if len(u.Weeks[0].Days) > 7 || len(u.Weeks[1].Days) > 7 {
-			return errors.New("UploadScheduleRequest wrong length of days ")
-		}
-
-		if len(u.Weeks[1].Days) < 1 || len(u.Weeks[1].Days) < 1 {
-			return errors.New("UploadScheduleRequest wrong length of days ")
-		}
-
-		if u.Weeks[0].IsEven == u.Weeks[1].IsEven {
-			return errors.New("UploadScheduleRequest wrong even of weeks")
-		}
-
+	if len(u.Weeks) == 2 && u.Weeks[0].IsEven == u.Weeks[1].IsEven {
+		return invalidSchedule("weeks[1].is_even", "weeks_parity", "weeks must have different parity")
 	}
 
 	return nil
 }
 
+func invalidSchedule(field, code, message string) error {
+	return domainErr.ErrValidation.WithFields(domainErr.FieldError{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// TransformToEntities marks the lessons of a single-week schedule as taking place every week,
+// its is_even is kept but does not limit the weeks
 func (u UploadScheduleRequest) TransformToEntities(groupID uint64) []schedule.Schedule {
 	var schedules []schedule.Schedule
 
+	everyWeek := len(u.Weeks) == 1
+
 	for _, week := range u.Weeks {
 		for _, day := range week.Days {
 			for _, subject := range day.Subjects {
@@ -80,6 +117,7 @@ func (u UploadScheduleRequest) TransformToEntities(groupID uint64) []schedule.Sc
 					Teacher:         subject.Teacher,
 					Room:            subject.Room,
 					IsEven:          week.IsEven,
+					EveryWeek:       everyWeek,
 					DayOfWeek:       day.DayNumber,
 					StartTime:       subject.StartTime,
 					EndTime:         subject.EndTime,
@@ -91,3 +129,109 @@ func (u UploadScheduleRequest) TransformToEntities(groupID uint64) []schedule.Sc
 	}
 	return schedules
 }
+
+type ExamRequest struct {
+	Kind        string  `json:"kind" binding:"required,oneof=exam consultation retake"`
+	SubjectName string  `json:"subject_name" binding:"required,max=200"`
+	Examiner    string  `json:"examiner" binding:"required,max=200"`
+	Room        string  `json:"room" binding:"required,max=50"`
+	BuildingID  uint64  `json:"building_id" binding:"required,gte=1"`
+	Date        string  `json:"date" binding:"required,datetime=2006-01-02"`
+	StartTime   string  `json:"start_time" binding:"required,datetime=15:04"`
+	EndTime     *string `json:"end_time" binding:"omitempty,datetime=15:04"`
+}
+
+func (e ExamRequest) validate(field string) error {
+	if e.EndTime != nil && *e.EndTime <= e.StartTime {
+		return invalidSchedule(field+".end_time", "end_before_start", "must be after the start time")
+	}
+
+	return nil
+}
+
+func (e ExamRequest) ToEntity(groupID uint64, term string) schedule.Exam {
+	date, _ := time.Parse(time.DateOnly, e.Date)
+	now := time.Now()
+
+	return schedule.Exam{
+		GroupID:     groupID,
+		BuildingsID: e.BuildingID,
+		Term:        term,
+		Kind:        e.Kind,
+		SubjectName: e.SubjectName,
+		Examiner:    e.Examiner,
+		Room:        e.Room,
+		Date:        date,
+		StartTime:   e.StartTime,
+		EndTime:     e.EndTime,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+type UploadExamsRequest struct {
+	// Term names the exam session, uploading the same term again replaces it
+	Term  string        `json:"term" binding:"required,max=32" example:"2026-winter"`
+	Exams []ExamRequest `json:"exams" binding:"required,min=1,max=200,dive"`
+}
+
+func (u UploadExamsRequest) Validate() error {
+	for i, exam := range u.Exams {
+		if err := exam.validate(fmt.Sprintf("exams[%d]", i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u UploadExamsRequest) TransformToEntities(groupID uint64) []schedule.Exam {
+	var exams []schedule.Exam
+
+	for _, exam := range u.Exams {
+		exams = append(exams, exam.ToEntity(groupID, u.Term))
+	}
+
+	return exams
+}
+
+type UpdateExamRequest struct {
+	ExamRequest
+}
+
+func (u UpdateExamRequest) Validate() error {
+	return u.validate("exam")
+}
+
+type FilterExamsRequest struct {
+	Term string `form:"term" binding:"omitempty,max=32"`
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (f FilterExamsRequest) ToDTO() schedule.ExamFilterDTO {
+	filter := schedule.ExamFilterDTO{Term: f.Term}
+
+	if from, err := time.Parse(time.DateOnly, f.From); err == nil {
+		filter.From = &from
+	}
+
+	if to, err := time.Parse(time.DateOnly, f.To); err == nil {
+		filter.To = &to
+	}
+
+	return filter
+}
+
+// CalendarRequest is the period of the calendar export, it starts today and spans the longest range by default
+type CalendarRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (c CalendarRequest) Period() (time.Time, time.Time) {
+	from, _ := time.Parse(time.DateOnly, c.From)
+	to, _ := time.Parse(time.DateOnly, c.To)
+
+	return from, to
+}
@@ -8,12 +8,21 @@ import (
 )
 
 type SummaryGroupResponse struct {
-	GroupID        uint64 `json:"group_id"`
-	Faculty        string `json:"faculty"`
-	Program        string `json:"program"`
-	ShortName      string `json:"short_name"`
-	NumberOfPeople int    `json:"number_of_people"`
-	ExistsSchedule bool   `json:"exists_schedule"`
+	GroupID        uint64    `json:"group_id"`
+	Faculty        string    `json:"faculty"`
+	Program        string    `json:"program"`
+	ShortName      string    `json:"short_name"`
+	NumberOfPeople int       `json:"number_of_people"`
+	Capacity       *int      `json:"capacity"`
+	ExistsSchedule bool      `json:"exists_schedule"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type SummaryGroupsPageResponse struct {
+	Groups []SummaryGroupResponse `json:"groups"`
+	Total  int                    `json:"total"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
 }
 
 type DetailsGroupResponse struct {
@@ -23,22 +32,56 @@ type DetailsGroupResponse struct {
 	Program        string    `json:"program"`
 	ShortName      string    `json:"short_name"`
 	NumberOfPeople int       `json:"number_of_people"`
+	Capacity       *int      `json:"capacity"`
 	ExistsSchedule bool      `json:"exists_schedule"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+type WaitlistPositionResponse struct {
+	Position int `json:"position"`
+}
+
 type DetailsScheduleResponse struct {
+	ScheduleID  uint64               `json:"schedule_id"`
 	Type        string               `json:"type"`
 	SubjectName string               `json:"subject_name"`
 	Teacher     string               `json:"teacher"`
 	Room        string               `json:"room"`
 	IsEven      bool                 `json:"is_even"`
+	EveryWeek   bool                 `json:"every_week"`
 	DayOfWeek   int                  `json:"day_of_week"`
 	StartTime   string               `json:"start_time"`
 	EndTime     string               `json:"end_time"`
 	Building    edu.BuildingResponse `json:"building"`
 }
 
+type ExamResponse struct {
+	ExamID      uint64               `json:"exam_id"`
+	Term        string               `json:"term"`
+	Kind        string               `json:"kind" enums:"exam,consultation,retake"`
+	SubjectName string               `json:"subject_name"`
+	Examiner    string               `json:"examiner"`
+	Room        string               `json:"room"`
+	Date        string               `json:"date" example:"2026-10-19"`
+	StartTime   string               `json:"start_time"`
+	EndTime     *string              `json:"end_time"`
+	Building    edu.BuildingResponse `json:"building"`
+}
+
+func PageToSummaryGroupsPageResponse(page group.SummaryGroupsPageDTO) SummaryGroupsPageResponse {
+	groups := EntitiesToSummaryGroupsResponse(page.Groups)
+	if groups == nil {
+		groups = []SummaryGroupResponse{}
+	}
+
+	return SummaryGroupsPageResponse{
+		Groups: groups,
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+}
+
 func EntitiesToSummaryGroupsResponse(entities []group.SummaryGroupDTO) []SummaryGroupResponse {
 	var summaryGroupsResponse []SummaryGroupResponse
 	for _, entity := range entities {
@@ -48,7 +91,9 @@ func EntitiesToSummaryGroupsResponse(entities []group.SummaryGroupDTO) []Summary
 			Program:        entity.Program,
 			ShortName:      entity.ShortName,
 			NumberOfPeople: entity.NumberOfPeople,
+			Capacity:       entity.Capacity,
 			ExistsSchedule: entity.ExistsSchedule,
+			CreatedAt:      entity.CreatedAt,
 		}
 
 		summaryGroupsResponse = append(summaryGroupsResponse, summaryGroupResponse)
@@ -66,6 +111,7 @@ func EntityToDetailsGroupResponse(entity group.DetailsGroupDTO) DetailsGroupResp
 		Program:        entity.Program,
 		ShortName:      entity.ShortName,
 		NumberOfPeople: entity.NumberOfPeople,
+		Capacity:       entity.Capacity,
 		ExistsSchedule: entity.ExistsSchedule,
 		CreatedAt:      entity.CreatedAt,
 	}
@@ -76,26 +122,61 @@ func EntitiesToSchedulesResponse(entities []schedule.DetailsScheduleDTO) []Detai
 	var schedulesResponse []DetailsScheduleResponse
 
 	for _, entity := range entities {
-		scheduleResponse := DetailsScheduleResponse{
-			Type:        entity.Type,
-			SubjectName: entity.SubjectName,
-			Teacher:     entity.Teacher,
-			Room:        entity.Room,
-			IsEven:      entity.IsEven,
-			DayOfWeek:   entity.DayOfWeek,
-			StartTime:   entity.StartTime,
-			EndTime:     entity.EndTime,
-			Building: edu.BuildingResponse{
-				BuildingID: entity.Building.BuildingID,
-				Name:       entity.Building.Name,
-				Latitude:   entity.Building.Latitude,
-				Longitude:  entity.Building.Longitude,
-				Address:    entity.Building.Address,
-			},
-		}
-
-		schedulesResponse = append(schedulesResponse, scheduleResponse)
+		schedulesResponse = append(schedulesResponse, EntityToScheduleResponse(entity))
 	}
 
 	return schedulesResponse
 }
+
+func EntityToScheduleResponse(entity schedule.DetailsScheduleDTO) DetailsScheduleResponse {
+	return DetailsScheduleResponse{
+		ScheduleID:  entity.ScheduleID,
+		Type:        entity.Type,
+		SubjectName: entity.SubjectName,
+		Teacher:     entity.Teacher,
+		Room:        entity.Room,
+		IsEven:      entity.IsEven,
+		EveryWeek:   entity.EveryWeek,
+		DayOfWeek:   entity.DayOfWeek,
+		StartTime:   entity.StartTime,
+		EndTime:     entity.EndTime,
+		Building: edu.BuildingResponse{
+			BuildingID: entity.Building.BuildingID,
+			Name:       entity.Building.Name,
+			Latitude:   entity.Building.Latitude,
+			Longitude:  entity.Building.Longitude,
+			Address:    entity.Building.Address,
+		},
+	}
+}
+
+func EntitiesToExamsResponse(entities []schedule.DetailsExamDTO) []ExamResponse {
+	exams := []ExamResponse{}
+
+	for _, entity := range entities {
+		exams = append(exams, EntityToExamResponse(entity))
+	}
+
+	return exams
+}
+
+func EntityToExamResponse(entity schedule.DetailsExamDTO) ExamResponse {
+	return ExamResponse{
+		ExamID:      entity.ExamID,
+		Term:        entity.Term,
+		Kind:        entity.Kind,
+		SubjectName: entity.SubjectName,
+		Examiner:    entity.Examiner,
+		Room:        entity.Room,
+		Date:        entity.Date.Format(time.DateOnly),
+		StartTime:   entity.StartTime,
+		EndTime:     entity.EndTime,
+		Building: edu.BuildingResponse{
+			BuildingID: entity.Building.BuildingID,
+			Name:       entity.Building.Name,
+			Latitude:   entity.Building.Latitude,
+			Longitude:  entity.Building.Longitude,
+			Address:    entity.Building.Address,
+		},
+	}
+}
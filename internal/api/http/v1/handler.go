@@ -2,29 +2,44 @@ package v1
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/admin"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/announcement"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/attendance"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/audit"
 	"github.com/tclutin/classflow-api/internal/api/http/v1/auth"
 	"github.com/tclutin/classflow-api/internal/api/http/v1/edu"
 	"github.com/tclutin/classflow-api/internal/api/http/v1/group"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/homework"
+	"github.com/tclutin/classflow-api/internal/api/http/v1/outbox"
 	"github.com/tclutin/classflow-api/internal/api/http/v1/user"
 	"github.com/tclutin/classflow-api/internal/domain"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
 )
 
 type Handler struct {
 	services *domain.Services
+	limiter  *ratelimit.Limiter
 }
 
-func NewHandler(services *domain.Services) *Handler {
+func NewHandler(services *domain.Services, limiter *ratelimit.Limiter) *Handler {
 	return &Handler{
 		services: services,
+		limiter:  limiter,
 	}
 }
 
 func (h *Handler) InitAPI(router *gin.RouterGroup) {
 	apiGroup := router.Group("/v1")
 	{
-		user.NewHandler(h.services.User).Bind(apiGroup, h.services.Auth)
-		auth.NewHandler(h.services.Auth).Bind(apiGroup, h.services.Auth)
-		group.NewHandler(h.services.Group).Bind(apiGroup, h.services.Auth, h.services.Group)
-		edu.NewHandler(h.services.Edu).Bind(apiGroup, h.services.Auth)
+		user.NewHandler(h.services.User).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
+		auth.NewHandler(h.services.Auth).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
+		group.NewHandler(h.services.Group).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
+		edu.NewHandler(h.services.Edu).Bind(apiGroup, h.services.Auth, h.limiter)
+		admin.NewHandler(h.services.Access).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
+		audit.NewHandler(h.services.Audit).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
+		outbox.NewHandler(h.services.Outbox).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
+		announcement.NewHandler(h.services.Announcement).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
+		homework.NewHandler(h.services.Homework).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
+		attendance.NewHandler(h.services.Attendance).Bind(apiGroup, h.services.Auth, h.services.Access, h.limiter)
 	}
 }
@@ -0,0 +1,330 @@
+package homework
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/auth"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
+	"net/http"
+)
+
+type Service interface {
+	Create(ctx context.Context, principal access.Principal, dto homework.CreateHomeworkDTO) (uint64, error)
+	Update(ctx context.Context, principal access.Principal, groupID, homeworkID uint64, dto homework.UpdateHomeworkDTO) error
+	Delete(ctx context.Context, principal access.Principal, groupID, homeworkID uint64) error
+	GetAllByGroupId(ctx context.Context, principal access.Principal, groupID uint64, filter homework.FilterDTO) ([]homework.DetailsHomeworkDTO, error)
+	GetAgenda(ctx context.Context, principal access.Principal, groupID uint64, filter homework.FilterDTO) ([]homework.AgendaDayDTO, error)
+	SetDone(ctx context.Context, principal access.Principal, groupID, homeworkID uint64, done bool) error
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	groupGroup := router.Group("/groups/:group_id", middleware.IPRateLimitMiddleware(limiter), middleware.JWTMiddleware(authService))
+
+	homeworkGroup := groupGroup.Group("/homework", middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy))
+	{
+		homeworkGroup.POST("", middleware.PermissionMiddleware(accessService, access.HomeworkWrite), h.Create)
+		homeworkGroup.GET("", h.GetAllByGroupId)
+		homeworkGroup.PUT("/:homework_id", middleware.PermissionMiddleware(accessService, access.HomeworkWrite), h.Update)
+		homeworkGroup.DELETE("/:homework_id", middleware.PermissionMiddleware(accessService, access.HomeworkWrite), h.Delete)
+		homeworkGroup.POST("/:homework_id/done", h.MarkDone)
+		homeworkGroup.DELETE("/:homework_id/done", h.UnmarkDone)
+	}
+
+	groupGroup.GET("/agenda", middleware.RateLimitMiddleware(limiter, middleware.SchedulePolicy), h.GetAgenda)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Create
+// @Description	Задать домашнее задание группе, при указании занятия срок сдачи должен совпадать с днём занятия
+// @Tags			homework
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string					true	"Group ID"
+// @Param			input		body		CreateHomeworkRequest	true	"Домашнее задание"
+// @Success		201			{integer}	integer					1
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/homework [post]
+func (h *Handler) Create(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request CreateHomeworkRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	homeworkID, err := h.service.Create(c.Request.Context(), principal, request.ToDTO(groupID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"homework_id": homeworkID,
+	})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetAllByGroupId
+// @Description	Получить домашние задания группы за период, по умолчанию на ближайшую неделю
+// @Tags			homework
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			from		query		string	false	"From"	example(2026-10-19)
+// @Param			to			query		string	false	"To"	example(2026-10-25)
+// @Success		200			{array}		HomeworkResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/homework [get]
+func (h *Handler) GetAllByGroupId(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request PeriodRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	items, err := h.service.GetAllByGroupId(c.Request.Context(), principal, groupID, request.ToDTO())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EntitiesToHomeworkResponse(items))
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Update
+// @Description	Изменить домашнее задание
+// @Tags			homework
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string					true	"Group ID"
+// @Param			homework_id	path		string					true	"Homework ID"
+// @Param			input		body		UpdateHomeworkRequest	true	"Домашнее задание"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/homework/{homework_id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, homeworkID, err := params(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request UpdateHomeworkRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err = h.service.Update(c.Request.Context(), principal, groupID, homeworkID, request.ToDTO()); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Delete
+// @Description	Удалить домашнее задание
+// @Tags			homework
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			homework_id	path		string	true	"Homework ID"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/homework/{homework_id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, homeworkID, err := params(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.service.Delete(c.Request.Context(), principal, groupID, homeworkID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		MarkDone
+// @Description	Отметить домашнее задание выполненным
+// @Tags			homework
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			homework_id	path		string	true	"Homework ID"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/homework/{homework_id}/done [post]
+func (h *Handler) MarkDone(c *gin.Context) {
+	h.setDone(c, true)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UnmarkDone
+// @Description	Снять отметку о выполнении домашнего задания
+// @Tags			homework
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			homework_id	path		string	true	"Homework ID"
+// @Success		200			{string}	string
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/homework/{homework_id}/done [delete]
+func (h *Handler) UnmarkDone(c *gin.Context) {
+	h.setDone(c, false)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetAgenda
+// @Description	Получить расписание по датам вместе с сессией и сроками сдачи домашних заданий, по умолчанию на ближайшую неделю
+// @Tags			homework
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			from		query		string	false	"From"	example(2026-10-19)
+// @Param			to			query		string	false	"To"	example(2026-10-25)
+// @Success		200			{array}		AgendaDayResponse
+// @Failure		400			{object}	response.Problem
+// @Failure		403			{object}	response.Problem
+// @Failure		404			{object}	response.Problem
+// @Failure		500			{object}	response.Problem
+// @Router			/groups/{group_id}/agenda [get]
+func (h *Handler) GetAgenda(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request PeriodRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	days, err := h.service.GetAgenda(c.Request.Context(), principal, groupID, request.ToDTO())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, DaysToAgendaResponse(days))
+}
+
+func (h *Handler) setDone(c *gin.Context, done bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		_ = c.Error(domainErr.ErrUnauthorized)
+		return
+	}
+
+	groupID, homeworkID, err := params(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.service.SetDone(c.Request.Context(), principal, groupID, homeworkID, done); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+func params(c *gin.Context) (uint64, uint64, error) {
+	groupID, err := middleware.ParamUint(c, "group_id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	homeworkID, err := middleware.ParamUint(c, "homework_id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return groupID, homeworkID, nil
+}
@@ -0,0 +1,66 @@
+package homework
+
+import (
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"time"
+)
+
+// dateLayout is the format of due dates and periods, dates carry no time zone
+const dateLayout = time.DateOnly
+
+type CreateHomeworkRequest struct {
+	ScheduleID  *uint64 `json:"schedule_id" binding:"omitempty,gte=1"`
+	SubjectName *string `json:"subject_name" binding:"omitempty,max=200"`
+	Description string  `json:"description" binding:"required,max=4000"`
+	DueDate     string  `json:"due_date" binding:"required,datetime=2006-01-02"`
+}
+
+func (r CreateHomeworkRequest) ToDTO(groupID uint64) homework.CreateHomeworkDTO {
+	dueDate, _ := time.Parse(dateLayout, r.DueDate)
+
+	return homework.CreateHomeworkDTO{
+		GroupID:     groupID,
+		ScheduleID:  r.ScheduleID,
+		SubjectName: r.SubjectName,
+		Description: r.Description,
+		DueDate:     dueDate,
+	}
+}
+
+type UpdateHomeworkRequest struct {
+	ScheduleID  *uint64 `json:"schedule_id" binding:"omitempty,gte=1"`
+	SubjectName *string `json:"subject_name" binding:"omitempty,max=200"`
+	Description string  `json:"description" binding:"required,max=4000"`
+	DueDate     string  `json:"due_date" binding:"required,datetime=2006-01-02"`
+}
+
+func (r UpdateHomeworkRequest) ToDTO() homework.UpdateHomeworkDTO {
+	dueDate, _ := time.Parse(dateLayout, r.DueDate)
+
+	return homework.UpdateHomeworkDTO{
+		ScheduleID:  r.ScheduleID,
+		SubjectName: r.SubjectName,
+		Description: r.Description,
+		DueDate:     dueDate,
+	}
+}
+
+// PeriodRequest is a range of dates, the coming week is used when it is omitted
+type PeriodRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (p PeriodRequest) ToDTO() homework.FilterDTO {
+	var filter homework.FilterDTO
+
+	if p.From != "" {
+		filter.From, _ = time.Parse(dateLayout, p.From)
+	}
+
+	if p.To != "" {
+		filter.To, _ = time.Parse(dateLayout, p.To)
+	}
+
+	return filter
+}
@@ -0,0 +1,79 @@
+package homework
+
+import (
+	"github.com/tclutin/classflow-api/internal/api/http/v1/group"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"time"
+)
+
+type HomeworkResponse struct {
+	HomeworkID  uint64    `json:"homework_id"`
+	GroupID     uint64    `json:"group_id"`
+	ScheduleID  *uint64   `json:"schedule_id"`
+	SubjectName *string   `json:"subject_name"`
+	Description string    `json:"description"`
+	DueDate     string    `json:"due_date" example:"2026-10-19"`
+	CreatedBy   *uint64   `json:"created_by"`
+	Done        bool      `json:"done"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type AgendaLessonResponse struct {
+	group.DetailsScheduleResponse
+	Homework []HomeworkResponse `json:"homework"`
+}
+
+type AgendaDayResponse struct {
+	Date     string                 `json:"date" example:"2026-10-19"`
+	IsEven   bool                   `json:"is_even"`
+	Lessons  []AgendaLessonResponse `json:"lessons"`
+	Exams    []group.ExamResponse   `json:"exams"`
+	Homework []HomeworkResponse     `json:"homework"`
+}
+
+func EntitiesToHomeworkResponse(entities []homework.DetailsHomeworkDTO) []HomeworkResponse {
+	items := []HomeworkResponse{}
+
+	for _, entity := range entities {
+		items = append(items, HomeworkResponse{
+			HomeworkID:  entity.HomeworkID,
+			GroupID:     entity.GroupID,
+			ScheduleID:  entity.ScheduleID,
+			SubjectName: entity.SubjectName,
+			Description: entity.Description,
+			DueDate:     entity.DueDate.Format(dateLayout),
+			CreatedBy:   entity.CreatedBy,
+			Done:        entity.Done,
+			CreatedAt:   entity.CreatedAt,
+			UpdatedAt:   entity.UpdatedAt,
+		})
+	}
+
+	return items
+}
+
+func DaysToAgendaResponse(days []homework.AgendaDayDTO) []AgendaDayResponse {
+	agenda := []AgendaDayResponse{}
+
+	for _, day := range days {
+		lessons := []AgendaLessonResponse{}
+
+		for _, lesson := range day.Lessons {
+			lessons = append(lessons, AgendaLessonResponse{
+				DetailsScheduleResponse: group.EntityToScheduleResponse(lesson.DetailsScheduleDTO),
+				Homework:                EntitiesToHomeworkResponse(lesson.Homework),
+			})
+		}
+
+		agenda = append(agenda, AgendaDayResponse{
+			Date:     day.Date.Format(dateLayout),
+			IsEven:   day.IsEven,
+			Lessons:  lessons,
+			Exams:    group.EntitiesToExamsResponse(day.Exams),
+			Homework: EntitiesToHomeworkResponse(day.Homework),
+		})
+	}
+
+	return agenda
+}
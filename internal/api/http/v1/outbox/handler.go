@@ -0,0 +1,96 @@
+package outbox
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/auth"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
+	"net/http"
+)
+
+type Service interface {
+	GetPending(ctx context.Context, limit int) ([]outbox.Event, error)
+	Acknowledge(ctx context.Context, eventIDs []uint64) error
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	outboxGroup := router.Group("/outbox",
+		middleware.IPRateLimitMiddleware(limiter),
+		middleware.JWTMiddleware(authService),
+		middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy),
+		middleware.PermissionMiddleware(accessService, access.OutboxConsume))
+	{
+		outboxGroup.GET("/events", h.GetPending)
+		outboxGroup.POST("/events/ack", h.Acknowledge)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetPending
+// @Description	Получить неподтверждённые события для уведомлений
+// @Tags			outbox
+// @Accept			json
+// @Produce		json
+// @Param			limit	query		int	false	"Limit"
+// @Success		200		{array}		EventResponse
+// @Failure		400		{object}	response.Problem
+// @Failure		403		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
+// @Router			/outbox/events [get]
+func (h *Handler) GetPending(c *gin.Context) {
+	var request PendingEventsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	events, err := h.service.GetPending(c.Request.Context(), request.Limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EntitiesToEventsResponse(events))
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Acknowledge
+// @Description	Подтвердить доставку событий
+// @Tags			outbox
+// @Accept			json
+// @Produce		json
+// @Param			input	body		AcknowledgeRequest	true	"Подтвердить события"
+// @Success		200		{string}	string
+// @Failure		400		{object}	response.Problem
+// @Failure		403		{object}	response.Problem
+// @Failure		500		{object}	response.Problem
+// @Router			/outbox/events/ack [post]
+func (h *Handler) Acknowledge(c *gin.Context) {
+	var request AcknowledgeRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.service.Acknowledge(c.Request.Context(), request.EventIDs); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
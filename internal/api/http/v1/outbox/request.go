@@ -0,0 +1,9 @@
+package outbox
+
+type PendingEventsRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type AcknowledgeRequest struct {
+	EventIDs []uint64 `json:"event_ids" binding:"required,min=1,max=500,dive,gte=1"`
+}
@@ -0,0 +1,31 @@
+package outbox
+
+import (
+	"encoding/json"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
+	"time"
+)
+
+type EventResponse struct {
+	EventID   uint64          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func EntitiesToEventsResponse(entities []outbox.Event) []EventResponse {
+	events := []EventResponse{}
+
+	for _, entity := range entities {
+		event := EventResponse{
+			EventID:   entity.EventID,
+			EventType: entity.EventType,
+			Payload:   entity.Payload,
+			CreatedAt: entity.CreatedAt,
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
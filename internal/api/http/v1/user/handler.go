@@ -2,13 +2,13 @@ package user
 
 import (
 	"context"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/tclutin/classflow-api/internal/api/http/middleware"
+	"github.com/tclutin/classflow-api/internal/domain/access"
 	"github.com/tclutin/classflow-api/internal/domain/auth"
 	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
 	"github.com/tclutin/classflow-api/internal/domain/user"
-	"github.com/tclutin/classflow-api/pkg/response"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
 	"net/http"
 )
 
@@ -26,10 +26,10 @@ func NewHandler(service Service) *Handler {
 	}
 }
 
-func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service) {
-	userGroup := router.Group("/users", middleware.JWTMiddleware(authService), middleware.RoleMiddleware(user.Student, user.Leader))
+func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service, accessService *access.Service, limiter *ratelimit.Limiter) {
+	userGroup := router.Group("/users", middleware.IPRateLimitMiddleware(limiter), middleware.JWTMiddleware(authService), middleware.RateLimitMiddleware(limiter, middleware.DefaultPolicy))
 	{
-		userGroup.PATCH("/settings", h.UpdateSettings)
+		userGroup.PATCH("/settings", middleware.PermissionMiddleware(accessService, access.SettingsWrite), h.UpdateSettings)
 	}
 }
 
@@ -41,21 +41,21 @@ func (h *Handler) Bind(router *gin.RouterGroup, authService *auth.Service) {
 // @Produce		json
 // @Param			input	body		UpdateUserSettingsRequest	false	"Update a user's account"
 // @Success		200			{string}	string
-// @Failure		401	{object}	response.APIError
-// @Failure		404	{object}	response.APIError
-// @Failure		500	{object}	response.APIError
+// @Failure		401	{object}	response.Problem
+// @Failure		404	{object}	response.Problem
+// @Failure		500	{object}	response.Problem
 // @Router			/users/settings [patch]
 func (h *Handler) UpdateSettings(c *gin.Context) {
 	userID, ok := c.Get("userID")
 	if !ok {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError("userID not found in context"))
+		_ = c.Error(domainErr.ErrUnauthorized)
 		return
 	}
 
 	var request UpdateUserSettingsRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewAPIError(err.Error()))
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -66,12 +66,7 @@ func (h *Handler) UpdateSettings(c *gin.Context) {
 	}, userID.(uint64))
 
 	if err != nil {
-		if errors.Is(err, domainErr.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.NewAPIError(err.Error()))
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewAPIError("An error occurred on the server. Please try again later."))
+		_ = c.Error(err)
 		return
 	}
 
@@ -0,0 +1,68 @@
+//go:build integration
+
+package api_test
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/testutil"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Run(m))
+}
+
+// newServer serves the API on top of the emptied shared database
+func newServer(t *testing.T) *testutil.Server {
+	t.Helper()
+
+	server := testutil.NewServer(testutil.Database(t), testutil.Config())
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// call sends the request, fails t unless the response has the wanted status and decodes the body into out when set
+func call(t *testing.T, server *testutil.Server, method, path, token string, body any, want int, out any) {
+	t.Helper()
+
+	resp, err := server.Do(context.Background(), method, path, token, body)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+
+	if resp.StatusCode != want {
+		data, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		t.Fatalf("%s %s: got status %d, want %d: %s", method, path, resp.StatusCode, want, data)
+	}
+
+	if out == nil {
+		_ = resp.Body.Close()
+		return
+	}
+
+	if err = testutil.DecodeJSON(resp, out); err != nil {
+		t.Fatalf("%s %s: failed to decode response: %v", method, path, err)
+	}
+}
+
+// token issues an access token for the user
+func token(t *testing.T, server *testutil.Server, userID uint64) string {
+	t.Helper()
+
+	token, err := server.Token(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+// problem is the code of an error response
+type problem struct {
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+}
@@ -4,14 +4,20 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
+	healthHandler "github.com/tclutin/classflow-api/internal/api/http/health"
+	"github.com/tclutin/classflow-api/internal/api/http/middleware"
 	"github.com/tclutin/classflow-api/internal/config"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/pkg/health"
+	"github.com/tclutin/classflow-api/pkg/ratelimit"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"log"
 
 	v1 "github.com/tclutin/classflow-api/internal/api/http/v1"
 	"github.com/tclutin/classflow-api/internal/domain"
-	"net/http"
 )
 
-func NewRouter(services *domain.Services, cfg *config.Config) *gin.Engine {
+func NewRouter(services *domain.Services, probes *health.Health, cfg *config.Config) *gin.Engine {
 	if cfg.IsProd() {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -20,20 +26,68 @@ func NewRouter(services *domain.Services, cfg *config.Config) *gin.Engine {
 		gin.SetMode(gin.DebugMode)
 	}
 
-	router := gin.Default()
+	middleware.RegisterValidatorTagNames()
 
-	router.Use(gin.Logger(), gin.Recovery())
+	router := gin.New()
 
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// without trusted proxies any client could pick its IP for the lockout and rate limits by X-Forwarded-For
+	if err := router.SetTrustedProxies(cfg.HTTPServer.TrustedProxies); err != nil {
+		log.Fatalf("invalid trusted proxies: %v", err)
+	}
 
-	router.GET("/health", func(c *gin.Context) {
-		c.Status(http.StatusOK)
+	router.Use(
+		otelgin.Middleware(cfg.Tracing.ServiceName),
+		middleware.RequestIDMiddleware(),
+		middleware.MetricsMiddleware(),
+		middleware.LoggerMiddleware(),
+		middleware.ErrorMiddleware(),
+		middleware.RecoveryMiddleware(),
+		middleware.RequestMetadataMiddleware())
+
+	router.NoRoute(func(c *gin.Context) {
+		_ = c.Error(domainErr.ErrRouteNotFound)
 	})
 
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	healthHandler.NewHandler(probes).Bind(router)
+
 	root := router.Group("/api")
 	{
-		v1.NewHandler(services).InitAPI(root)
+		v1.NewHandler(services, newRateLimiter(cfg)).InitAPI(root)
 	}
 
 	return router
 }
+
+func newRateLimiter(cfg *config.Config) *ratelimit.Limiter {
+	if !cfg.RateLimit.Enabled {
+		return nil
+	}
+
+	var store ratelimit.Store
+
+	switch cfg.RateLimit.Store {
+	case config.MemoryStore:
+		store = ratelimit.NewMemoryStore()
+	default:
+		log.Fatalf("unsupported rate limit store: %s", cfg.RateLimit.Store)
+	}
+
+	return ratelimit.NewLimiter(store, map[string]ratelimit.Policy{
+		middleware.DefaultPolicy:  toRateLimitPolicy(cfg.RateLimit.Default),
+		middleware.AuthPolicy:     toRateLimitPolicy(cfg.RateLimit.Auth),
+		middleware.SchedulePolicy: toRateLimitPolicy(cfg.RateLimit.Schedule),
+		middleware.IPPolicy: {
+			Anonymous: ratelimit.Rule{Rate: cfg.RateLimit.IP.Rate, Burst: cfg.RateLimit.IP.Burst},
+		},
+	}, cfg.RateLimit.APIKeys)
+}
+
+func toRateLimitPolicy(policy config.RateLimitPolicy) ratelimit.Policy {
+	return ratelimit.Policy{
+		User:      ratelimit.Rule{Rate: policy.User.Rate, Burst: policy.User.Burst},
+		APIKey:    ratelimit.Rule{Rate: policy.APIKey.Rate, Burst: policy.APIKey.Burst},
+		Anonymous: ratelimit.Rule{Rate: policy.Anonymous.Rate, Burst: policy.Anonymous.Burst},
+	}
+}
@@ -2,119 +2,190 @@ package app
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/tclutin/classflow-api/internal/api"
 	"github.com/tclutin/classflow-api/internal/config"
 	"github.com/tclutin/classflow-api/internal/domain"
+	"github.com/tclutin/classflow-api/internal/metric"
 	"github.com/tclutin/classflow-api/internal/migrator"
 	"github.com/tclutin/classflow-api/internal/repository"
 	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/health"
 	"github.com/tclutin/classflow-api/pkg/jwt"
+	"github.com/tclutin/classflow-api/pkg/lifecycle"
 	"github.com/tclutin/classflow-api/pkg/logger"
+	"github.com/tclutin/classflow-api/pkg/tracing"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
-
 	"syscall"
+	"time"
 )
 
+const healthCheckTimeout = 2 * time.Second
+
 type App struct {
-	server *http.Server
-	pool   *pgxpool.Pool
+	runner *lifecycle.Runner
 	logger *slog.Logger
 }
 
-func NewApp() *App {
-	cfg := config.MustLoad()
-
+func NewApp(cfg *config.Config) *App {
 	appLogger := logger.New(cfg.Environment, "logs/app.log")
 
-	dsn := fmt.Sprintf(
-		"postgresql://%v:%v@%v:%v/%v",
-		cfg.Postgres.User,
-		cfg.Postgres.Password,
-		cfg.Postgres.Host,
-		cfg.Postgres.Port,
-		cfg.Postgres.DbName)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: cfg.Tracing.ServiceName,
+		Environment: cfg.Environment,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		appLogger.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
+	postgres, err := postgresql.NewPool(context.Background(), cfg.Postgres.PrimaryPool(), appLogger)
+	if err != nil {
+		appLogger.Error("Failed to connect to the database", "error", err)
+		os.Exit(1)
+	}
+
+	prometheus.MustRegister(metric.NewPoolCollector(postgres, "primary"))
+
+	replica := postgres
+	if cfg.Postgres.HasReplica() {
+		replica, err = postgresql.NewPool(context.Background(), cfg.Postgres.ReplicaPool(), appLogger)
+		if err != nil {
+			appLogger.Error("Failed to connect to the database replica", "error", err)
+			os.Exit(1)
+		}
 
-	postgres := postgresql.NewPool(context.Background(), dsn)
+		prometheus.MustRegister(metric.NewPoolCollector(replica, "replica"))
+	}
+
+	migr, err := migrator.New(postgres, appLogger)
+	if err != nil {
+		appLogger.Error("Failed to set up migrations", "error", err)
+		os.Exit(1)
+	}
 
-	migr := migrator.New(postgres, appLogger)
-	migr.Init(context.Background(), cfg.Admin.Email, cfg.Admin.Password)
+	if cfg.Migrations.OnStart {
+		err = migr.Init(context.Background(), cfg.Admin.Email, cfg.Admin.Password)
+	} else {
+		err = migr.CreateAdminUser(context.Background(), cfg.Admin.Email, cfg.Admin.Password)
+	}
+	if err != nil {
+		appLogger.Error("Failed to prepare the database", "error", err)
+		os.Exit(1)
+	}
 
-	repositories := repository.NewRepositories(postgres, appLogger)
+	repositories := repository.NewRepositories(postgres, replica, appLogger)
+	if cfg.Lockout.Store == config.MemoryStore {
+		repositories.Lockout = repository.NewMemoryLockoutRepository()
+	}
 
 	jwtManager := jwt.MustLoadTokenManager(cfg.JWT.Secret)
 
-	services := domain.NewServices(appLogger, jwtManager, repositories, cfg)
+	services := domain.NewServices(appLogger, jwtManager, repositories, postgresql.NewTxManager(postgres), cfg)
 
-	router := api.NewRouter(services, cfg)
+	probes := health.New(healthCheckTimeout)
+	probes.Register("postgres", postgres.Ping)
+	if cfg.Postgres.HasReplica() {
+		probes.Register("postgres_replica", replica.Ping)
+	}
+	probes.Register("migrations", migr.CheckVersion)
+
+	router := api.NewRouter(services, probes, cfg)
 
 	appServer := &http.Server{
 		Addr:    net.JoinHostPort(cfg.HTTPServer.Address, cfg.HTTPServer.Port),
 		Handler: router,
 	}
 
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+
+	metricsServer := &http.Server{
+		Addr:    cfg.Metrics.Address,
+		Handler: metricsMux,
+	}
+
+	runner := lifecycle.NewRunner(cfg.HTTPServer.ShutdownTimeout, appLogger)
+
+	runner.Add(
+		lifecycle.Component{
+			Name: "postgres",
+			Stop: func(_ context.Context) error {
+				postgres.Close()
+				return nil
+			},
+		},
+		lifecycle.Component{
+			Name: "postgres-replica",
+			Stop: func(_ context.Context) error {
+				if replica != postgres {
+					replica.Close()
+				}
+				return nil
+			},
+		},
+		lifecycle.Component{
+			Name: "tracing",
+			Stop: shutdownTracing,
+		},
+		lifecycle.Every("lockout-cleanup", cfg.Lockout.CleanupInterval, appLogger, func(ctx context.Context) error {
+			_, err := services.Lockout.PurgeStale(ctx)
+			return err
+		}),
+		lifecycle.HTTPServer("metrics-server", metricsServer),
+		drainingServer(lifecycle.HTTPServer("http-server", appServer), probes, cfg.HTTPServer.DrainDelay, appLogger),
+	)
+
 	return &App{
-		server: appServer,
-		pool:   postgres,
+		runner: runner,
 		logger: appLogger,
 	}
 }
 
-func (app *App) Run(ctx context.Context) {
+// Run blocks until SIGINT or SIGTERM is received or a component fails, then stops the application
+func (app *App) Run(ctx context.Context) error {
 	app.logger.Info("Starting application...")
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		mux := http.NewServeMux()
-		mux.Handle("/metrics", promhttp.Handler())
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-		httpServer := &http.Server{
-			Addr:    "app:2112",
-			Handler: mux,
-		}
+	if err := app.runner.Run(ctx); err != nil {
+		app.logger.Error("Application stopped with error", "error", err)
+		return err
+	}
 
-		app.logger.Info("Prometheus server is starting...")
-		err := httpServer.ListenAndServe()
-		if err != nil && errors.Is(err, http.ErrServerClosed) {
-			app.logger.Error("Server stopped with error", "error", err)
-			os.Exit(1)
-		}
-	}()
+	app.logger.Info("Application shutdown completed")
 
-	go func() {
-		app.logger.Info("Server is starting...")
-		err := app.server.ListenAndServe()
-		if err != nil && errors.Is(err, http.ErrServerClosed) {
-			app.logger.Error("Server stopped with error", "error", err)
-			os.Exit(1)
-		}
-	}()
+	return nil
+}
 
-	app.logger.Info("Server started successfully")
+// drainingServer flips readiness before stopping the server, so load balancers stop routing new requests
+// while in-flight ones are still being served
+func drainingServer(component lifecycle.Component, probes *health.Health, drainDelay time.Duration, logger *slog.Logger) lifecycle.Component {
+	shutdown := component.Stop
 
-	<-quit
-	app.logger.Info("Received shutdown signal, stopping application...")
-	app.Stop(ctx)
-}
+	component.Stop = func(ctx context.Context) error {
+		probes.SetShuttingDown()
 
-func (app *App) Stop(ctx context.Context) {
-	app.logger.Info("Shutting down app...")
+		if drainDelay > 0 {
+			logger.Info("Waiting for load balancers to stop routing traffic", "delay", drainDelay)
 
-	app.pool.Close()
+			select {
+			case <-time.After(drainDelay):
+			case <-ctx.Done():
+			}
+		}
 
-	if err := app.server.Shutdown(ctx); err != nil {
-		app.logger.Error("Error during server shutdown", "error", err)
-		os.Exit(1)
+		return shutdown(ctx)
 	}
 
-	app.logger.Info("Application shutdown completed")
+	return component
 }
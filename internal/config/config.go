@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"io/fs"
 	"log"
 	"time"
 )
@@ -12,49 +16,223 @@ const (
 	prod string = "prod"
 )
 
+const (
+	PostgresStore string = "postgres"
+	MemoryStore   string = "memory"
+)
+
+// Config is read from an optional YAML file first, environment variables override the file,
+// fields tagged secret can also be read from a file named by the <ENV>_FILE variable
 type Config struct {
-	Environment string `env:"ENVIRONMENT"`
-	Admin       Admin
-	HTTPServer  HTTPServer
-	Postgres    Postgres
-	JWT         JWT
+	Environment string     `yaml:"environment" env:"ENVIRONMENT" env-default:"prod"`
+	Admin       Admin      `yaml:"admin"`
+	HTTPServer  HTTPServer `yaml:"http"`
+	Metrics     Metrics    `yaml:"metrics"`
+	Postgres    Postgres   `yaml:"postgres"`
+	Migrations  Migrations `yaml:"migrations"`
+	Schedule    Schedule   `yaml:"schedule"`
+	JWT         JWT        `yaml:"jwt"`
+	Lockout     Lockout    `yaml:"lockout"`
+	RateLimit   RateLimit  `yaml:"rate_limit"`
+	Tracing     Tracing    `yaml:"tracing"`
 }
 
 type Admin struct {
-	Email    string `env:"ADMIN_EMAIL"`
-	Password string `env:"ADMIN_PASSWORD"`
+	Email    string `yaml:"email" env:"ADMIN_EMAIL"`
+	Password string `yaml:"password" env:"ADMIN_PASSWORD" secret:"true"`
 }
 
+// HTTPServer DrainDelay is how long readiness reports down before the server stops accepting requests,
+// ShutdownTimeout bounds the whole shutdown including the drain delay. TrustedProxies are the IPs and CIDRs
+// whose X-Forwarded-For header is used as the client IP, the peer address is used when it is empty
 type HTTPServer struct {
-	Address string `env:"HTTP_HOST"`
-	Port    string `env:"HTTP_PORT"`
+	Address         string        `yaml:"host" env:"HTTP_HOST" env-default:"0.0.0.0"`
+	Port            string        `yaml:"port" env:"HTTP_PORT" env-default:"8080"`
+	DrainDelay      time.Duration `yaml:"drain_delay" env:"HTTP_DRAIN_DELAY" env-default:"5s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"30s"`
+	TrustedProxies  []string      `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES" env-separator:","`
 }
 
+type Metrics struct {
+	Address string `yaml:"address" env:"METRICS_ADDRESS" env-default:":2112"`
+}
+
+// Postgres pool sizes and timeouts of zero keep the pgx defaults, the replica is used by read-only
+// repositories when ReplicaHost is set and shares credentials with the primary
 type Postgres struct {
-	Host     string `env:"POSTGRES_HOST"`
-	Port     string `env:"POSTGRES_PORT"`
-	DbName   string `env:"POSTGRES_DB"`
-	User     string `env:"POSTGRES_USER"`
-	Password string `env:"POSTGRES_PASSWORD"`
+	Host     string `yaml:"host" env:"POSTGRES_HOST" env-default:"localhost"`
+	Port     string `yaml:"port" env:"POSTGRES_PORT" env-default:"5432"`
+	DbName   string `yaml:"db" env:"POSTGRES_DB"`
+	User     string `yaml:"user" env:"POSTGRES_USER"`
+	Password string `yaml:"password" env:"POSTGRES_PASSWORD" secret:"true"`
+	SSLMode  string `yaml:"ssl_mode" env:"POSTGRES_SSL_MODE" env-default:"disable"`
+
+	MaxConns          int32         `yaml:"max_conns" env:"POSTGRES_MAX_CONNS" env-default:"10"`
+	MinConns          int32         `yaml:"min_conns" env:"POSTGRES_MIN_CONNS" env-default:"0"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" env:"POSTGRES_MAX_CONN_LIFETIME" env-default:"1h"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" env:"POSTGRES_MAX_CONN_IDLE_TIME" env-default:"30m"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"POSTGRES_HEALTH_CHECK_PERIOD" env-default:"1m"`
+	ConnectTimeout    time.Duration `yaml:"connect_timeout" env:"POSTGRES_CONNECT_TIMEOUT" env-default:"5s"`
+	StatementTimeout  time.Duration `yaml:"statement_timeout" env:"POSTGRES_STATEMENT_TIMEOUT" env-default:"30s"`
+
+	ConnectAttempts int           `yaml:"connect_attempts" env:"POSTGRES_CONNECT_ATTEMPTS" env-default:"10"`
+	RetryDelay      time.Duration `yaml:"retry_delay" env:"POSTGRES_RETRY_DELAY" env-default:"500ms"`
+	MaxRetryDelay   time.Duration `yaml:"max_retry_delay" env:"POSTGRES_MAX_RETRY_DELAY" env-default:"10s"`
+
+	ReplicaHost string `yaml:"replica_host" env:"POSTGRES_REPLICA_HOST"`
+	ReplicaPort string `yaml:"replica_port" env:"POSTGRES_REPLICA_PORT" env-default:"5432"`
+}
+
+func (p Postgres) HasReplica() bool {
+	return p.ReplicaHost != ""
+}
+
+// PrimaryPool and ReplicaPool describe the connection pools built from these settings
+func (p Postgres) PrimaryPool() postgresql.Config {
+	return p.pool(p.Host, p.Port)
+}
+
+func (p Postgres) ReplicaPool() postgresql.Config {
+	return p.pool(p.ReplicaHost, p.ReplicaPort)
+}
+
+func (p Postgres) pool(host, port string) postgresql.Config {
+	return postgresql.Config{
+		Host:              host,
+		Port:              port,
+		Database:          p.DbName,
+		User:              p.User,
+		Password:          p.Password,
+		SSLMode:           p.SSLMode,
+		MaxConns:          p.MaxConns,
+		MinConns:          p.MinConns,
+		MaxConnLifetime:   p.MaxConnLifetime,
+		MaxConnIdleTime:   p.MaxConnIdleTime,
+		HealthCheckPeriod: p.HealthCheckPeriod,
+		ConnectTimeout:    p.ConnectTimeout,
+		StatementTimeout:  p.StatementTimeout,
+		ConnectAttempts:   p.ConnectAttempts,
+		RetryDelay:        p.RetryDelay,
+		MaxRetryDelay:     p.MaxRetryDelay,
+	}
+}
+
+// Migrations OnStart applies migrations and seeds when the app starts, disable it when they are run
+// separately with cmd/migrate
+type Migrations struct {
+	OnStart bool `yaml:"on_start" env:"MIGRATIONS_ON_START" env-default:"true"`
+}
+
+// Schedule TermStart is a date in the first, odd week of the term, the week parity of lessons is counted
+// from it. When it is not set the term starts on September 1 of the academic year
+type Schedule struct {
+	TermStart time.Time `yaml:"term_start" env:"SCHEDULE_TERM_START" env-layout:"2006-01-02"`
 }
 
 type JWT struct {
-	Secret string        `env:"JWT_SECRET"`
-	Expire time.Duration `env:"JWT_EXPIRE"`
+	Secret string        `yaml:"secret" env:"JWT_SECRET" secret:"true"`
+	Expire time.Duration `yaml:"expire" env:"JWT_EXPIRE" env-default:"720h"`
 }
 
-func MustLoad() *Config {
-	var config Config
+type Lockout struct {
+	Store           string        `yaml:"store" env:"LOCKOUT_STORE" env-default:"postgres"`
+	MaxAttempts     int           `yaml:"max_attempts" env:"LOCKOUT_MAX_ATTEMPTS" env-default:"5"`
+	IPMaxAttempts   int           `yaml:"ip_max_attempts" env:"LOCKOUT_IP_MAX_ATTEMPTS" env-default:"30"`
+	Window          time.Duration `yaml:"window" env:"LOCKOUT_WINDOW" env-default:"15m"`
+	BaseDelay       time.Duration `yaml:"base_delay" env:"LOCKOUT_BASE_DELAY" env-default:"30s"`
+	MaxDelay        time.Duration `yaml:"max_delay" env:"LOCKOUT_MAX_DELAY" env-default:"1h"`
+	TrustedIPs      []string      `yaml:"trusted_ips" env:"LOCKOUT_TRUSTED_IPS" env-separator:","`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"LOCKOUT_CLEANUP_INTERVAL" env-default:"10m"`
+}
+
+// RateLimit IP is checked per client address before authentication on protected routes, so requests
+// with bad tokens are limited too. It is shared by everybody behind one address and should stay generous
+type RateLimit struct {
+	Enabled  bool            `yaml:"enabled" env:"RATE_LIMIT_ENABLED" env-default:"true"`
+	Store    string          `yaml:"store" env:"RATE_LIMIT_STORE" env-default:"memory"`
+	APIKeys  []string        `yaml:"api_keys" env:"RATE_LIMIT_API_KEYS" env-separator:"," secret:"true"`
+	Default  RateLimitPolicy `yaml:"default" env-prefix:"RATE_LIMIT_DEFAULT_"`
+	Auth     RateLimitPolicy `yaml:"auth" env-prefix:"RATE_LIMIT_AUTH_"`
+	Schedule RateLimitPolicy `yaml:"schedule" env-prefix:"RATE_LIMIT_SCHEDULE_"`
+	IP       RateLimitRule   `yaml:"ip" env-prefix:"RATE_LIMIT_IP_"`
+}
+
+type RateLimitPolicy struct {
+	User      RateLimitRule `yaml:"user" env-prefix:"USER_"`
+	APIKey    RateLimitRule `yaml:"api_key" env-prefix:"API_KEY_"`
+	Anonymous RateLimitRule `yaml:"anonymous" env-prefix:"ANONYMOUS_"`
+}
 
-	if err := godotenv.Load(".env"); err != nil {
+// RateLimitRule Rate is requests per second, Burst is the bucket size
+type RateLimitRule struct {
+	Rate  float64 `yaml:"rate" env:"RATE"`
+	Burst int     `yaml:"burst" env:"BURST"`
+}
+
+// Tracing follows the OpenTelemetry env names, traces are not exported when the endpoint is empty
+type Tracing struct {
+	ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME" env-default:"classflow-api"`
+	Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE" env-default:"false"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_RATIO" env-default:"1"`
+}
+
+func MustLoad(path string) *Config {
+	config, err := Load(path)
+	if err != nil {
 		log.Fatalln(err)
 	}
 
-	if err := cleanenv.ReadEnv(&config); err != nil {
-		log.Fatalln(err)
+	return config
+}
+
+// Load reads the YAML file at path when it is not empty, then the environment, and validates the result.
+// A missing .env file is not an error, containers usually get their variables injected
+func Load(path string) (*Config, error) {
+	config := Config{
+		RateLimit: RateLimit{
+			Default: RateLimitPolicy{
+				User:      RateLimitRule{Rate: 10, Burst: 30},
+				APIKey:    RateLimitRule{Rate: 50, Burst: 100},
+				Anonymous: RateLimitRule{Rate: 2, Burst: 10},
+			},
+			Auth: RateLimitPolicy{
+				User:      RateLimitRule{Rate: 1, Burst: 5},
+				APIKey:    RateLimitRule{Rate: 20, Burst: 50},
+				Anonymous: RateLimitRule{Rate: 0.5, Burst: 5},
+			},
+			Schedule: RateLimitPolicy{
+				User:      RateLimitRule{Rate: 5, Burst: 20},
+				APIKey:    RateLimitRule{Rate: 100, Burst: 200},
+				Anonymous: RateLimitRule{Rate: 1, Burst: 5},
+			},
+			IP: RateLimitRule{Rate: 50, Burst: 100},
+		},
+	}
+
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+
+	if err := loadSecretFiles(&config); err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		if err := cleanenv.ReadConfig(path, &config); err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+	} else {
+		if err := cleanenv.ReadEnv(&config); err != nil {
+			return nil, fmt.Errorf("failed to read environment: %w", err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	return &config
+	return &config, nil
 }
 
 func (c *Config) IsProd() bool {
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const (
+	secretFileSuffix = "_FILE"
+	redacted         = "[REDACTED]"
+)
+
+// loadSecretFiles sets every secret variable that is unset but has a <ENV>_FILE counterpart,
+// the way docker and kubernetes secrets are mounted
+func loadSecretFiles(config *Config) error {
+	for _, name := range secretEnvNames(reflect.TypeOf(config).Elem(), "") {
+		path, ok := os.LookupEnv(name + secretFileSuffix)
+		if !ok {
+			continue
+		}
+
+		if _, ok = os.LookupEnv(name); ok {
+			return fmt.Errorf("both %s and %s%s are set", name, name, secretFileSuffix)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s%s: %w", name, secretFileSuffix, err)
+		}
+
+		if err = os.Setenv(name, strings.TrimRight(string(content), "\r\n")); err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func secretEnvNames(t reflect.Type, prefix string) []string {
+	var names []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			names = append(names, secretEnvNames(field.Type, prefix+field.Tag.Get("env-prefix"))...)
+			continue
+		}
+
+		if field.Tag.Get("secret") == "true" {
+			names = append(names, prefix+field.Tag.Get("env"))
+		}
+	}
+
+	return names
+}
+
+// Print writes the effective config as YAML with secrets redacted
+func Print(w io.Writer, config *Config) error {
+	copied := *config
+	redact(reflect.ValueOf(&copied).Elem())
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(copied); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		tag := v.Type().Field(i).Tag
+
+		switch {
+		case field.Kind() == reflect.Struct:
+			redact(field)
+		case tag.Get("secret") != "true" || field.IsZero():
+			continue
+		case field.Kind() == reflect.String:
+			field.SetString(redacted)
+		case field.Kind() == reflect.Slice:
+			field.Set(reflect.ValueOf([]string{redacted}))
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minJWTSecretLength = 16
+	maxJWTExpire       = 90 * 24 * time.Hour
+)
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate reports every invalid setting at once, so a broken deployment is fixed in one pass
+func (c *Config) Validate() error {
+	var errs []error
+
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(slices.Contains([]string{dev, prod}, c.Environment), "ENVIRONMENT must be one of %s, %s", dev, prod)
+
+	check(c.Admin.Email != "", "ADMIN_EMAIL is required")
+	check(c.Admin.Password != "", "ADMIN_PASSWORD is required")
+
+	check(validPort(c.HTTPServer.Port), "HTTP_PORT must be a port number between 1 and 65535")
+	check(c.HTTPServer.DrainDelay >= 0, "HTTP_DRAIN_DELAY must not be negative")
+	check(c.HTTPServer.ShutdownTimeout > c.HTTPServer.DrainDelay, "HTTP_SHUTDOWN_TIMEOUT must be greater than HTTP_DRAIN_DELAY")
+	check(validProxies(c.HTTPServer.TrustedProxies), "HTTP_TRUSTED_PROXIES must be IP addresses or CIDRs")
+	check(c.Metrics.Address != "", "METRICS_ADDRESS is required")
+
+	check(c.Postgres.Host != "", "POSTGRES_HOST is required")
+	check(validPort(c.Postgres.Port), "POSTGRES_PORT must be a port number between 1 and 65535")
+	check(c.Postgres.DbName != "", "POSTGRES_DB is required")
+	check(c.Postgres.User != "", "POSTGRES_USER is required")
+	check(c.Postgres.Password != "", "POSTGRES_PASSWORD is required")
+	check(slices.Contains(sslModes, c.Postgres.SSLMode), "POSTGRES_SSL_MODE must be one of %s", strings.Join(sslModes, ", "))
+	check(c.Postgres.MaxConns > 0, "POSTGRES_MAX_CONNS must be positive")
+	check(c.Postgres.MinConns >= 0 && c.Postgres.MinConns <= c.Postgres.MaxConns, "POSTGRES_MIN_CONNS must be between 0 and POSTGRES_MAX_CONNS")
+	check(c.Postgres.StatementTimeout >= 0, "POSTGRES_STATEMENT_TIMEOUT must not be negative")
+	check(c.Postgres.ConnectAttempts > 0, "POSTGRES_CONNECT_ATTEMPTS must be positive")
+	check(c.Postgres.RetryDelay > 0 && c.Postgres.MaxRetryDelay >= c.Postgres.RetryDelay, "POSTGRES_RETRY_DELAY must be positive and not greater than POSTGRES_MAX_RETRY_DELAY")
+	check(!c.Postgres.HasReplica() || validPort(c.Postgres.ReplicaPort), "POSTGRES_REPLICA_PORT must be a port number between 1 and 65535")
+
+	check(len(c.JWT.Secret) >= minJWTSecretLength, "JWT_SECRET must be at least %d characters", minJWTSecretLength)
+	check(c.JWT.Expire > 0 && c.JWT.Expire <= maxJWTExpire, "JWT_EXPIRE must be positive and at most 2160h")
+
+	check(slices.Contains([]string{PostgresStore, MemoryStore}, c.Lockout.Store), "LOCKOUT_STORE must be one of %s, %s", PostgresStore, MemoryStore)
+	check(c.Lockout.MaxAttempts > 0, "LOCKOUT_MAX_ATTEMPTS must be positive")
+	check(c.Lockout.IPMaxAttempts > 0, "LOCKOUT_IP_MAX_ATTEMPTS must be positive")
+	check(c.Lockout.Window > 0, "LOCKOUT_WINDOW must be positive")
+	check(c.Lockout.BaseDelay > 0, "LOCKOUT_BASE_DELAY must be positive")
+	check(c.Lockout.MaxDelay >= c.Lockout.BaseDelay, "LOCKOUT_MAX_DELAY must not be less than LOCKOUT_BASE_DELAY")
+	check(c.Lockout.CleanupInterval > 0, "LOCKOUT_CLEANUP_INTERVAL must be positive")
+
+	check(c.RateLimit.Store == MemoryStore, "RATE_LIMIT_STORE must be %s", MemoryStore)
+
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "OTEL_TRACES_SAMPLER_RATIO must be between 0 and 1")
+
+	return errors.Join(errs...)
+}
+
+func validProxies(proxies []string) bool {
+	for _, proxy := range proxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validPort(port string) bool {
+	value, err := strconv.Atoi(port)
+	return err == nil && value > 0 && value <= 65535
+}
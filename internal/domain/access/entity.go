@@ -0,0 +1,72 @@
+package access
+
+const (
+	UsersCreate        = "users:create"
+	AdminsManage       = "admins:manage"
+	AnnouncementsWrite = "announcements:write"
+	AttendanceExport   = "attendance:export"
+	AttendanceWrite    = "attendance:write"
+	AuditRead          = "audit:read"
+	GroupCreate        = "group:create"
+	GroupDelete        = "group:delete"
+	GroupJoin          = "group:join"
+	GroupLeave         = "group:leave"
+	HomeworkWrite      = "homework:write"
+	MembershipRead     = "membership:read"
+	MembersManage      = "members:manage"
+	OutboxConsume      = "outbox:consume"
+	ScheduleWrite      = "schedule:write"
+	SettingsWrite      = "settings:write"
+)
+
+const (
+	ScopeFaculty = "faculty"
+	ScopeGroup   = "group"
+)
+
+// Principal is the authenticated caller whose permissions are checked
+type Principal struct {
+	UserID uint64
+	Role   string
+}
+
+// Scope is a resource a permission can be limited to, e.g. a faculty or a group
+type Scope struct {
+	Type string
+	ID   uint64
+}
+
+func Faculty(facultyID uint64) Scope {
+	return Scope{Type: ScopeFaculty, ID: facultyID}
+}
+
+func Group(groupID uint64) Scope {
+	return Scope{Type: ScopeGroup, ID: groupID}
+}
+
+// Grant is a permission given to a role, ScopeType is nil when the permission is not limited to any resource
+type Grant struct {
+	Role       string
+	Permission string
+	ScopeType  *string
+}
+
+// Grants is everything a principal is allowed to do, it is loaded once per request and reused by every check
+type Grants struct {
+	Principal Principal
+	Grants    []Grant
+	Scopes    []Scope
+}
+
+// ScopeIds returns the ids of the principal's scopes of scopeType
+func (g Grants) ScopeIds(scopeType string) []uint64 {
+	ids := make([]uint64, 0)
+
+	for _, scope := range g.Scopes {
+		if scope.Type == scopeType {
+			ids = append(ids, scope.ID)
+		}
+	}
+
+	return ids
+}
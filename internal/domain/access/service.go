@@ -0,0 +1,334 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/tracing"
+	"slices"
+)
+
+type UserService interface {
+	GetById(ctx context.Context, userID uint64) (user.User, error)
+	Update(ctx context.Context, user user.User) error
+}
+
+type EduService interface {
+	GetFacultyById(ctx context.Context, facultyID uint64) (edu.Faculty, error)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...postgresql.TxOption) error
+}
+
+type Repository interface {
+	GetGrants(ctx context.Context, role string) ([]Grant, error)
+	GetScopes(ctx context.Context, userID uint64) ([]Scope, error)
+	CreateScope(ctx context.Context, userID uint64, scope Scope) error
+	DeleteScope(ctx context.Context, userID uint64, scope Scope) error
+}
+
+type Service struct {
+	userService  UserService
+	eduService   EduService
+	auditService AuditService
+	txManager    TxManager
+	repo         Repository
+}
+
+func NewService(repo Repository, txManager TxManager, userService UserService, eduService EduService, auditService AuditService) *Service {
+	return &Service{
+		userService:  userService,
+		eduService:   eduService,
+		auditService: auditService,
+		txManager:    txManager,
+		repo:         repo,
+	}
+}
+
+type grantsKey struct{}
+
+// WithGrants stores the loaded grants in the context so later checks within the request skip the database
+func WithGrants(ctx context.Context, grants Grants) context.Context {
+	return context.WithValue(ctx, grantsKey{}, grants)
+}
+
+// Load returns the grants of the principal, taking them from the context when they were already loaded
+func (s *Service) Load(ctx context.Context, principal Principal) (Grants, error) {
+	if grants, ok := ctx.Value(grantsKey{}).(Grants); ok && grants.Principal == principal {
+		return grants, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "access.Service.Load")
+	defer span.End()
+
+	grants, err := s.repo.GetGrants(ctx, principal.Role)
+	if err != nil {
+		return Grants{}, fmt.Errorf("failed to get grants: %w", err)
+	}
+
+	scopes, err := s.repo.GetScopes(ctx, principal.UserID)
+	if err != nil {
+		return Grants{}, fmt.Errorf("failed to get scopes: %w", err)
+	}
+
+	return Grants{Principal: principal, Grants: grants, Scopes: scopes}, nil
+}
+
+// HasPermission reports whether the principal holds the permission globally or within at least one scope,
+// it is meant for coarse checks before the target resource is known
+func (s *Service) HasPermission(ctx context.Context, principal Principal, permission string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "access.Service.HasPermission")
+	defer span.End()
+
+	grants, err := s.Load(ctx, principal)
+	if err != nil {
+		return false, err
+	}
+
+	for _, grant := range grants.Grants {
+		if grant.Permission != permission {
+			continue
+		}
+
+		if grant.ScopeType == nil || len(grants.ScopeIds(*grant.ScopeType)) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Can reports whether the principal holds the permission globally or within any of the given scopes
+func (s *Service) Can(ctx context.Context, principal Principal, permission string, scopes ...Scope) (bool, error) {
+	ctx, span := tracing.Start(ctx, "access.Service.Can")
+	defer span.End()
+
+	grants, err := s.Load(ctx, principal)
+	if err != nil {
+		return false, err
+	}
+
+	for _, grant := range grants.Grants {
+		if grant.Permission != permission {
+			continue
+		}
+
+		if grant.ScopeType == nil {
+			return true, nil
+		}
+
+		for _, scope := range scopes {
+			if scope.Type == *grant.ScopeType && slices.Contains(grants.Scopes, scope) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (s *Service) Authorize(ctx context.Context, principal Principal, permission string, scopes ...Scope) error {
+	ctx, span := tracing.Start(ctx, "access.Service.Authorize")
+	defer span.End()
+
+	ok, err := s.Can(ctx, principal, permission, scopes...)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return domainErr.ErrForbidden
+	}
+
+	return nil
+}
+
+// ScopedIds returns the ids of scopeType the principal is limited to for the permission,
+// restricted is false when the principal holds the permission globally or does not hold it at all
+func (s *Service) ScopedIds(ctx context.Context, principal Principal, permission string, scopeType string) ([]uint64, bool, error) {
+	ctx, span := tracing.Start(ctx, "access.Service.ScopedIds")
+	defer span.End()
+
+	grants, err := s.Load(ctx, principal)
+	if err != nil {
+		return nil, false, err
+	}
+
+	restricted := false
+
+	for _, grant := range grants.Grants {
+		if grant.Permission != permission {
+			continue
+		}
+
+		if grant.ScopeType == nil {
+			return nil, false, nil
+		}
+
+		if *grant.ScopeType == scopeType {
+			restricted = true
+		}
+	}
+
+	if !restricted {
+		return make([]uint64, 0), false, nil
+	}
+
+	return grants.ScopeIds(scopeType), true, nil
+}
+
+func (s *Service) GetFacultyIdsByUserId(ctx context.Context, userID uint64) ([]uint64, error) {
+	ctx, span := tracing.Start(ctx, "access.Service.GetFacultyIdsByUserId")
+	defer span.End()
+
+	if _, err := s.getAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.getFacultyIds(ctx, userID)
+}
+
+// AssignFaculty limits an administrator to the faculty. A global admin becomes a faculty admin only when demote
+// is set and they are not the caller, so the caller always keeps a global admin around
+func (s *Service) AssignFaculty(ctx context.Context, principal Principal, userID uint64, facultyID uint64, demote bool) error {
+	ctx, span := tracing.Start(ctx, "access.Service.AssignFaculty")
+	defer span.End()
+
+	usr, err := s.userService.GetById(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case usr.Role == user.Admin && !demote:
+		return domainErr.ErrAdminDemotion
+	case usr.Role == user.Admin && userID == principal.UserID:
+		return domainErr.ErrSelfDemotion
+	case usr.Role != user.Admin && usr.Role != user.FacultyAdmin && usr.Role != user.FormerAdmin:
+		return domainErr.ErrNotAdmin
+	}
+
+	if _, err = s.eduService.GetFacultyById(ctx, facultyID); err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getFacultyIds(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err = s.repo.CreateScope(ctx, userID, Faculty(facultyID)); err != nil {
+			return fmt.Errorf("failed to assign faculty: %w", err)
+		}
+
+		after, err := s.getFacultyIds(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		err = s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.AdminFacultyAssign,
+			TargetType: audit.TargetUser,
+			TargetID:   userID,
+			Before:     map[string]any{"role": usr.Role, "faculty_ids": before},
+			After:      map[string]any{"role": user.FacultyAdmin, "faculty_ids": after},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if usr.Role == user.FacultyAdmin {
+			return nil
+		}
+
+		usr.Role = user.FacultyAdmin
+
+		return s.userService.Update(ctx, usr)
+	})
+}
+
+// RevokeFaculty removes the faculty from the administrator, a faculty admin left without faculties
+// becomes a former admin until a faculty is assigned again
+func (s *Service) RevokeFaculty(ctx context.Context, userID uint64, facultyID uint64) error {
+	ctx, span := tracing.Start(ctx, "access.Service.RevokeFaculty")
+	defer span.End()
+
+	usr, err := s.getAdmin(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getFacultyIds(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err = s.repo.DeleteScope(ctx, userID, Faculty(facultyID)); err != nil {
+			return fmt.Errorf("failed to revoke faculty: %w", err)
+		}
+
+		after, err := s.getFacultyIds(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		role := usr.Role
+		if role == user.FacultyAdmin && len(after) == 0 {
+			role = user.FormerAdmin
+		}
+
+		err = s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.AdminFacultyRevoke,
+			TargetType: audit.TargetUser,
+			TargetID:   userID,
+			Before:     map[string]any{"role": usr.Role, "faculty_ids": before},
+			After:      map[string]any{"role": role, "faculty_ids": after},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if role == usr.Role {
+			return nil
+		}
+
+		usr.Role = role
+
+		return s.userService.Update(ctx, usr)
+	})
+}
+
+func (s *Service) getFacultyIds(ctx context.Context, userID uint64) ([]uint64, error) {
+	scopes, err := s.repo.GetScopes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faculties: %w", err)
+	}
+
+	return Grants{Scopes: scopes}.ScopeIds(ScopeFaculty), nil
+}
+
+func (s *Service) getAdmin(ctx context.Context, userID uint64) (user.User, error) {
+	usr, err := s.userService.GetById(ctx, userID)
+	if err != nil {
+		return usr, err
+	}
+
+	if usr.Role != user.Admin && usr.Role != user.FacultyAdmin {
+		return usr, domainErr.ErrNotAdmin
+	}
+
+	return usr, nil
+}
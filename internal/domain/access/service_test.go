@@ -0,0 +1,108 @@
+package access_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/internal/repository/memory"
+	"testing"
+	"time"
+)
+
+func TestService_FacultyAssignments(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+	txManager := memory.NewTxManager(store)
+
+	auditService := audit.NewService(repos.Audit)
+	userService := user.NewService(repos.User, txManager, auditService)
+	service := access.NewService(repos.Access, txManager, userService, edu.NewService(repos.Edu), auditService)
+
+	faculty, err := repos.Edu.AddFaculty(ctx, "Институт информационных технологий")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newUser := func(role string, email string) uint64 {
+		t.Helper()
+
+		userID, err := repos.User.Create(ctx, user.User{Role: role, Email: &email, CreatedAt: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return userID
+	}
+
+	admin := access.Principal{UserID: newUser(user.Admin, "admin@classflow.test"), Role: user.Admin}
+	facultyAdmin := newUser(user.FacultyAdmin, "faculty@classflow.test")
+	student := newUser(user.Student, "student@classflow.test")
+
+	role := func(userID uint64) string {
+		t.Helper()
+
+		usr, err := repos.User.GetById(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return usr.Role
+	}
+
+	tests := []struct {
+		name     string
+		run      func() error
+		userID   uint64
+		wantErr  error
+		wantRole string
+	}{
+		{
+			name:     "assign a faculty",
+			run:      func() error { return service.AssignFaculty(ctx, admin, facultyAdmin, faculty.FacultyID, false) },
+			userID:   facultyAdmin,
+			wantRole: user.FacultyAdmin,
+		},
+		{
+			name:     "revoke the last faculty",
+			run:      func() error { return service.RevokeFaculty(ctx, facultyAdmin, faculty.FacultyID) },
+			userID:   facultyAdmin,
+			wantRole: user.FormerAdmin,
+		},
+		{
+			name:     "former admin is assigned again",
+			run:      func() error { return service.AssignFaculty(ctx, admin, facultyAdmin, faculty.FacultyID, false) },
+			userID:   facultyAdmin,
+			wantRole: user.FacultyAdmin,
+		},
+		{
+			name:     "student with an email is not an admin",
+			run:      func() error { return service.AssignFaculty(ctx, admin, student, faculty.FacultyID, false) },
+			userID:   student,
+			wantErr:  domainErr.ErrNotAdmin,
+			wantRole: user.Student,
+		},
+		{
+			name:     "global admin is not demoted implicitly",
+			run:      func() error { return service.AssignFaculty(ctx, admin, admin.UserID, faculty.FacultyID, false) },
+			userID:   admin.UserID,
+			wantErr:  domainErr.ErrAdminDemotion,
+			wantRole: user.Admin,
+		},
+	}
+
+	// the steps change the same users, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		if err := tt.run(); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+
+		if got := role(tt.userID); got != tt.wantRole {
+			t.Fatalf("%s: got role %s, want %s", tt.name, got, tt.wantRole)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package announcement
+
+import "time"
+
+type CreateAnnouncementDTO struct {
+	GroupID       uint64
+	Title         string
+	Body          string
+	AttachmentURL *string
+	Pinned        bool
+	ExpiresAt     *time.Time
+}
+
+type UpdateAnnouncementDTO struct {
+	Title         string
+	Body          string
+	AttachmentURL *string
+	Pinned        bool
+	ExpiresAt     *time.Time
+}
+
+// DetailsAnnouncementDTO is an announcement as seen by one user, Read tells whether that user has read it
+type DetailsAnnouncementDTO struct {
+	Announcement
+	ReadCount int
+	Read      bool
+}
+
+type AnnouncementsPageDTO struct {
+	Announcements []DetailsAnnouncementDTO
+	Total         int
+	Limit         int
+	Offset        int
+}
+
+type FilterDTO struct {
+	IncludeExpired bool
+	Now            time.Time
+	Limit          int
+	Offset         int
+}
+
+// CreatedPayload is published to the outbox, so the notification service can broadcast the post to the group
+type CreatedPayload struct {
+	AnnouncementID uint64 `json:"announcement_id"`
+	GroupID        uint64 `json:"group_id"`
+	ShortName      string `json:"short_name"`
+	Title          string `json:"title"`
+	Pinned         bool   `json:"pinned"`
+}
@@ -0,0 +1,21 @@
+package announcement
+
+import "time"
+
+type Announcement struct {
+	AnnouncementID uint64
+	GroupID        uint64
+	AuthorID       *uint64
+	Title          string
+	Body           string
+	AttachmentURL  *string
+	Pinned         bool
+	// ExpiresAt hides the announcement from the board once passed, nil keeps it forever
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (a Announcement) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !a.ExpiresAt.After(now)
+}
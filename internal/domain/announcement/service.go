@@ -0,0 +1,272 @@
+package announcement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/tracing"
+	"time"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type GroupService interface {
+	Authorize(ctx context.Context, principal access.Principal, permission string, groupID uint64) (group.Group, error)
+	AuthorizeRead(ctx context.Context, principal access.Principal, permission string, groupID uint64) (group.Group, error)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+type OutboxService interface {
+	Publish(ctx context.Context, eventType string, payload any) error
+}
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...postgresql.TxOption) error
+}
+
+type Repository interface {
+	Create(ctx context.Context, announcement Announcement) (uint64, error)
+	Update(ctx context.Context, announcement Announcement) error
+	Delete(ctx context.Context, announcementID uint64) error
+	GetById(ctx context.Context, announcementID uint64) (Announcement, error)
+	GetAllByGroupId(ctx context.Context, groupID uint64, userID uint64, filter FilterDTO) ([]DetailsAnnouncementDTO, int, error)
+	MarkRead(ctx context.Context, announcementID uint64, userID uint64, readAt time.Time) error
+}
+
+type Service struct {
+	groupService  GroupService
+	auditService  AuditService
+	outboxService OutboxService
+	txManager     TxManager
+	repo          Repository
+}
+
+func NewService(
+	repo Repository,
+	txManager TxManager,
+	groupService GroupService,
+	auditService AuditService,
+	outboxService OutboxService,
+) *Service {
+
+	return &Service{
+		groupService:  groupService,
+		auditService:  auditService,
+		outboxService: outboxService,
+		txManager:     txManager,
+		repo:          repo,
+	}
+}
+
+// Create posts to the board of the group and publishes an event for the notification service
+// within the same transaction
+func (s *Service) Create(ctx context.Context, principal access.Principal, dto CreateAnnouncementDTO) (uint64, error) {
+	ctx, span := tracing.Start(ctx, "announcement.Service.Create")
+	defer span.End()
+
+	grp, err := s.groupService.Authorize(ctx, principal, access.AnnouncementsWrite, dto.GroupID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(now) {
+		return 0, domainErr.ErrInvalidExpiry
+	}
+
+	entity := Announcement{
+		GroupID:       dto.GroupID,
+		AuthorID:      &principal.UserID,
+		Title:         dto.Title,
+		Body:          dto.Body,
+		AttachmentURL: dto.AttachmentURL,
+		Pinned:        dto.Pinned,
+		ExpiresAt:     dto.ExpiresAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		announcementID, err := s.repo.Create(ctx, entity)
+		if err != nil {
+			return fmt.Errorf("failed to create announcement: %w", err)
+		}
+
+		entity.AnnouncementID = announcementID
+
+		err = s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.AnnouncementCreate,
+			TargetType: audit.TargetAnnouncement,
+			TargetID:   announcementID,
+			After:      entity,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return s.outboxService.Publish(ctx, outbox.AnnouncementCreated, CreatedPayload{
+			AnnouncementID: announcementID,
+			GroupID:        grp.GroupID,
+			ShortName:      grp.ShortName,
+			Title:          entity.Title,
+			Pinned:         entity.Pinned,
+		})
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return entity.AnnouncementID, nil
+}
+
+func (s *Service) Update(ctx context.Context, principal access.Principal, groupID, announcementID uint64, dto UpdateAnnouncementDTO) error {
+	ctx, span := tracing.Start(ctx, "announcement.Service.Update")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.AnnouncementsWrite, groupID); err != nil {
+		return err
+	}
+
+	before, err := s.getById(ctx, groupID, announcementID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(now) {
+		return domainErr.ErrInvalidExpiry
+	}
+
+	entity := before
+	entity.Title = dto.Title
+	entity.Body = dto.Body
+	entity.AttachmentURL = dto.AttachmentURL
+	entity.Pinned = dto.Pinned
+	entity.ExpiresAt = dto.ExpiresAt
+	entity.UpdatedAt = now
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, entity); err != nil {
+			return fmt.Errorf("failed to update announcement: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.AnnouncementUpdate,
+			TargetType: audit.TargetAnnouncement,
+			TargetID:   announcementID,
+			Before:     before,
+			After:      entity,
+		})
+	})
+}
+
+func (s *Service) Delete(ctx context.Context, principal access.Principal, groupID, announcementID uint64) error {
+	ctx, span := tracing.Start(ctx, "announcement.Service.Delete")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.AnnouncementsWrite, groupID); err != nil {
+		return err
+	}
+
+	before, err := s.getById(ctx, groupID, announcementID)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, announcementID); err != nil {
+			return fmt.Errorf("failed to delete announcement: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.AnnouncementDelete,
+			TargetType: audit.TargetAnnouncement,
+			TargetID:   announcementID,
+			Before:     before,
+		})
+	})
+}
+
+// GetAllByGroupId lists the board with pinned posts first, expired posts are hidden unless asked for
+func (s *Service) GetAllByGroupId(ctx context.Context, principal access.Principal, groupID uint64, filter FilterDTO) (AnnouncementsPageDTO, error) {
+	ctx, span := tracing.Start(ctx, "announcement.Service.GetAllByGroupId")
+	defer span.End()
+
+	if _, err := s.groupService.AuthorizeRead(ctx, principal, access.AnnouncementsWrite, groupID); err != nil {
+		return AnnouncementsPageDTO{}, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageLimit
+	}
+
+	filter.Limit = min(filter.Limit, maxPageLimit)
+	filter.Offset = max(filter.Offset, 0)
+	filter.Now = time.Now()
+
+	announcements, total, err := s.repo.GetAllByGroupId(ctx, groupID, principal.UserID, filter)
+	if err != nil {
+		return AnnouncementsPageDTO{}, fmt.Errorf("failed to get announcements: %w", err)
+	}
+
+	return AnnouncementsPageDTO{
+		Announcements: announcements,
+		Total:         total,
+		Limit:         filter.Limit,
+		Offset:        filter.Offset,
+	}, nil
+}
+
+// MarkRead records a read receipt of the caller, marking an announcement twice is not an error
+func (s *Service) MarkRead(ctx context.Context, principal access.Principal, groupID, announcementID uint64) error {
+	ctx, span := tracing.Start(ctx, "announcement.Service.MarkRead")
+	defer span.End()
+
+	if _, err := s.groupService.AuthorizeRead(ctx, principal, access.AnnouncementsWrite, groupID); err != nil {
+		return err
+	}
+
+	if _, err := s.getById(ctx, groupID, announcementID); err != nil {
+		return err
+	}
+
+	if err := s.repo.MarkRead(ctx, announcementID, principal.UserID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark announcement as read: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) getById(ctx context.Context, groupID, announcementID uint64) (Announcement, error) {
+	entity, err := s.repo.GetById(ctx, announcementID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Announcement{}, domainErr.ErrAnnouncementNotFound
+		}
+
+		return Announcement{}, fmt.Errorf("failed to get announcement: %w", err)
+	}
+
+	if entity.GroupID != groupID {
+		return Announcement{}, domainErr.ErrAnnouncementNotFound
+	}
+
+	return entity, nil
+}
@@ -0,0 +1,91 @@
+package attendance
+
+import "time"
+
+type MarkDTO struct {
+	UserID uint64
+	Status string
+}
+
+// MarkLessonDTO marks members at the lesson of ScheduleID held on LessonDate, members left out are not changed
+type MarkLessonDTO struct {
+	GroupID    uint64
+	ScheduleID uint64
+	LessonDate time.Time
+	Marks      []MarkDTO
+}
+
+// SheetEntryDTO is a member of the group at a lesson occurrence, Status is nil until the member is marked
+type SheetEntryDTO struct {
+	UserID   uint64
+	FullName *string
+	Email    *string
+	Status   *string
+}
+
+// DetailsRecordDTO is a record with the lesson it was taken at
+type DetailsRecordDTO struct {
+	Record
+	SubjectName string
+	StartTime   string
+}
+
+// SummaryDTO counts the records of a member for a subject
+type SummaryDTO struct {
+	UserID      uint64
+	FullName    *string
+	Email       *string
+	SubjectName string
+	Present     int
+	Absent      int
+	Excused     int
+}
+
+func (s SummaryDTO) Total() int {
+	return s.Present + s.Absent + s.Excused
+}
+
+// FilterDTO selects records of lessons held from From to To inclusive, empty fields match everything
+type FilterDTO struct {
+	From        *time.Time
+	To          *time.Time
+	SubjectName string
+}
+
+// OpenWindowDTO opens check-in to the lesson of ScheduleID held on LessonDate for Duration,
+// Radius is optional
+type OpenWindowDTO struct {
+	GroupID    uint64
+	ScheduleID uint64
+	LessonDate time.Time
+	Duration   time.Duration
+	Radius     *int
+}
+
+// CodeDTO is the code of an open window valid until ExpiresAt, Payload carries the window and
+// the code for a QR code
+type CodeDTO struct {
+	WindowID   uint64
+	ScheduleID uint64
+	LessonDate time.Time
+	Code       string
+	Payload    string
+	ExpiresAt  time.Time
+	ClosesAt   time.Time
+}
+
+// CheckInDTO is a code submitted by a member, WindowID is known when the code comes from a QR code,
+// the location is required by windows with a radius
+type CheckInDTO struct {
+	WindowID  *uint64
+	Code      string
+	Latitude  *float64
+	Longitude *float64
+}
+
+// CheckInResultDTO is the lesson occurrence the member checked in to
+type CheckInResultDTO struct {
+	ScheduleID  uint64
+	SubjectName string
+	LessonDate  time.Time
+}
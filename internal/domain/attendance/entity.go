@@ -0,0 +1,47 @@
+package attendance
+
+import "time"
+
+const (
+	Present = "present"
+	Absent  = "absent"
+	Excused = "excused"
+)
+
+const (
+	SourceManual  = "manual"
+	SourceCheckin = "checkin"
+)
+
+// Record is the attendance of one member at a lesson occurrence, the lesson of ScheduleID held on LessonDate
+type Record struct {
+	RecordID   uint64
+	GroupID    uint64
+	ScheduleID uint64
+	UserID     uint64
+	LessonDate time.Time
+	Status     string
+	Source     string
+	MarkedBy   *uint64
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Window lets members check in to a lesson occurrence themselves from OpensAt until ClosesAt by a code
+// derived from Secret, Radius limits the distance to the building of the lesson in meters
+type Window struct {
+	WindowID   uint64
+	GroupID    uint64
+	ScheduleID uint64
+	LessonDate time.Time
+	Secret     []byte
+	Radius     *int
+	OpenedBy   *uint64
+	OpensAt    time.Time
+	ClosesAt   time.Time
+	CreatedAt  time.Time
+}
+
+func (w Window) IsOpen(t time.Time) bool {
+	return !t.Before(w.OpensAt) && t.Before(w.ClosesAt)
+}
@@ -0,0 +1,560 @@
+package attendance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/lockout"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/geo"
+	"github.com/tclutin/classflow-api/pkg/otp"
+	"github.com/tclutin/classflow-api/pkg/tracing"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultWindow is how long check-in stays open when no duration is given
+	DefaultWindow = 10 * time.Minute
+	MaxWindow     = 2 * time.Hour
+)
+
+// codes rotate every 30 seconds, the code of the previous period is still accepted
+var codes = otp.Generator{
+	Digits: 6,
+	Period: 30 * time.Second,
+}
+
+const codeSkew = 1
+
+type GroupService interface {
+	Authorize(ctx context.Context, principal access.Principal, permission string, groupID uint64) (group.Group, error)
+}
+
+type ScheduleService interface {
+	GetById(ctx context.Context, scheduleID uint64) (schedule.Schedule, error)
+	OccursOn(lesson schedule.Schedule, date time.Time) bool
+}
+
+type EduService interface {
+	GetBuildingById(ctx context.Context, buildingID uint64) (edu.Building, error)
+}
+
+type LockoutService interface {
+	Check(ctx context.Context, keys ...lockout.Key) error
+	RegisterFailure(ctx context.Context, keys ...lockout.Key) error
+	Reset(ctx context.Context, keys ...lockout.Key) error
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...postgresql.TxOption) error
+}
+
+type MemberRepository interface {
+	GetGroupIdByUserId(ctx context.Context, userID uint64) (uint64, error)
+}
+
+type Repository interface {
+	Upsert(ctx context.Context, records []Record) error
+	GetSheet(ctx context.Context, groupID, scheduleID uint64, lessonDate time.Time) ([]SheetEntryDTO, error)
+	GetAllByUserId(ctx context.Context, userID uint64, filter FilterDTO) ([]DetailsRecordDTO, error)
+	GetSummary(ctx context.Context, groupID uint64, filter FilterDTO) ([]SummaryDTO, error)
+	CheckIn(ctx context.Context, record Record) error
+	OpenWindow(ctx context.Context, window Window) (uint64, error)
+	CloseWindow(ctx context.Context, windowID uint64, closesAt time.Time) error
+	GetWindowById(ctx context.Context, windowID uint64) (Window, error)
+	GetOpenWindowsByGroupId(ctx context.Context, groupID uint64, at time.Time) ([]Window, error)
+}
+
+type Service struct {
+	groupService    GroupService
+	scheduleService ScheduleService
+	eduService      EduService
+	lockoutService  LockoutService
+	auditService    AuditService
+	txManager       TxManager
+	memberRepo      MemberRepository
+	repo            Repository
+}
+
+func NewService(
+	repo Repository,
+	txManager TxManager,
+	memberRepo MemberRepository,
+	groupService GroupService,
+	scheduleService ScheduleService,
+	eduService EduService,
+	lockoutService LockoutService,
+	auditService AuditService,
+) *Service {
+
+	return &Service{
+		groupService:    groupService,
+		scheduleService: scheduleService,
+		eduService:      eduService,
+		lockoutService:  lockoutService,
+		auditService:    auditService,
+		txManager:       txManager,
+		memberRepo:      memberRepo,
+		repo:            repo,
+	}
+}
+
+// Mark sets the status of the members at the lesson occurrence, marking a member again overwrites the status
+func (s *Service) Mark(ctx context.Context, principal access.Principal, dto MarkLessonDTO) error {
+	ctx, span := tracing.Start(ctx, "attendance.Service.Mark")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.AttendanceWrite, dto.GroupID); err != nil {
+		return err
+	}
+
+	lessonDate := schedule.Date(dto.LessonDate)
+
+	if err := s.checkLesson(ctx, dto.GroupID, dto.ScheduleID, lessonDate); err != nil {
+		return err
+	}
+
+	if lessonDate.After(schedule.Date(time.Now())) {
+		return domainErr.ErrLessonNotHeld
+	}
+
+	now := time.Now()
+	records := make([]Record, 0, len(dto.Marks))
+
+	for _, mark := range dto.Marks {
+		if err := s.checkMember(ctx, dto.GroupID, mark.UserID); err != nil {
+			return err
+		}
+
+		records = append(records, Record{
+			GroupID:    dto.GroupID,
+			ScheduleID: dto.ScheduleID,
+			UserID:     mark.UserID,
+			LessonDate: lessonDate,
+			Status:     mark.Status,
+			Source:     SourceManual,
+			MarkedBy:   &principal.UserID,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Upsert(ctx, records); err != nil {
+			return fmt.Errorf("failed to mark attendance: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.AttendanceMark,
+			TargetType: audit.TargetGroup,
+			TargetID:   dto.GroupID,
+			After: map[string]any{
+				"schedule_id": dto.ScheduleID,
+				"lesson_date": lessonDate,
+				"marks":       dto.Marks,
+			},
+		})
+	})
+}
+
+// GetSheet lists every member of the group with the status at the lesson occurrence
+func (s *Service) GetSheet(ctx context.Context, principal access.Principal, groupID, scheduleID uint64, lessonDate time.Time) ([]SheetEntryDTO, error) {
+	ctx, span := tracing.Start(ctx, "attendance.Service.GetSheet")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.AttendanceWrite, groupID); err != nil {
+		return nil, err
+	}
+
+	lessonDate = schedule.Date(lessonDate)
+
+	if err := s.checkLesson(ctx, groupID, scheduleID, lessonDate); err != nil {
+		return nil, err
+	}
+
+	sheet, err := s.repo.GetSheet(ctx, groupID, scheduleID, lessonDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance sheet: %w", err)
+	}
+
+	return sheet, nil
+}
+
+// GetAllByUserId returns the own records of the user ordered by lesson date
+func (s *Service) GetAllByUserId(ctx context.Context, userID uint64, filter FilterDTO) ([]DetailsRecordDTO, error) {
+	ctx, span := tracing.Start(ctx, "attendance.Service.GetAllByUserId")
+	defer span.End()
+
+	records, err := s.repo.GetAllByUserId(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+
+	return records, nil
+}
+
+// GetSummary counts the records of the group per member and subject, it backs the CSV export
+func (s *Service) GetSummary(ctx context.Context, principal access.Principal, groupID uint64, filter FilterDTO) ([]SummaryDTO, error) {
+	ctx, span := tracing.Start(ctx, "attendance.Service.GetSummary")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.AttendanceExport, groupID); err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repo.GetSummary(ctx, groupID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// OpenWindow opens check-in to a lesson held today and returns its first code, opening it again
+// replaces the window with a new secret so earlier codes stop working
+func (s *Service) OpenWindow(ctx context.Context, principal access.Principal, dto OpenWindowDTO) (CodeDTO, error) {
+	ctx, span := tracing.Start(ctx, "attendance.Service.OpenWindow")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.AttendanceWrite, dto.GroupID); err != nil {
+		return CodeDTO{}, err
+	}
+
+	lessonDate := schedule.Date(dto.LessonDate)
+
+	if err := s.checkLesson(ctx, dto.GroupID, dto.ScheduleID, lessonDate); err != nil {
+		return CodeDTO{}, err
+	}
+
+	now := time.Now()
+
+	if !lessonDate.Equal(schedule.Date(now)) {
+		return CodeDTO{}, domainErr.ErrLessonNotToday
+	}
+
+	if dto.Duration <= 0 {
+		dto.Duration = DefaultWindow
+	}
+
+	secret, err := otp.NewSecret()
+	if err != nil {
+		return CodeDTO{}, err
+	}
+
+	window := Window{
+		GroupID:    dto.GroupID,
+		ScheduleID: dto.ScheduleID,
+		LessonDate: lessonDate,
+		Secret:     secret,
+		Radius:     dto.Radius,
+		OpenedBy:   &principal.UserID,
+		OpensAt:    now,
+		ClosesAt:   now.Add(min(dto.Duration, MaxWindow)),
+		CreatedAt:  now,
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		window.WindowID, err = s.repo.OpenWindow(ctx, window)
+		if err != nil {
+			return fmt.Errorf("failed to open check-in: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.AttendanceCheckinOpen,
+			TargetType: audit.TargetGroup,
+			TargetID:   dto.GroupID,
+			After: map[string]any{
+				"window_id":   window.WindowID,
+				"schedule_id": window.ScheduleID,
+				"lesson_date": window.LessonDate,
+				"closes_at":   window.ClosesAt,
+				"radius":      window.Radius,
+			},
+		})
+	})
+	if err != nil {
+		return CodeDTO{}, err
+	}
+
+	return codeOf(window, now), nil
+}
+
+// GetCode returns the current code of an open window, the screen showing the code polls it
+func (s *Service) GetCode(ctx context.Context, principal access.Principal, groupID, windowID uint64) (CodeDTO, error) {
+	ctx, span := tracing.Start(ctx, "attendance.Service.GetCode")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.AttendanceWrite, groupID); err != nil {
+		return CodeDTO{}, err
+	}
+
+	window, err := s.getWindow(ctx, groupID, windowID)
+	if err != nil {
+		return CodeDTO{}, err
+	}
+
+	now := time.Now()
+
+	if !window.IsOpen(now) {
+		return CodeDTO{}, domainErr.ErrCheckinClosed
+	}
+
+	return codeOf(window, now), nil
+}
+
+// CloseWindow ends check-in before the window runs out
+func (s *Service) CloseWindow(ctx context.Context, principal access.Principal, groupID, windowID uint64) error {
+	ctx, span := tracing.Start(ctx, "attendance.Service.CloseWindow")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.AttendanceWrite, groupID); err != nil {
+		return err
+	}
+
+	window, err := s.getWindow(ctx, groupID, windowID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if !window.IsOpen(now) {
+		return domainErr.ErrCheckinClosed
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err = s.repo.CloseWindow(ctx, windowID, now); err != nil {
+			return fmt.Errorf("failed to close check-in: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.AttendanceCheckinClose,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			Before: map[string]any{
+				"window_id": windowID,
+				"closes_at": window.ClosesAt,
+			},
+			After: map[string]any{
+				"window_id": windowID,
+				"closes_at": now,
+			},
+		})
+	})
+}
+
+// CheckIn marks the caller present at the lesson of the open window of their group the code belongs to.
+// A mark already set for the lesson, including one set by the leader, is kept
+func (s *Service) CheckIn(ctx context.Context, principal access.Principal, dto CheckInDTO) (CheckInResultDTO, error) {
+	ctx, span := tracing.Start(ctx, "attendance.Service.CheckIn")
+	defer span.End()
+
+	groupID, err := s.memberRepo.GetGroupIdByUserId(ctx, principal.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return CheckInResultDTO{}, domainErr.ErrMemberNotFound
+		}
+
+		return CheckInResultDTO{}, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	now := time.Now()
+
+	windows, err := s.repo.GetOpenWindowsByGroupId(ctx, groupID, now)
+	if err != nil {
+		return CheckInResultDTO{}, fmt.Errorf("failed to get check-in windows: %w", err)
+	}
+
+	if len(windows) == 0 {
+		return CheckInResultDTO{}, domainErr.ErrCheckinClosed
+	}
+
+	// wrong codes are counted per user and window, so a code cannot be guessed while the window is open
+	keys := checkInKeys(principal.UserID, windows, dto)
+
+	if err = s.lockoutService.Check(ctx, keys...); err != nil {
+		return CheckInResultDTO{}, err
+	}
+
+	window, ok := matchWindow(windows, dto, now)
+	if !ok {
+		if err = s.lockoutService.RegisterFailure(ctx, keys...); err != nil {
+			return CheckInResultDTO{}, err
+		}
+
+		return CheckInResultDTO{}, domainErr.ErrInvalidCheckinCode
+	}
+
+	if err = s.lockoutService.Reset(ctx, lockout.CheckIn(principal.UserID, window.WindowID)); err != nil {
+		return CheckInResultDTO{}, err
+	}
+
+	lesson, err := s.scheduleService.GetById(ctx, window.ScheduleID)
+	if err != nil {
+		return CheckInResultDTO{}, err
+	}
+
+	if window.Radius != nil {
+		if err = s.checkLocation(ctx, lesson.BuildingsID, *window.Radius, dto); err != nil {
+			return CheckInResultDTO{}, err
+		}
+	}
+
+	err = s.repo.CheckIn(ctx, Record{
+		GroupID:    window.GroupID,
+		ScheduleID: window.ScheduleID,
+		UserID:     principal.UserID,
+		LessonDate: window.LessonDate,
+		Status:     Present,
+		Source:     SourceCheckin,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return CheckInResultDTO{}, fmt.Errorf("failed to check in: %w", err)
+	}
+
+	return CheckInResultDTO{
+		ScheduleID:  window.ScheduleID,
+		SubjectName: lesson.SubjectName,
+		LessonDate:  window.LessonDate,
+	}, nil
+}
+
+func (s *Service) checkLocation(ctx context.Context, buildingID uint64, radius int, dto CheckInDTO) error {
+	if dto.Latitude == nil || dto.Longitude == nil {
+		return domainErr.ErrLocationRequired
+	}
+
+	building, err := s.eduService.GetBuildingById(ctx, buildingID)
+	if err != nil {
+		return err
+	}
+
+	if geo.Distance(*dto.Latitude, *dto.Longitude, building.Latitude, building.Longitude) > float64(radius) {
+		return domainErr.ErrCheckinTooFar
+	}
+
+	return nil
+}
+
+func (s *Service) getWindow(ctx context.Context, groupID, windowID uint64) (Window, error) {
+	window, err := s.repo.GetWindowById(ctx, windowID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return window, domainErr.ErrCheckinNotFound
+		}
+
+		return window, fmt.Errorf("failed to get check-in window: %w", err)
+	}
+
+	if window.GroupID != groupID {
+		return Window{}, domainErr.ErrCheckinNotFound
+	}
+
+	return window, nil
+}
+
+// checkLesson makes sure the lesson belongs to the group and takes place on the date
+func (s *Service) checkLesson(ctx context.Context, groupID, scheduleID uint64, lessonDate time.Time) error {
+	lesson, err := s.scheduleService.GetById(ctx, scheduleID)
+	if err != nil {
+		return err
+	}
+
+	if lesson.GroupID != groupID {
+		return domainErr.ErrLessonNotFound
+	}
+
+	if !s.scheduleService.OccursOn(lesson, lessonDate) {
+		return domainErr.ErrLessonNotOnDate
+	}
+
+	return nil
+}
+
+func (s *Service) checkMember(ctx context.Context, groupID, userID uint64) error {
+	memberOf, err := s.memberRepo.GetGroupIdByUserId(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+
+		return fmt.Errorf("failed to get member: %w", err)
+	}
+
+	if memberOf != groupID {
+		return domainErr.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// checkInKeys returns the lockout keys of the windows the code is checked against
+func checkInKeys(userID uint64, windows []Window, dto CheckInDTO) []lockout.Key {
+	var keys []lockout.Key
+
+	for _, window := range windows {
+		if dto.WindowID == nil || *dto.WindowID == window.WindowID {
+			keys = append(keys, lockout.CheckIn(userID, window.WindowID))
+		}
+	}
+
+	return keys
+}
+
+// matchWindow finds the window the code is valid for, only the window from the QR code is tried when it is known
+func matchWindow(windows []Window, dto CheckInDTO, now time.Time) (Window, bool) {
+	for _, window := range windows {
+		if dto.WindowID != nil && *dto.WindowID != window.WindowID {
+			continue
+		}
+
+		if codes.Verify(window.Secret, dto.Code, now, codeSkew) {
+			return window, true
+		}
+	}
+
+	return Window{}, false
+}
+
+func codeOf(window Window, now time.Time) CodeDTO {
+	code := codes.Code(window.Secret, now)
+
+	expiresAt := codes.Expires(now)
+	if expiresAt.After(window.ClosesAt) {
+		expiresAt = window.ClosesAt
+	}
+
+	return CodeDTO{
+		WindowID:   window.WindowID,
+		ScheduleID: window.ScheduleID,
+		LessonDate: window.LessonDate,
+		Code:       code,
+		Payload:    payload(window.WindowID, code),
+		ExpiresAt:  expiresAt,
+		ClosesAt:   window.ClosesAt,
+	}
+}
+
+// payload is the text of the QR code, a link the bot understands
+func payload(windowID uint64, code string) string {
+	query := url.Values{}
+	query.Set("window_id", strconv.FormatUint(windowID, 10))
+	query.Set("code", code)
+
+	return "classflow://checkin?" + query.Encode()
+}
@@ -0,0 +1,91 @@
+package attendance_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tclutin/classflow-api/internal/config"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/lockout"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/internal/repository/memory"
+	"testing"
+	"time"
+)
+
+const maxAttempts = 3
+
+func TestService_CheckInLockout(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+
+	cfg := &config.Config{
+		Lockout: config.Lockout{
+			MaxAttempts:   maxAttempts,
+			IPMaxAttempts: 100,
+			Window:        15 * time.Minute,
+			BaseDelay:     30 * time.Second,
+			MaxDelay:      time.Hour,
+		},
+	}
+
+	service := attendance.NewService(
+		repos.Attendance,
+		memory.NewTxManager(store),
+		repos.Member,
+		nil,
+		schedule.NewService(repos.Schedule, schedule.Term{}),
+		edu.NewService(repos.Edu),
+		lockout.NewService(repos.Lockout, cfg),
+		audit.NewService(repos.Audit))
+
+	const groupID = 1
+
+	now := time.Now()
+
+	_, err := repos.Attendance.OpenWindow(ctx, attendance.Window{
+		GroupID:    groupID,
+		ScheduleID: 1,
+		LessonDate: schedule.Date(now),
+		Secret:     []byte("secret"),
+		OpensAt:    now.Add(-time.Minute),
+		ClosesAt:   now.Add(time.Hour),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	student := func(userID uint64) access.Principal {
+		if _, err := repos.Member.Create(ctx, userID, groupID); err != nil {
+			t.Fatal(err)
+		}
+
+		return access.Principal{UserID: userID}
+	}
+
+	guesser, classmate := student(100), student(200)
+
+	wrong := attendance.CheckInDTO{Code: "wrong"}
+
+	for range maxAttempts {
+		if _, err = service.CheckIn(ctx, guesser, wrong); !errors.Is(err, domainErr.ErrInvalidCheckinCode) {
+			t.Fatalf("expected %v before the lockout, got %v", domainErr.ErrInvalidCheckinCode, err)
+		}
+	}
+
+	var locked *lockout.LockedError
+
+	if _, err = service.CheckIn(ctx, guesser, wrong); !errors.As(err, &locked) || locked.RetryAfter <= 0 {
+		t.Fatalf("expected the user to be locked out of the window, got %v", err)
+	}
+
+	// the failures of one user do not lock the others out
+	if _, err = service.CheckIn(ctx, classmate, wrong); !errors.Is(err, domainErr.ErrInvalidCheckinCode) {
+		t.Fatalf("expected %v for another user, got %v", domainErr.ErrInvalidCheckinCode, err)
+	}
+}
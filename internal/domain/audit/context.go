@@ -0,0 +1,28 @@
+package audit
+
+import "context"
+
+type metadataKey struct{}
+
+// Metadata describes who made the request, it is attached to the context by the HTTP layer
+type Metadata struct {
+	ActorID   *uint64
+	RequestID string
+	IP        string
+}
+
+func WithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+func WithActor(ctx context.Context, actorID uint64) context.Context {
+	metadata := MetadataFromContext(ctx)
+	metadata.ActorID = &actorID
+
+	return WithMetadata(ctx, metadata)
+}
+
+func MetadataFromContext(ctx context.Context) Metadata {
+	metadata, _ := ctx.Value(metadataKey{}).(Metadata)
+	return metadata
+}
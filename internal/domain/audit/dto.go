@@ -0,0 +1,21 @@
+package audit
+
+import "time"
+
+// Entry describes a change, Before and After are snapshots serialized to JSON, nil when absent
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   uint64
+	Before     any
+	After      any
+}
+
+type FilterDTO struct {
+	ActorID    *uint64
+	TargetType string
+	TargetID   *uint64
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	GroupCreate            = "group.create"
+	GroupUpdate            = "group.update"
+	GroupDelete            = "group.delete"
+	GroupJoin              = "group.join"
+	GroupLeave             = "group.leave"
+	GroupScheduleUpload    = "group.schedule.upload"
+	GroupExamsUpload       = "group.exams.upload"
+	GroupExamUpdate        = "group.exam.update"
+	GroupExamDelete        = "group.exam.delete"
+	GroupWaitlistJoin      = "group.waitlist.join"
+	GroupWaitlistLeave     = "group.waitlist.leave"
+	GroupWaitlistPromote   = "group.waitlist.promote"
+	AnnouncementCreate     = "announcement.create"
+	AnnouncementUpdate     = "announcement.update"
+	AnnouncementDelete     = "announcement.delete"
+	HomeworkCreate         = "homework.create"
+	HomeworkUpdate         = "homework.update"
+	HomeworkDelete         = "homework.delete"
+	AttendanceMark         = "attendance.mark"
+	AttendanceCheckinOpen  = "attendance.checkin.open"
+	AttendanceCheckinClose = "attendance.checkin.close"
+	AdminFacultyAssign     = "admin.faculty.assign"
+	AdminFacultyRevoke     = "admin.faculty.revoke"
+	UserCreate             = "user.create"
+	UserUpdate             = "user.update"
+)
+
+const (
+	TargetGroup        = "group"
+	TargetUser         = "user"
+	TargetAnnouncement = "announcement"
+	TargetHomework     = "homework"
+)
+
+type Record struct {
+	RecordID   uint64
+	ActorID    *uint64
+	Action     string
+	TargetType string
+	TargetID   uint64
+	RequestID  *string
+	IP         *string
+	Before     json.RawMessage
+	After      json.RawMessage
+	CreatedAt  time.Time
+}
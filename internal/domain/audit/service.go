@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/tclutin/classflow-api/pkg/tracing"
+	"time"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+type Repository interface {
+	Create(ctx context.Context, record Record) error
+	GetAll(ctx context.Context, filter FilterDTO) ([]Record, error)
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Record writes the entry, call it within the transaction of the change it describes
+func (s *Service) Record(ctx context.Context, entry Entry) error {
+	ctx, span := tracing.Start(ctx, "audit.Service.Record")
+	defer span.End()
+
+	before, err := snapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := snapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	metadata := MetadataFromContext(ctx)
+
+	record := Record{
+		ActorID:    metadata.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		RequestID:  optional(metadata.RequestID),
+		IP:         optional(metadata.IP),
+		Before:     before,
+		After:      after,
+		CreatedAt:  time.Now(),
+	}
+
+	if err = s.repo.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to create audit record: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetAll(ctx context.Context, filter FilterDTO) ([]Record, error) {
+	ctx, span := tracing.Start(ctx, "audit.Service.GetAll")
+	defer span.End()
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLimit
+	}
+
+	filter.Limit = min(filter.Limit, maxLimit)
+
+	return s.repo.GetAll(ctx, filter)
+}
+
+func snapshot(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit snapshot: %w", err)
+	}
+
+	return data, nil
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -13,6 +13,7 @@ type SignUpDTO struct {
 type LogInDTO struct {
 	Email    string
 	Password string
+	IP       string
 }
 
 type SignUpWithTelegramDTO struct {
@@ -23,4 +24,5 @@ type SignUpWithTelegramDTO struct {
 
 type LogInWithTelegramDTO struct {
 	TelegramChatID int64
+	IP             string
 }
@@ -2,13 +2,17 @@ package auth
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
 	"github.com/tclutin/classflow-api/internal/config"
 	"github.com/tclutin/classflow-api/internal/domain/errors"
 	domenErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/lockout"
 	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/internal/metric"
 	"github.com/tclutin/classflow-api/pkg/hash"
 	"github.com/tclutin/classflow-api/pkg/jwt"
+	"github.com/tclutin/classflow-api/pkg/tracing"
 	"time"
 )
 
@@ -19,26 +23,38 @@ type UserService interface {
 	Create(ctx context.Context, user user.User) (uint64, error)
 }
 
+type LockoutService interface {
+	Check(ctx context.Context, keys ...lockout.Key) error
+	RegisterFailure(ctx context.Context, keys ...lockout.Key) error
+	Reset(ctx context.Context, keys ...lockout.Key) error
+}
+
 type Service struct {
-	userService  UserService
-	tokenManager jwt.Manager
-	cfg          *config.Config
+	userService    UserService
+	lockoutService LockoutService
+	tokenManager   jwt.Manager
+	cfg            *config.Config
 }
 
 func NewService(
 	userService UserService,
+	lockoutService LockoutService,
 	tokenManager jwt.Manager,
 	cfg *config.Config,
 ) *Service {
 
 	return &Service{
-		userService:  userService,
-		tokenManager: tokenManager,
-		cfg:          cfg,
+		userService:    userService,
+		lockoutService: lockoutService,
+		tokenManager:   tokenManager,
+		cfg:            cfg,
 	}
 }
 
 func (s *Service) SignUp(ctx context.Context, dto SignUpDTO) (TokenDTO, error) {
+	ctx, span := tracing.Start(ctx, "auth.Service.SignUp")
+	defer span.End()
+
 	_, err := s.userService.GetByEmail(ctx, dto.Email)
 	if err == nil {
 		return TokenDTO{}, domenErr.ErrUserAlreadyExists
@@ -66,6 +82,8 @@ func (s *Service) SignUp(ctx context.Context, dto SignUpDTO) (TokenDTO, error) {
 		return TokenDTO{}, err
 	}
 
+	metric.IncSignupsCounter("email")
+
 	token, err := s.tokenManager.NewToken(userID, s.cfg.JWT.Expire)
 	if err != nil {
 		return TokenDTO{}, fmt.Errorf("failed to create access token: %w", err)
@@ -78,15 +96,39 @@ func (s *Service) SignUp(ctx context.Context, dto SignUpDTO) (TokenDTO, error) {
 }
 
 func (s *Service) LogIn(ctx context.Context, dto LogInDTO) (TokenDTO, error) {
+	ctx, span := tracing.Start(ctx, "auth.Service.LogIn")
+	defer span.End()
+
+	ipKey, emailKey := lockout.IP(dto.IP), lockout.Email(dto.Email)
+
+	if err := s.lockoutService.Check(ctx, ipKey, emailKey); err != nil {
+		return TokenDTO{}, err
+	}
+
 	usr, err := s.userService.GetByEmail(ctx, dto.Email)
 	if err != nil {
+		// an unknown email counts like a wrong password, so the lockout does not tell which emails exist
+		if stdErrors.Is(err, errors.ErrUserNotFound) {
+			if lockErr := s.lockoutService.RegisterFailure(ctx, ipKey, emailKey); lockErr != nil {
+				return TokenDTO{}, lockErr
+			}
+		}
+
 		return TokenDTO{}, err
 	}
 
 	if !hash.CompareBcryptHash(*usr.PasswordHash, dto.Password) {
+		if lockErr := s.lockoutService.RegisterFailure(ctx, ipKey, emailKey); lockErr != nil {
+			return TokenDTO{}, lockErr
+		}
+
 		return TokenDTO{}, errors.ErrWrongPassword
 	}
 
+	if err = s.lockoutService.Reset(ctx, emailKey); err != nil {
+		return TokenDTO{}, err
+	}
+
 	token, err := s.tokenManager.NewToken(usr.UserID, s.cfg.JWT.Expire)
 	if err != nil {
 		return TokenDTO{}, fmt.Errorf("failed to create access token: %w", err)
@@ -99,6 +141,9 @@ func (s *Service) LogIn(ctx context.Context, dto LogInDTO) (TokenDTO, error) {
 }
 
 func (s *Service) SignUpWithTelegram(ctx context.Context, dto SignUpWithTelegramDTO) (TokenDTO, error) {
+	ctx, span := tracing.Start(ctx, "auth.Service.SignUpWithTelegram")
+	defer span.End()
+
 	_, err := s.userService.GetByTelegramChatId(ctx, dto.TelegramChatID)
 	if err == nil {
 		return TokenDTO{}, errors.ErrUserAlreadyExists
@@ -123,6 +168,8 @@ func (s *Service) SignUpWithTelegram(ctx context.Context, dto SignUpWithTelegram
 		return TokenDTO{}, err
 	}
 
+	metric.IncSignupsCounter("telegram")
+
 	token, err := s.tokenManager.NewToken(userID, s.cfg.JWT.Expire)
 	if err != nil {
 		return TokenDTO{}, fmt.Errorf("failed to create access token: %w", err)
@@ -135,8 +182,28 @@ func (s *Service) SignUpWithTelegram(ctx context.Context, dto SignUpWithTelegram
 }
 
 func (s *Service) LogInWithTelegramRequest(ctx context.Context, dto LogInWithTelegramDTO) (TokenDTO, error) {
+	ctx, span := tracing.Start(ctx, "auth.Service.LogInWithTelegramRequest")
+	defer span.End()
+
+	// the IP of the bot is usually trusted and skipped, the chat key still locks out guessing of one chat
+	ipKey, chatKey := lockout.IP(dto.IP), lockout.TelegramChat(dto.TelegramChatID)
+
+	if err := s.lockoutService.Check(ctx, ipKey, chatKey); err != nil {
+		return TokenDTO{}, err
+	}
+
 	usr, err := s.userService.GetByTelegramChatId(ctx, dto.TelegramChatID)
 	if err != nil {
+		if stdErrors.Is(err, errors.ErrUserNotFound) {
+			if lockErr := s.lockoutService.RegisterFailure(ctx, ipKey, chatKey); lockErr != nil {
+				return TokenDTO{}, lockErr
+			}
+		}
+
+		return TokenDTO{}, err
+	}
+
+	if err = s.lockoutService.Reset(ctx, chatKey); err != nil {
 		return TokenDTO{}, err
 	}
 
@@ -152,6 +219,9 @@ func (s *Service) LogInWithTelegramRequest(ctx context.Context, dto LogInWithTel
 }
 
 func (s *Service) Who(ctx context.Context, userID uint64) (user.User, error) {
+	ctx, span := tracing.Start(ctx, "auth.Service.Who")
+	defer span.End()
+
 	usr, err := s.userService.GetById(ctx, userID)
 	if err != nil {
 		return usr, err
@@ -161,11 +231,14 @@ func (s *Service) Who(ctx context.Context, userID uint64) (user.User, error) {
 }
 
 func (s *Service) VerifyAndGetCredentials(ctx context.Context, token string) (user.User, error) {
+	ctx, span := tracing.Start(ctx, "auth.Service.VerifyAndGetCredentials")
+	defer span.End()
+
 	var user user.User
 
 	userID, err := s.tokenManager.ParseToken(token)
 	if err != nil {
-		return user, err
+		return user, fmt.Errorf("%w: %v", domenErr.ErrInvalidToken, err)
 	}
 
 	user, err = s.userService.GetById(ctx, userID)
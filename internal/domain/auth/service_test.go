@@ -0,0 +1,247 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/tclutin/classflow-api/internal/config"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/domain/auth"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/lockout"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/internal/repository/memory"
+	"github.com/tclutin/classflow-api/pkg/jwt"
+	"testing"
+	"time"
+)
+
+const maxAttempts = 3
+
+// setup returns an auth service on top of a fresh in-memory store together with its token manager
+func setup() (context.Context, *auth.Service, *jwt.TokenManager) {
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+
+	cfg := &config.Config{
+		JWT: config.JWT{
+			Secret: "test-secret",
+			Expire: time.Hour,
+		},
+		Lockout: config.Lockout{
+			MaxAttempts:   maxAttempts,
+			IPMaxAttempts: 100,
+			Window:        15 * time.Minute,
+			BaseDelay:     30 * time.Second,
+			MaxDelay:      time.Hour,
+		},
+	}
+
+	tokens := jwt.MustLoadTokenManager(cfg.JWT.Secret)
+	userService := user.NewService(repos.User, memory.NewTxManager(store), audit.NewService(repos.Audit))
+	service := auth.NewService(userService, lockout.NewService(repos.Lockout, cfg), tokens, cfg)
+
+	return context.Background(), service, tokens
+}
+
+// subject returns the user the access token was issued to
+func subject(t *testing.T, tokens *jwt.TokenManager, token auth.TokenDTO) uint64 {
+	t.Helper()
+
+	userID, err := tokens.ParseToken(token.AccessToken)
+	if err != nil {
+		t.Fatalf("invalid access token: %v", err)
+	}
+
+	return userID
+}
+
+func TestService_SignUp(t *testing.T) {
+	ctx, service, tokens := setup()
+
+	dto := auth.SignUpDTO{Email: "admin@classflow.test", Password: "password"}
+
+	token, err := service.SignUp(ctx, dto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := service.Who(ctx, subject(t, tokens, token))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Role != user.Admin || created.PasswordHash == nil || *created.PasswordHash == dto.Password {
+		t.Fatalf("expected an admin with a hashed password, got %+v", created)
+	}
+
+	if _, err = service.SignUp(ctx, dto); !errors.Is(err, domainErr.ErrUserAlreadyExists) {
+		t.Fatalf("expected %v on a second sign up, got %v", domainErr.ErrUserAlreadyExists, err)
+	}
+}
+
+func TestService_LogIn(t *testing.T) {
+	ctx, service, tokens := setup()
+
+	signUp, err := service.SignUp(ctx, auth.SignUpDTO{Email: "admin@classflow.test", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userID := subject(t, tokens, signUp)
+
+	tests := []struct {
+		name    string
+		dto     auth.LogInDTO
+		wantErr error
+	}{
+		{
+			name: "valid credentials",
+			dto:  auth.LogInDTO{Email: "admin@classflow.test", Password: "password", IP: "10.0.0.1"},
+		},
+		{
+			name:    "wrong password",
+			dto:     auth.LogInDTO{Email: "admin@classflow.test", Password: "wrong", IP: "10.0.0.1"},
+			wantErr: domainErr.ErrWrongPassword,
+		},
+		{
+			name:    "unknown email",
+			dto:     auth.LogInDTO{Email: "missing@classflow.test", Password: "password", IP: "10.0.0.1"},
+			wantErr: domainErr.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := service.LogIn(ctx, tt.dto)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := subject(t, tokens, token); got != userID {
+				t.Fatalf("token is issued to %d, want %d", got, userID)
+			}
+		})
+	}
+}
+
+func TestService_LogInLockout(t *testing.T) {
+	ctx, service, _ := setup()
+
+	if _, err := service.SignUp(ctx, auth.SignUpDTO{Email: "admin@classflow.test", Password: "password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	wrong := auth.LogInDTO{Email: "admin@classflow.test", Password: "wrong", IP: "10.0.0.1"}
+
+	for range maxAttempts {
+		if _, err := service.LogIn(ctx, wrong); !errors.Is(err, domainErr.ErrWrongPassword) {
+			t.Fatalf("expected %v before the lockout, got %v", domainErr.ErrWrongPassword, err)
+		}
+	}
+
+	_, err := service.LogIn(ctx, auth.LogInDTO{Email: "Admin@classflow.test", Password: "password", IP: "10.0.0.2"})
+
+	var locked *lockout.LockedError
+	if !errors.As(err, &locked) || locked.RetryAfter <= 0 {
+		t.Fatalf("expected the email to be locked out even with the right password, got %v", err)
+	}
+
+	if !errors.Is(err, domainErr.ErrTooManyAttempts) {
+		t.Fatalf("expected %v, got %v", domainErr.ErrTooManyAttempts, err)
+	}
+}
+
+func TestService_LogInLockoutUnknownEmail(t *testing.T) {
+	ctx, service, _ := setup()
+
+	// the failures come from different addresses, only the email key adds them up
+	for i := range maxAttempts {
+		_, err := service.LogIn(ctx, auth.LogInDTO{Email: "nobody@classflow.test", Password: "wrong", IP: fmt.Sprintf("10.0.0.%d", i+1)})
+		if !errors.Is(err, domainErr.ErrUserNotFound) {
+			t.Fatalf("expected %v before the lockout, got %v", domainErr.ErrUserNotFound, err)
+		}
+	}
+
+	_, err := service.LogIn(ctx, auth.LogInDTO{Email: "nobody@classflow.test", Password: "wrong", IP: "10.0.1.1"})
+	if !errors.Is(err, domainErr.ErrTooManyAttempts) {
+		t.Fatalf("expected an unknown email to be locked out like a known one, got %v", err)
+	}
+}
+
+func TestService_Telegram(t *testing.T) {
+	ctx, service, tokens := setup()
+
+	dto := auth.SignUpWithTelegramDTO{TelegramChatID: 100500, TelegramUsername: "student", Fullname: "Иванов Иван"}
+
+	signUp, err := service.SignUpWithTelegram(ctx, dto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := service.Who(ctx, subject(t, tokens, signUp))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Role != user.Student || created.TelegramChatID == nil || *created.TelegramChatID != dto.TelegramChatID {
+		t.Fatalf("expected a student with the chat id, got %+v", created)
+	}
+
+	if _, err = service.SignUpWithTelegram(ctx, dto); !errors.Is(err, domainErr.ErrUserAlreadyExists) {
+		t.Fatalf("expected %v on a second sign up, got %v", domainErr.ErrUserAlreadyExists, err)
+	}
+
+	logIn, err := service.LogInWithTelegramRequest(ctx, auth.LogInWithTelegramDTO{TelegramChatID: dto.TelegramChatID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := subject(t, tokens, logIn); got != created.UserID {
+		t.Fatalf("token is issued to %d, want %d", got, created.UserID)
+	}
+
+	_, err = service.LogInWithTelegramRequest(ctx, auth.LogInWithTelegramDTO{TelegramChatID: dto.TelegramChatID + 1})
+	if !errors.Is(err, domainErr.ErrUserNotFound) {
+		t.Fatalf("expected %v for an unknown chat, got %v", domainErr.ErrUserNotFound, err)
+	}
+}
+
+func TestService_VerifyAndGetCredentials(t *testing.T) {
+	ctx, service, tokens := setup()
+
+	signUp, err := service.SignUp(ctx, auth.SignUpDTO{Email: "admin@classflow.test", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credentials, err := service.VerifyAndGetCredentials(ctx, signUp.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if credentials.UserID != subject(t, tokens, signUp) {
+		t.Fatalf("credentials of user %d do not match the token", credentials.UserID)
+	}
+
+	if _, err = service.VerifyAndGetCredentials(ctx, "malformed"); !errors.Is(err, domainErr.ErrInvalidToken) {
+		t.Fatalf("expected %v for a malformed token, got %v", domainErr.ErrInvalidToken, err)
+	}
+
+	expired, err := tokens.NewToken(credentials.UserID, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = service.VerifyAndGetCredentials(ctx, expired); !errors.Is(err, domainErr.ErrInvalidToken) {
+		t.Fatalf("expected %v for an expired token, got %v", domainErr.ErrInvalidToken, err)
+	}
+}
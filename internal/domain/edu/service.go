@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/jackc/pgx/v5"
 	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/pkg/tracing"
 )
 
 type Repository interface {
@@ -28,22 +29,37 @@ func NewService(repo Repository) *Service {
 }
 
 func (s *Service) GetAllTypesOfSubject(ctx context.Context) ([]TypeOfSubject, error) {
+	ctx, span := tracing.Start(ctx, "edu.Service.GetAllTypesOfSubject")
+	defer span.End()
+
 	return s.repo.GetAllTypesOfSubject(ctx)
 }
 
 func (s *Service) GetAllFaculties(ctx context.Context) ([]Faculty, error) {
+	ctx, span := tracing.Start(ctx, "edu.Service.GetAllFaculties")
+	defer span.End()
+
 	return s.repo.GetAllFaculty(ctx)
 }
 
 func (s *Service) GetAllProgramsByFacultyId(ctx context.Context, facultyID uint64) ([]Program, error) {
+	ctx, span := tracing.Start(ctx, "edu.Service.GetAllProgramsByFacultyId")
+	defer span.End()
+
 	return s.repo.GetAllProgramsByFacultyId(ctx, facultyID)
 }
 
 func (s *Service) GetAllBuildings(ctx context.Context) ([]Building, error) {
+	ctx, span := tracing.Start(ctx, "edu.Service.GetAllBuildings")
+	defer span.End()
+
 	return s.repo.GetAllBuildings(ctx)
 }
 
 func (s *Service) GetBuildingById(ctx context.Context, buildingID uint64) (Building, error) {
+	ctx, span := tracing.Start(ctx, "edu.Service.GetBuildingById")
+	defer span.End()
+
 	building, err := s.repo.GetBuildingById(ctx, buildingID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -57,6 +73,9 @@ func (s *Service) GetBuildingById(ctx context.Context, buildingID uint64) (Build
 }
 
 func (s *Service) GetTypeOfSubjectById(ctx context.Context, typeOfSubjectId uint64) (TypeOfSubject, error) {
+	ctx, span := tracing.Start(ctx, "edu.Service.GetTypeOfSubjectById")
+	defer span.End()
+
 	typeOfSubject, err := s.repo.GetTypeOfSubjectById(ctx, typeOfSubjectId)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -70,6 +89,9 @@ func (s *Service) GetTypeOfSubjectById(ctx context.Context, typeOfSubjectId uint
 }
 
 func (s *Service) GetProgramById(ctx context.Context, programID uint64) (Program, error) {
+	ctx, span := tracing.Start(ctx, "edu.Service.GetProgramById")
+	defer span.End()
+
 	program, err := s.repo.GetProgramById(ctx, programID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -83,6 +105,9 @@ func (s *Service) GetProgramById(ctx context.Context, programID uint64) (Program
 }
 
 func (s *Service) GetFacultyById(ctx context.Context, facultyID uint64) (Faculty, error) {
+	ctx, span := tracing.Start(ctx, "edu.Service.GetFacultyById")
+	defer span.End()
+
 	faculty, err := s.repo.GetFacultyById(ctx, facultyID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -1,44 +1,183 @@
 package errors
 
-import "errors"
+import "net/http"
+
+// Error is a domain error with a stable machine-readable code and the HTTP status it maps to,
+// the message is the English fallback, localized texts are looked up by code
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Fields  []FieldError
+}
+
+// FieldError describes a single invalid input field
+type FieldError struct {
+	Field   string
+	Code    string
+	Param   string
+	Message string
+}
+
+func New(code string, status int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is matches errors by code, so copies made by WithFields still match their sentinel
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return e.Code == t.Code
+}
+
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	err := *e
+	err.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+
+	return &err
+}
 
 var (
+	// ErrInternal Common
+	ErrInternal = New("internal_error", http.StatusInternalServerError, "An error occurred on the server. Please try again later.")
+
+	// ErrInvalidRequest Common
+	ErrInvalidRequest = New("invalid_request", http.StatusBadRequest, "invalid request")
+
+	// ErrValidation Common
+	ErrValidation = New("validation_failed", http.StatusBadRequest, "request validation failed")
+
+	// ErrRouteNotFound Common
+	ErrRouteNotFound = New("route_not_found", http.StatusNotFound, "route not found")
+
+	// ErrUnauthorized Common
+	ErrUnauthorized = New("unauthorized", http.StatusUnauthorized, "authentication is required")
+
+	// ErrRateLimitExceeded Common
+	ErrRateLimitExceeded = New("rate_limit_exceeded", http.StatusTooManyRequests, "rate limit exceeded, try again later")
+
 	// ErrUserNotFound UserService
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound = New("user_not_found", http.StatusNotFound, "user not found")
 
 	// ErrUserAlreadyExists UserService
-	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrUserAlreadyExists = New("user_already_exists", http.StatusConflict, "user already exists")
 
 	// ErrWrongPassword AuthService
-	ErrWrongPassword = errors.New("wrong password")
+	ErrWrongPassword = New("wrong_password", http.StatusBadRequest, "wrong password")
+
+	// ErrInvalidToken AuthService
+	ErrInvalidToken = New("invalid_token", http.StatusUnauthorized, "token is invalid or expired")
+
+	// ErrTooManyAttempts AuthService
+	ErrTooManyAttempts = New("too_many_attempts", http.StatusTooManyRequests, "too many failed login attempts, try again later")
 
 	// ErrProgramNotFound EduService
-	ErrProgramNotFound = errors.New("program not found")
+	ErrProgramNotFound = New("program_not_found", http.StatusNotFound, "program not found")
 
 	// ErrFacultyNotFound EduService
-	ErrFacultyNotFound = errors.New("faculty not found")
+	ErrFacultyNotFound = New("faculty_not_found", http.StatusNotFound, "faculty not found")
 
 	// ErrTypeOfSubjectNotFound EduService
-	ErrTypeOfSubjectNotFound = errors.New("type of subject not found")
+	ErrTypeOfSubjectNotFound = New("type_of_subject_not_found", http.StatusNotFound, "type of subject not found")
 
 	// ErrBuildingNotFound EduService
-	ErrBuildingNotFound = errors.New("building not found")
+	ErrBuildingNotFound = New("building_not_found", http.StatusNotFound, "building not found")
 
 	// ErrGroupNotFound GroupService
-	ErrGroupNotFound = errors.New("group not found")
+	ErrGroupNotFound = New("group_not_found", http.StatusNotFound, "group not found")
 
 	// ErrGroupAlreadyExists GroupService
-	ErrGroupAlreadyExists = errors.New("group already exists with this shortname")
+	ErrGroupAlreadyExists = New("group_already_exists", http.StatusConflict, "group already exists with this shortname")
 
 	// ErrFacultyProgramIdMismatch GroupService
-	ErrFacultyProgramIdMismatch = errors.New("faculty and program id does not match")
+	ErrFacultyProgramIdMismatch = New("faculty_program_mismatch", http.StatusBadRequest, "faculty and program id does not match")
 
 	// ErrAlreadyInGroup GroupService
-	ErrAlreadyInGroup = errors.New("you are already in a group")
+	ErrAlreadyInGroup = New("already_in_group", http.StatusConflict, "you are already in a group")
 
 	// ErrGroupAlreadyHasSchedule GroupService
-	ErrGroupAlreadyHasSchedule = errors.New("group already has schedule")
+	ErrGroupAlreadyHasSchedule = New("group_already_has_schedule", http.StatusConflict, "group already has schedule")
+
+	// ErrGroupFull GroupService
+	ErrGroupFull = New("group_full", http.StatusConflict, "group has reached its capacity, join the waitlist instead")
+
+	// ErrGroupNotFull GroupService
+	ErrGroupNotFull = New("group_not_full", http.StatusConflict, "group has free places, join it directly")
+
+	// ErrCapacityBelowMembers GroupService
+	ErrCapacityBelowMembers = New("capacity_below_members", http.StatusConflict, "capacity is less than the current number of people")
+
+	// ErrAlreadyInWaitlist GroupService
+	ErrAlreadyInWaitlist = New("already_in_waitlist", http.StatusConflict, "you are already in a waitlist")
+
+	// ErrWaitlistEntryNotFound GroupService
+	ErrWaitlistEntryNotFound = New("waitlist_entry_not_found", http.StatusNotFound, "waitlist entry not found")
+
+	// ErrExamNotFound GroupService
+	ErrExamNotFound = New("exam_not_found", http.StatusNotFound, "exam not found")
 
 	//ErrMemberNotFound GroupService
-	ErrMemberNotFound = errors.New("member not found")
+	ErrMemberNotFound = New("member_not_found", http.StatusNotFound, "member not found")
+
+	// ErrAnnouncementNotFound AnnouncementService
+	ErrAnnouncementNotFound = New("announcement_not_found", http.StatusNotFound, "announcement not found")
+
+	// ErrInvalidExpiry AnnouncementService
+	ErrInvalidExpiry = New("invalid_expiry", http.StatusBadRequest, "expiry must be in the future")
+
+	// ErrLessonNotFound ScheduleService
+	ErrLessonNotFound = New("lesson_not_found", http.StatusNotFound, "lesson not found")
+
+	// ErrInvalidDateRange ScheduleService
+	ErrInvalidDateRange = New("invalid_date_range", http.StatusBadRequest, "date range must start before it ends and span at most 31 days")
+
+	// ErrHomeworkNotFound HomeworkService
+	ErrHomeworkNotFound = New("homework_not_found", http.StatusNotFound, "homework not found")
+
+	// ErrLessonNotOnDate HomeworkService, AttendanceService
+	ErrLessonNotOnDate = New("lesson_not_on_date", http.StatusBadRequest, "lesson does not take place on the date")
+
+	// ErrLessonNotHeld AttendanceService
+	ErrLessonNotHeld = New("lesson_not_held", http.StatusBadRequest, "attendance can not be marked before the lesson day")
+
+	// ErrLessonNotToday AttendanceService
+	ErrLessonNotToday = New("lesson_not_today", http.StatusBadRequest, "check-in can be opened only on the lesson day")
+
+	// ErrCheckinNotFound AttendanceService
+	ErrCheckinNotFound = New("checkin_not_found", http.StatusNotFound, "check-in not found")
+
+	// ErrCheckinClosed AttendanceService
+	ErrCheckinClosed = New("checkin_closed", http.StatusConflict, "check-in is closed")
+
+	// ErrInvalidCheckinCode AttendanceService
+	ErrInvalidCheckinCode = New("invalid_checkin_code", http.StatusBadRequest, "check-in code is invalid or expired")
+
+	// ErrLocationRequired AttendanceService
+	ErrLocationRequired = New("location_required", http.StatusBadRequest, "check-in requires your location")
+
+	// ErrCheckinTooFar AttendanceService
+	ErrCheckinTooFar = New("checkin_too_far", http.StatusForbidden, "you are too far from the building of the lesson")
+
+	// ErrForbidden AccessService
+	ErrForbidden = New("forbidden", http.StatusForbidden, "you do not have permission to access this resource")
+
+	// ErrNotAdmin AccessService
+	ErrNotAdmin = New("not_admin", http.StatusBadRequest, "user is not an administrator")
+
+	// ErrAdminDemotion AccessService
+	ErrAdminDemotion = New("admin_demotion", http.StatusConflict, "a global administrator must be demoted explicitly to be limited to a faculty")
+
+	// ErrSelfDemotion AccessService
+	ErrSelfDemotion = New("self_demotion", http.StatusConflict, "you cannot demote yourself")
 )
@@ -6,6 +6,7 @@ type CreateGroupDTO struct {
 	FacultyID uint64
 	ProgramID uint64
 	ShortName string
+	Capacity  *int
 }
 
 type DetailsGroupDTO struct {
@@ -15,6 +16,7 @@ type DetailsGroupDTO struct {
 	Program        string
 	ShortName      string
 	NumberOfPeople int
+	Capacity       *int
 	ExistsSchedule bool
 	CreatedAt      time.Time
 }
@@ -25,10 +27,37 @@ type SummaryGroupDTO struct {
 	Program        string
 	ShortName      string
 	NumberOfPeople int
+	Capacity       *int
 	ExistsSchedule bool
+	CreatedAt      time.Time
+}
+
+type SummaryGroupsPageDTO struct {
+	Groups []SummaryGroupDTO
+	Total  int
+	Limit  int
+	Offset int
 }
 
 type FilterDTO struct {
-	Faculty string
-	Program string
+	Faculty        string
+	Program        string
+	Search         string
+	FacultyIDs     []uint64
+	ProgramIDs     []uint64
+	ExistsSchedule *bool
+	// AllowedFacultyIDs is set by the service for faculty admins and narrows any other faculty filter
+	AllowedFacultyIDs []uint64
+	SortBy            string
+	Desc              bool
+	Limit             int
+	Offset            int
+}
+
+// WaitlistPromotedPayload is published to the outbox when a user gets a place from the waitlist
+type WaitlistPromotedPayload struct {
+	UserID         uint64 `json:"user_id"`
+	GroupID        uint64 `json:"group_id"`
+	ShortName      string `json:"short_name"`
+	TelegramChatID *int64 `json:"telegram_chat_id"`
 }
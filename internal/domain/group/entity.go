@@ -9,6 +9,27 @@ type Group struct {
 	ProgramID      uint64
 	ShortName      string
 	NumberOfPeople int
+	// Capacity is the maximum number of people, nil when the group is unlimited
+	Capacity       *int
 	ExistsSchedule bool
 	CreatedAt      time.Time
 }
+
+// IsFull reports whether joining would exceed the capacity
+func (g Group) IsFull() bool {
+	return g.Capacity != nil && g.NumberOfPeople >= *g.Capacity
+}
+
+// WaitlistEntry is a user queued for a place in a full group, a user waits for one group at a time
+type WaitlistEntry struct {
+	WaitlistID uint64
+	GroupID    uint64
+	UserID     uint64
+	CreatedAt  time.Time
+}
+
+const (
+	SortByName      = "name"
+	SortBySize      = "size"
+	SortByCreatedAt = "created_at"
+)
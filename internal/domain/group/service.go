@@ -5,17 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
 	"github.com/tclutin/classflow-api/internal/domain/edu"
 	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
 	"github.com/tclutin/classflow-api/internal/domain/schedule"
 	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/internal/metric"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/tracing"
 	"log/slog"
 	"time"
 )
 
-/*
-Нужно решить, что делать с транзакциями и убрать дублирование, думаю в будущем посмотрим, что можно сделать
-*/
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
 
 type UserService interface {
 	GetById(ctx context.Context, userID uint64) (user.User, error)
@@ -23,6 +30,8 @@ type UserService interface {
 
 type ScheduleService interface {
 	GetSchedulesByGroupId(ctx context.Context, filter schedule.FilterDTO, groupID uint64) ([]schedule.DetailsScheduleDTO, error)
+	GetExamsByGroupId(ctx context.Context, filter schedule.ExamFilterDTO, groupID uint64) ([]schedule.DetailsExamDTO, error)
+	GetDaysByGroupId(ctx context.Context, groupID uint64, from, to time.Time) ([]schedule.DayDTO, error)
 }
 
 type EduService interface {
@@ -32,28 +41,58 @@ type EduService interface {
 	GetBuildingById(ctx context.Context, buildingID uint64) (edu.Building, error)
 }
 
+type AccessService interface {
+	Authorize(ctx context.Context, principal access.Principal, permission string, scopes ...access.Scope) error
+	ScopedIds(ctx context.Context, principal access.Principal, permission string, scopeType string) ([]uint64, bool, error)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+type OutboxService interface {
+	Publish(ctx context.Context, eventType string, payload any) error
+}
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...postgresql.TxOption) error
+}
+
 type UserRepository interface {
-	UpdateTx(ctx context.Context, tx pgx.Tx, user user.User) error
+	Update(ctx context.Context, user user.User) error
 }
 
 type ScheduleRepository interface {
-	CreateTx(ctx context.Context, tx pgx.Tx, schedule []schedule.Schedule) error
+	Create(ctx context.Context, schedule []schedule.Schedule) error
+	CreateExams(ctx context.Context, exams []schedule.Exam) error
+	UpdateExam(ctx context.Context, exam schedule.Exam) error
+	DeleteExam(ctx context.Context, examID uint64) error
+	DeleteExamsByTerm(ctx context.Context, groupID uint64, term string) error
+	GetExamById(ctx context.Context, examID uint64) (schedule.Exam, error)
 }
 
 type MemberRepository interface {
-	DeleteTx(ctx context.Context, tx pgx.Tx, userId uint64) error
-	CreateTx(ctx context.Context, tx pgx.Tx, userID uint64, groupId uint64) (uint64, error)
+	Delete(ctx context.Context, userId uint64) error
+	Create(ctx context.Context, userID uint64, groupId uint64) (uint64, error)
 	GetGroupIdByUserId(ctx context.Context, userID uint64) (uint64, error)
 }
 
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry WaitlistEntry) (uint64, error)
+	Position(ctx context.Context, groupID, waitlistID uint64) (int, error)
+	GetFirstByGroupId(ctx context.Context, groupID uint64) (WaitlistEntry, error)
+	Delete(ctx context.Context, waitlistID uint64) error
+	DeleteByUserId(ctx context.Context, userID uint64) error
+}
+
 type Repository interface {
 	Create(ctx context.Context, group Group) (uint64, error)
 	Update(ctx context.Context, group Group) error
-	BeginTx(ctx context.Context) (pgx.Tx, error)
-	UpdateTx(ctx context.Context, tx pgx.Tx, group Group) error
-	DeleteTx(ctx context.Context, tx pgx.Tx, groupID uint64) error
+	AddMembers(ctx context.Context, groupID uint64, delta int) (int, error)
+	Delete(ctx context.Context, groupID uint64) error
 	GetById(ctx context.Context, groupID uint64) (Group, error)
-	GetSummaryGroups(ctx context.Context, filter FilterDTO) ([]SummaryGroupDTO, error)
+	GetByIdForUpdate(ctx context.Context, groupID uint64) (Group, error)
+	GetSummaryGroups(ctx context.Context, filter FilterDTO) ([]SummaryGroupDTO, int, error)
 	GetByShortName(ctx context.Context, shortname string) (Group, error)
 	GetDetailsGroupById(ctx context.Context, groupID uint64) (DetailsGroupDTO, error)
 }
@@ -63,7 +102,12 @@ type Service struct {
 	scheduleService ScheduleService
 	userService     UserService
 	eduService      EduService
+	accessService   AccessService
+	auditService    AuditService
+	outboxService   OutboxService
+	txManager       TxManager
 	memberRepo      MemberRepository
+	waitlistRepo    WaitlistRepository
 	scheduleRepo    ScheduleRepository
 	userRepo        UserRepository
 	repo            Repository
@@ -72,12 +116,17 @@ type Service struct {
 func NewService(
 	logger *slog.Logger,
 	repository Repository,
+	txManager TxManager,
 	memberRepo MemberRepository,
+	waitlistRepo WaitlistRepository,
 	userRepo UserRepository,
 	scheduleService ScheduleService,
 	scheduleRepo ScheduleRepository,
 	userService UserService,
 	eduService EduService,
+	accessService AccessService,
+	auditService AuditService,
+	outboxService OutboxService,
 ) *Service {
 
 	return &Service{
@@ -86,14 +135,27 @@ func NewService(
 		scheduleRepo:    scheduleRepo,
 		userService:     userService,
 		repo:            repository,
+		txManager:       txManager,
 		memberRepo:      memberRepo,
+		waitlistRepo:    waitlistRepo,
 		userRepo:        userRepo,
 		eduService:      eduService,
+		accessService:   accessService,
+		auditService:    auditService,
+		outboxService:   outboxService,
 	}
 }
 
-func (s *Service) Create(ctx context.Context, dto CreateGroupDTO) (uint64, error) {
-	_, err := s.GetByShortName(ctx, dto.ShortName)
+func (s *Service) Create(ctx context.Context, principal access.Principal, dto CreateGroupDTO) (uint64, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.Create")
+	defer span.End()
+
+	err := s.accessService.Authorize(ctx, principal, access.GroupCreate, access.Faculty(dto.FacultyID))
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = s.GetByShortName(ctx, dto.ShortName)
 	if err == nil {
 		return 0, domainErr.ErrGroupAlreadyExists
 	}
@@ -118,66 +180,121 @@ func (s *Service) Create(ctx context.Context, dto CreateGroupDTO) (uint64, error
 		ProgramID:      dto.ProgramID,
 		ShortName:      dto.ShortName,
 		NumberOfPeople: 0,
+		Capacity:       dto.Capacity,
 		ExistsSchedule: false,
 		CreatedAt:      time.Now(),
 	}
 
-	groupID, err := s.repo.Create(ctx, entity)
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		groupID, err := s.repo.Create(ctx, entity)
+		if err != nil {
+			return fmt.Errorf("error creating group: %w", err)
+		}
+
+		entity.GroupID = groupID
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupCreate,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			After:      entity,
+		})
+	})
+
 	if err != nil {
-		return 0, fmt.Errorf("error creating group: %w", err)
+		return 0, err
 	}
 
-	return groupID, nil
+	return entity.GroupID, nil
 }
 
-func (s *Service) Delete(ctx context.Context, groupID uint64) error {
+func (s *Service) Delete(ctx context.Context, principal access.Principal, groupID uint64) error {
+	ctx, span := tracing.Start(ctx, "group.Service.Delete")
+	defer span.End()
+
 	group, err := s.GetById(ctx, groupID)
 	if err != nil {
 		return err
 	}
 
-	tx, err := s.repo.BeginTx(ctx)
+	err = s.accessService.Authorize(ctx, principal, access.GroupDelete, access.Faculty(group.FacultyID))
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if err != nil {
-			s.logger.Error("Rolling back transaction due to error",
-				"error", err,
-			)
-			tx.Rollback(ctx)
-		} else {
-			s.logger.Info("Committing transaction")
-			tx.Commit(ctx)
-		}
-	}()
-
-	if group.LeaderID != nil {
-		usr, err := s.userService.GetById(ctx, *group.LeaderID)
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		group, err := s.getByIdForUpdate(ctx, groupID)
 		if err != nil {
 			return err
 		}
 
-		usr.Role = user.Student
-
-		if err = s.userRepo.UpdateTx(ctx, tx, usr); err != nil {
-			return fmt.Errorf("failed to update user:  %w", err)
+		if group.LeaderID != nil {
+			leader, err := s.userService.GetById(ctx, *group.LeaderID)
+			if err != nil {
+				return err
+			}
+
+			before := leader
+			leader.Role = user.Student
+
+			if err = s.userRepo.Update(ctx, leader); err != nil {
+				return fmt.Errorf("failed to update user:  %w", err)
+			}
+
+			err = s.auditService.Record(ctx, audit.Entry{
+				Action:     audit.UserUpdate,
+				TargetType: audit.TargetUser,
+				TargetID:   leader.UserID,
+				Before:     before,
+				After:      leader,
+			})
+
+			if err != nil {
+				return err
+			}
 		}
-	}
 
-	if err = s.repo.DeleteTx(ctx, tx, groupID); err != nil {
-		return fmt.Errorf("failed to delete group: %w", err)
-	}
+		if err = s.repo.Delete(ctx, groupID); err != nil {
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
 
-	return nil
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupDelete,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			Before:     group,
+		})
+	})
 }
 
 func (s *Service) Update(ctx context.Context, group Group) error {
-	return s.repo.Update(ctx, group)
+	ctx, span := tracing.Start(ctx, "group.Service.Update")
+	defer span.End()
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getByIdForUpdate(ctx, group.GroupID)
+		if err != nil {
+			return err
+		}
+
+		if err = s.repo.Update(ctx, group); err != nil {
+			return fmt.Errorf("failed to update group: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupUpdate,
+			TargetType: audit.TargetGroup,
+			TargetID:   group.GroupID,
+			Before:     before,
+			After:      group,
+		})
+	})
 }
 
 func (s *Service) GetById(ctx context.Context, groupID uint64) (Group, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetById")
+	defer span.End()
+
 	group, err := s.repo.GetById(ctx, groupID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -190,7 +307,46 @@ func (s *Service) GetById(ctx context.Context, groupID uint64) (Group, error) {
 	return group, nil
 }
 
+// Authorize checks the permission against the faculty of the group and the group itself, which lets
+// admins, faculty admins of the faculty and the leader of the group through
+func (s *Service) Authorize(ctx context.Context, principal access.Principal, permission string, groupID uint64) (Group, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.Authorize")
+	defer span.End()
+
+	group, err := s.GetById(ctx, groupID)
+	if err != nil {
+		return Group{}, err
+	}
+
+	err = s.accessService.Authorize(ctx, principal, permission, access.Faculty(group.FacultyID), access.Group(group.GroupID))
+	if err != nil {
+		return Group{}, err
+	}
+
+	return group, nil
+}
+
+// AuthorizeRead lets members of the group through along with everyone Authorize allows for the permission
+func (s *Service) AuthorizeRead(ctx context.Context, principal access.Principal, permission string, groupID uint64) (Group, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.AuthorizeRead")
+	defer span.End()
+
+	memberOf, err := s.memberRepo.GetGroupIdByUserId(ctx, principal.UserID)
+	if err == nil && memberOf == groupID {
+		return s.GetById(ctx, groupID)
+	}
+
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Group{}, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	return s.Authorize(ctx, principal, permission, groupID)
+}
+
 func (s *Service) GetByShortName(ctx context.Context, shortname string) (Group, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetByShortName")
+	defer span.End()
+
 	group, err := s.repo.GetByShortName(ctx, shortname)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -204,6 +360,9 @@ func (s *Service) GetByShortName(ctx context.Context, shortname string) (Group,
 }
 
 func (s *Service) GetCurrentGroupByUserID(ctx context.Context, userID uint64) (DetailsGroupDTO, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetCurrentGroupByUserID")
+	defer span.End()
+
 	groupID, err := s.memberRepo.GetGroupIdByUserId(ctx, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -221,11 +380,44 @@ func (s *Service) GetCurrentGroupByUserID(ctx context.Context, userID uint64) (D
 	return currentGroup, nil
 }
 
-func (s *Service) GetAllGroupsSummary(ctx context.Context, filter FilterDTO) ([]SummaryGroupDTO, error) {
-	return s.repo.GetSummaryGroups(ctx, filter)
+// GetAllGroupsSummary limits faculty admins to the groups of the faculties they manage
+func (s *Service) GetAllGroupsSummary(ctx context.Context, principal access.Principal, filter FilterDTO) (SummaryGroupsPageDTO, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetAllGroupsSummary")
+	defer span.End()
+
+	facultyIDs, restricted, err := s.accessService.ScopedIds(ctx, principal, access.GroupCreate, access.ScopeFaculty)
+	if err != nil {
+		return SummaryGroupsPageDTO{}, err
+	}
+
+	if restricted {
+		filter.AllowedFacultyIDs = facultyIDs
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageLimit
+	}
+
+	filter.Limit = min(filter.Limit, maxPageLimit)
+	filter.Offset = max(filter.Offset, 0)
+
+	groups, total, err := s.repo.GetSummaryGroups(ctx, filter)
+	if err != nil {
+		return SummaryGroupsPageDTO{}, err
+	}
+
+	return SummaryGroupsPageDTO{
+		Groups: groups,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
 }
 
 func (s *Service) GetSchedulesByGroupId(ctx context.Context, filter schedule.FilterDTO, groupID uint64) ([]schedule.DetailsScheduleDTO, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetSchedulesByGroupId")
+	defer span.End()
+
 	_, err := s.GetById(ctx, groupID)
 	if err != nil {
 		return nil, err
@@ -239,32 +431,23 @@ func (s *Service) GetSchedulesByGroupId(ctx context.Context, filter schedule.Fil
 	return schedules, nil
 }
 
-func (s *Service) UploadSchedule(ctx context.Context, schedule []schedule.Schedule, groupID uint64) error {
+func (s *Service) UploadSchedule(ctx context.Context, principal access.Principal, schedule []schedule.Schedule, groupID uint64) error {
+	ctx, span := tracing.Start(ctx, "group.Service.UploadSchedule")
+	defer span.End()
+
 	group, err := s.GetById(ctx, groupID)
 	if err != nil {
 		return err
 	}
 
-	if group.ExistsSchedule {
-		return domainErr.ErrGroupAlreadyHasSchedule
-	}
-
-	tx, err := s.repo.BeginTx(ctx)
+	err = s.accessService.Authorize(ctx, principal, access.ScheduleWrite, access.Faculty(group.FacultyID))
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if err != nil {
-			s.logger.Error("Rolling back transaction due to error",
-				"error", err,
-			)
-			tx.Rollback(ctx)
-		} else {
-			s.logger.Info("Committing transaction")
-			tx.Commit(ctx)
-		}
-	}()
+	if group.ExistsSchedule {
+		return domainErr.ErrGroupAlreadyHasSchedule
+	}
 
 	for _, value := range schedule {
 		_, err = s.eduService.GetTypeOfSubjectById(ctx, value.TypeOfSubjectID)
@@ -272,125 +455,563 @@ func (s *Service) UploadSchedule(ctx context.Context, schedule []schedule.Schedu
 			return err
 		}
 
-		_, err = s.eduService.GetFacultyById(ctx, value.BuildingsID)
+		_, err = s.eduService.GetBuildingById(ctx, value.BuildingsID)
 		if err != nil {
 			return err
 		}
+	}
 
-		_, err = s.eduService.GetBuildingById(ctx, value.BuildingsID)
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		group, err := s.getByIdForUpdate(ctx, groupID)
 		if err != nil {
 			return err
 		}
-	}
 
-	if err = s.scheduleRepo.CreateTx(ctx, tx, schedule); err != nil {
-		return fmt.Errorf("failed to create new schedule: %w", err)
+		// a concurrent upload may have finished since the check above
+		if group.ExistsSchedule {
+			return domainErr.ErrGroupAlreadyHasSchedule
+		}
+
+		before := group
+		group.ExistsSchedule = true
+
+		if err = s.scheduleRepo.Create(ctx, schedule); err != nil {
+			return fmt.Errorf("failed to create new schedule: %w", err)
+		}
+
+		if err = s.repo.Update(ctx, group); err != nil {
+			return fmt.Errorf("failed to update group: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupScheduleUpload,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			Before:     before,
+			After: map[string]any{
+				"group":    group,
+				"schedule": schedule,
+			},
+		})
+	})
+
+	if err == nil {
+		metric.IncScheduleUploadsCounter()
 	}
 
-	group.ExistsSchedule = true
+	return err
+}
+
+func (s *Service) GetExamsByGroupId(ctx context.Context, filter schedule.ExamFilterDTO, groupID uint64) ([]schedule.DetailsExamDTO, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetExamsByGroupId")
+	defer span.End()
 
-	if err = s.repo.UpdateTx(ctx, tx, group); err != nil {
-		return fmt.Errorf("failed to update group: %w", err)
+	if _, err := s.GetById(ctx, groupID); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return s.scheduleService.GetExamsByGroupId(ctx, filter, groupID)
 }
 
-func (s *Service) JoinToGroup(ctx context.Context, userID, groupID uint64) error {
-	_, err := s.memberRepo.GetGroupIdByUserId(ctx, userID)
-	if err == nil {
-		return domainErr.ErrAlreadyInGroup
+// GetDaysByGroupId returns the lessons and exams of the group by date, it backs the calendar export
+func (s *Service) GetDaysByGroupId(ctx context.Context, groupID uint64, from, to time.Time) ([]schedule.DayDTO, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetDaysByGroupId")
+	defer span.End()
+
+	if _, err := s.GetById(ctx, groupID); err != nil {
+		return nil, err
 	}
 
+	return s.scheduleService.GetDaysByGroupId(ctx, groupID, from, to)
+}
+
+// UploadExams replaces the exam session of the term, exams of other terms are kept
+func (s *Service) UploadExams(ctx context.Context, principal access.Principal, groupID uint64, term string, exams []schedule.Exam) error {
+	ctx, span := tracing.Start(ctx, "group.Service.UploadExams")
+	defer span.End()
+
 	group, err := s.GetById(ctx, groupID)
 	if err != nil {
 		return err
 	}
 
-	tx, err := s.repo.BeginTx(ctx)
+	err = s.accessService.Authorize(ctx, principal, access.ScheduleWrite, access.Faculty(group.FacultyID))
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if err != nil {
-			s.logger.Error("Rolling back transaction due to error",
-				"error", err,
-			)
-			tx.Rollback(ctx)
-		} else {
-			s.logger.Info("Committing transaction")
-			tx.Commit(ctx)
+	for _, exam := range exams {
+		if _, err = s.eduService.GetBuildingById(ctx, exam.BuildingsID); err != nil {
+			return err
+		}
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.scheduleRepo.DeleteExamsByTerm(ctx, groupID, term); err != nil {
+			return fmt.Errorf("failed to delete exams: %w", err)
 		}
-	}()
 
-	_, err = s.memberRepo.CreateTx(ctx, tx, userID, groupID)
+		if err := s.scheduleRepo.CreateExams(ctx, exams); err != nil {
+			return fmt.Errorf("failed to create exams: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupExamsUpload,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			After: map[string]any{
+				"term":  term,
+				"exams": exams,
+			},
+		})
+	})
+}
+
+func (s *Service) UpdateExam(ctx context.Context, principal access.Principal, exam schedule.Exam) error {
+	ctx, span := tracing.Start(ctx, "group.Service.UpdateExam")
+	defer span.End()
+
+	group, before, err := s.getExam(ctx, principal, exam.GroupID, exam.ExamID)
 	if err != nil {
-		return fmt.Errorf("failed to create member: %w", err)
+		return err
+	}
+
+	if _, err = s.eduService.GetBuildingById(ctx, exam.BuildingsID); err != nil {
+		return err
 	}
 
-	group.NumberOfPeople++
+	exam.Term = before.Term
+	exam.CreatedAt = before.CreatedAt
 
-	if err = s.repo.UpdateTx(ctx, tx, group); err != nil {
-		return fmt.Errorf("failed to update group: %w", err)
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.scheduleRepo.UpdateExam(ctx, exam); err != nil {
+			return fmt.Errorf("failed to update exam: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupExamUpdate,
+			TargetType: audit.TargetGroup,
+			TargetID:   group.GroupID,
+			Before:     before,
+			After:      exam,
+		})
+	})
+}
+
+func (s *Service) DeleteExam(ctx context.Context, principal access.Principal, groupID, examID uint64) error {
+	ctx, span := tracing.Start(ctx, "group.Service.DeleteExam")
+	defer span.End()
+
+	group, before, err := s.getExam(ctx, principal, groupID, examID)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.scheduleRepo.DeleteExam(ctx, examID); err != nil {
+			return fmt.Errorf("failed to delete exam: %w", err)
+		}
 
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupExamDelete,
+			TargetType: audit.TargetGroup,
+			TargetID:   group.GroupID,
+			Before:     before,
+		})
+	})
 }
 
-func (s *Service) LeaveFromGroup(ctx context.Context, userID uint64) error {
-	groupID, err := s.memberRepo.GetGroupIdByUserId(ctx, userID)
+// getExam authorizes the schedule change and returns the exam, an exam of another group is not found
+func (s *Service) getExam(ctx context.Context, principal access.Principal, groupID, examID uint64) (Group, schedule.Exam, error) {
+	group, err := s.GetById(ctx, groupID)
+	if err != nil {
+		return Group{}, schedule.Exam{}, err
+	}
+
+	err = s.accessService.Authorize(ctx, principal, access.ScheduleWrite, access.Faculty(group.FacultyID))
+	if err != nil {
+		return Group{}, schedule.Exam{}, err
+	}
+
+	exam, err := s.scheduleRepo.GetExamById(ctx, examID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return domainErr.ErrMemberNotFound
+			return Group{}, schedule.Exam{}, domainErr.ErrExamNotFound
 		}
+
+		return Group{}, schedule.Exam{}, fmt.Errorf("failed to get exam: %w", err)
 	}
 
+	if exam.GroupID != groupID {
+		return Group{}, schedule.Exam{}, domainErr.ErrExamNotFound
+	}
+
+	return group, exam, nil
+}
+
+// SetCapacity changes the maximum number of people, nil removes the limit, the freed places are
+// given to the waitlist right away
+func (s *Service) SetCapacity(ctx context.Context, principal access.Principal, groupID uint64, capacity *int) error {
+	ctx, span := tracing.Start(ctx, "group.Service.SetCapacity")
+	defer span.End()
+
 	group, err := s.GetById(ctx, groupID)
 	if err != nil {
 		return err
 	}
 
-	usr, err := s.userService.GetById(ctx, userID)
+	err = s.accessService.Authorize(ctx, principal, access.MembersManage, access.Faculty(group.FacultyID), access.Group(group.GroupID))
 	if err != nil {
 		return err
 	}
 
-	tx, err := s.repo.BeginTx(ctx)
-	if err != nil {
+	var promoted int
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		group, err := s.getByIdForUpdate(ctx, groupID)
+		if err != nil {
+			return err
+		}
+
+		if capacity != nil && *capacity < group.NumberOfPeople {
+			return domainErr.ErrCapacityBelowMembers
+		}
+
+		before := group
+		group.Capacity = capacity
+
+		if err = s.repo.Update(ctx, group); err != nil {
+			return fmt.Errorf("failed to update group: %w", err)
+		}
+
+		err = s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupUpdate,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			Before:     before,
+			After:      group,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		promoted, err = s.promoteFromWaitlist(ctx, group)
+
+		return err
+	})
+
+	if err == nil {
+		for range promoted {
+			metric.IncGroupJoinsCounter()
+		}
+	}
+
+	return err
+}
+
+// JoinToGroup relies on the unique member constraint rather than a prior check, so two concurrent joins
+// of one user cannot both succeed
+func (s *Service) JoinToGroup(ctx context.Context, userID, groupID uint64) error {
+	ctx, span := tracing.Start(ctx, "group.Service.JoinToGroup")
+	defer span.End()
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		group, err := s.getByIdForUpdate(ctx, groupID)
+		if err != nil {
+			return err
+		}
+
+		if group.IsFull() {
+			return domainErr.ErrGroupFull
+		}
+
+		if _, err = s.memberRepo.Create(ctx, userID, groupID); err != nil {
+			if postgresql.IsUniqueViolation(err) {
+				return domainErr.ErrAlreadyInGroup
+			}
+
+			return fmt.Errorf("failed to create member: %w", err)
+		}
+
+		// a member no longer waits for any group
+		if err = s.waitlistRepo.DeleteByUserId(ctx, userID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to delete waitlist entry: %w", err)
+		}
+
+		before := group
+
+		group.NumberOfPeople, err = s.repo.AddMembers(ctx, groupID, 1)
+		if err != nil {
+			return fmt.Errorf("failed to update group: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupJoin,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			Before:     before,
+			After:      group,
+		})
+	})
+
+	if err == nil {
+		metric.IncGroupJoinsCounter()
+	}
+
+	return err
+}
+
+// LeaveFromGroup gives the freed place to the first user in the waitlist within the same transaction
+func (s *Service) LeaveFromGroup(ctx context.Context, userID uint64) error {
+	ctx, span := tracing.Start(ctx, "group.Service.LeaveFromGroup")
+	defer span.End()
+
+	var promoted int
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		groupID, err := s.memberRepo.GetGroupIdByUserId(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domainErr.ErrMemberNotFound
+			}
+
+			return fmt.Errorf("failed to get member: %w", err)
+		}
+
+		group, err := s.getByIdForUpdate(ctx, groupID)
+		if err != nil {
+			return err
+		}
+
+		if err = s.memberRepo.Delete(ctx, userID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domainErr.ErrMemberNotFound
+			}
+
+			return fmt.Errorf("failed to delete member: %w", err)
+		}
+
+		before := group
+
+		if group.LeaderID != nil && userID == *group.LeaderID {
+			usr, err := s.userService.GetById(ctx, userID)
+			if err != nil {
+				return err
+			}
+
+			usr.Role = user.Student
+			if err = s.userRepo.Update(ctx, usr); err != nil {
+				return fmt.Errorf("failed to update user: %w", err)
+			}
+
+			group.LeaderID = nil
+			if err = s.repo.Update(ctx, group); err != nil {
+				return fmt.Errorf("failed to update group: %w", err)
+			}
+		}
+
+		group.NumberOfPeople, err = s.repo.AddMembers(ctx, groupID, -1)
+		if err != nil {
+			return fmt.Errorf("failed to update group: %w", err)
+		}
+
+		err = s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupLeave,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			Before:     before,
+			After:      group,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		promoted, err = s.promoteFromWaitlist(ctx, group)
+
 		return err
+	})
+
+	if err == nil {
+		metric.IncGroupLeavesCounter()
+
+		for range promoted {
+			metric.IncGroupJoinsCounter()
+		}
 	}
 
-	defer func() {
+	return err
+}
+
+// JoinWaitlist queues the user for a place in a full group and returns the position in the queue
+func (s *Service) JoinWaitlist(ctx context.Context, userID, groupID uint64) (int, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.JoinWaitlist")
+	defer span.End()
+
+	var position int
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		group, err := s.getByIdForUpdate(ctx, groupID)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.memberRepo.GetGroupIdByUserId(ctx, userID)
+		if err == nil {
+			return domainErr.ErrAlreadyInGroup
+		}
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to get member: %w", err)
+		}
+
+		if !group.IsFull() {
+			return domainErr.ErrGroupNotFull
+		}
+
+		entry := WaitlistEntry{
+			GroupID:   groupID,
+			UserID:    userID,
+			CreatedAt: time.Now(),
+		}
+
+		entry.WaitlistID, err = s.waitlistRepo.Create(ctx, entry)
 		if err != nil {
-			s.logger.Error("Rolling back transaction due to error",
-				"error", err,
-			)
-			tx.Rollback(ctx)
-		} else {
-			s.logger.Info("Committing transaction")
-			tx.Commit(ctx)
+			if postgresql.IsUniqueViolation(err) {
+				return domainErr.ErrAlreadyInWaitlist
+			}
+
+			return fmt.Errorf("failed to create waitlist entry: %w", err)
 		}
-	}()
 
-	if group.LeaderID != nil && usr.UserID == *group.LeaderID {
-		usr.Role = user.Student
-		group.LeaderID = nil
-		if err = s.userRepo.UpdateTx(ctx, tx, usr); err != nil {
-			return fmt.Errorf("failed to update user: %w", err)
+		position, err = s.waitlistRepo.Position(ctx, groupID, entry.WaitlistID)
+		if err != nil {
+			return fmt.Errorf("failed to get waitlist position: %w", err)
 		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupWaitlistJoin,
+			TargetType: audit.TargetGroup,
+			TargetID:   groupID,
+			After:      entry,
+		})
+	})
+
+	if err != nil {
+		return 0, err
 	}
 
-	if err = s.memberRepo.DeleteTx(ctx, tx, userID); err != nil {
-		return fmt.Errorf("failed to delete member: %w", err)
+	return position, nil
+}
+
+func (s *Service) LeaveWaitlist(ctx context.Context, userID uint64) error {
+	ctx, span := tracing.Start(ctx, "group.Service.LeaveWaitlist")
+	defer span.End()
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.waitlistRepo.DeleteByUserId(ctx, userID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domainErr.ErrWaitlistEntryNotFound
+			}
+
+			return fmt.Errorf("failed to delete waitlist entry: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupWaitlistLeave,
+			TargetType: audit.TargetUser,
+			TargetID:   userID,
+		})
+	})
+}
+
+// promoteFromWaitlist fills the free places of the group in the order users joined the waitlist and
+// notifies each promoted user through the outbox, call it within the transaction holding the group lock
+func (s *Service) promoteFromWaitlist(ctx context.Context, group Group) (int, error) {
+	var promoted int
+
+	for !group.IsFull() {
+		entry, err := s.waitlistRepo.GetFirstByGroupId(ctx, group.GroupID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				break
+			}
+
+			return 0, fmt.Errorf("failed to get waitlist entry: %w", err)
+		}
+
+		if err = s.waitlistRepo.Delete(ctx, entry.WaitlistID); err != nil {
+			return 0, fmt.Errorf("failed to delete waitlist entry: %w", err)
+		}
+
+		// the user has joined another group in the meantime, the entry is dropped
+		_, err = s.memberRepo.GetGroupIdByUserId(ctx, entry.UserID)
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("failed to get member: %w", err)
+		}
+
+		if _, err = s.memberRepo.Create(ctx, entry.UserID, group.GroupID); err != nil {
+			return 0, fmt.Errorf("failed to create member: %w", err)
+		}
+
+		before := group
+
+		group.NumberOfPeople, err = s.repo.AddMembers(ctx, group.GroupID, 1)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update group: %w", err)
+		}
+
+		err = s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.GroupWaitlistPromote,
+			TargetType: audit.TargetGroup,
+			TargetID:   group.GroupID,
+			Before:     before,
+			After: map[string]any{
+				"group": group,
+				"entry": entry,
+			},
+		})
+
+		if err != nil {
+			return 0, err
+		}
+
+		usr, err := s.userService.GetById(ctx, entry.UserID)
+		if err != nil {
+			return 0, err
+		}
+
+		err = s.outboxService.Publish(ctx, outbox.GroupWaitlistPromoted, WaitlistPromotedPayload{
+			UserID:         usr.UserID,
+			GroupID:        group.GroupID,
+			ShortName:      group.ShortName,
+			TelegramChatID: usr.TelegramChatID,
+		})
+
+		if err != nil {
+			return 0, err
+		}
+
+		promoted++
 	}
 
-	group.NumberOfPeople = group.NumberOfPeople - 1
+	return promoted, nil
+}
+
+func (s *Service) getByIdForUpdate(ctx context.Context, groupID uint64) (Group, error) {
+	group, err := s.repo.GetByIdForUpdate(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Group{}, domainErr.ErrGroupNotFound
+		}
 
-	if err = s.repo.UpdateTx(ctx, tx, group); err != nil {
-		return fmt.Errorf("failed to update group: %w", err)
+		return Group{}, fmt.Errorf("failed to lock group: %w", err)
 	}
 
-	return nil
+	return group, nil
 }
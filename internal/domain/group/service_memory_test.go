@@ -0,0 +1,440 @@
+package group_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/internal/repository/memory"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// memoryEnv is a group service on top of a fresh in-memory store with one faculty and program,
+// admins may create groups and manage members anywhere
+type memoryEnv struct {
+	ctx     context.Context
+	service *group.Service
+	repos   *memory.Repositories
+	faculty edu.Faculty
+	program edu.Program
+	admin   access.Principal
+}
+
+func newMemoryEnv(t *testing.T) memoryEnv {
+	t.Helper()
+
+	ctx := context.Background()
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+	txManager := memory.NewTxManager(store)
+
+	faculty, err := repos.Edu.AddFaculty(ctx, "Институт информационных технологий")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := repos.Edu.AddProgram(ctx, faculty.FacultyID, "Программная инженерия")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repos.Access.AddGrants(ctx,
+		access.Grant{Role: user.Admin, Permission: access.GroupCreate},
+		access.Grant{Role: user.Admin, Permission: access.MembersManage},
+		access.Grant{Role: user.Admin, Permission: access.ScheduleWrite})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auditService := audit.NewService(repos.Audit)
+	userService := user.NewService(repos.User, txManager, auditService)
+	eduService := edu.NewService(repos.Edu)
+
+	service := group.NewService(slog.New(slog.NewTextHandler(io.Discard, nil)),
+		repos.Group,
+		txManager,
+		repos.Member,
+		repos.Waitlist,
+		repos.User,
+		schedule.NewService(repos.Schedule, schedule.Term{}),
+		repos.Schedule,
+		userService,
+		eduService,
+		access.NewService(repos.Access, txManager, userService, eduService, auditService),
+		auditService,
+		outbox.NewService(repos.Outbox))
+
+	env := memoryEnv{
+		ctx:     ctx,
+		service: service,
+		repos:   repos,
+		faculty: faculty,
+		program: program,
+	}
+
+	env.admin = access.Principal{UserID: env.user(t, user.Admin), Role: user.Admin}
+
+	return env
+}
+
+func (e memoryEnv) user(t *testing.T, role string) uint64 {
+	t.Helper()
+
+	chatID := time.Now().UnixNano()
+
+	userID, err := e.repos.User.Create(e.ctx, user.User{Role: role, TelegramChatID: &chatID, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return userID
+}
+
+func (e memoryEnv) group(t *testing.T, shortName string, capacity *int) uint64 {
+	t.Helper()
+
+	groupID, err := e.service.Create(e.ctx, e.admin, group.CreateGroupDTO{
+		FacultyID: e.faculty.FacultyID,
+		ProgramID: e.program.ProgramID,
+		ShortName: shortName,
+		Capacity:  capacity,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return groupID
+}
+
+func TestService_Create(t *testing.T) {
+	env := newMemoryEnv(t)
+	env.group(t, "PI-101", nil)
+
+	other, err := env.repos.Edu.AddFaculty(env.ctx, "Институт математики")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	student := access.Principal{UserID: env.user(t, user.Student), Role: user.Student}
+
+	tests := []struct {
+		name      string
+		principal access.Principal
+		dto       group.CreateGroupDTO
+		wantErr   error
+	}{
+		{
+			name:      "new group",
+			principal: env.admin,
+			dto:       group.CreateGroupDTO{FacultyID: env.faculty.FacultyID, ProgramID: env.program.ProgramID, ShortName: "PI-102"},
+		},
+		{
+			name:      "taken short name",
+			principal: env.admin,
+			dto:       group.CreateGroupDTO{FacultyID: env.faculty.FacultyID, ProgramID: env.program.ProgramID, ShortName: "PI-101"},
+			wantErr:   domainErr.ErrGroupAlreadyExists,
+		},
+		{
+			name:      "program of another faculty",
+			principal: env.admin,
+			dto:       group.CreateGroupDTO{FacultyID: other.FacultyID, ProgramID: env.program.ProgramID, ShortName: "PM-201"},
+			wantErr:   domainErr.ErrFacultyProgramIdMismatch,
+		},
+		{
+			name:      "missing program",
+			principal: env.admin,
+			dto:       group.CreateGroupDTO{FacultyID: env.faculty.FacultyID, ProgramID: 1 << 40, ShortName: "PM-202"},
+			wantErr:   domainErr.ErrProgramNotFound,
+		},
+		{
+			name:      "student",
+			principal: student,
+			dto:       group.CreateGroupDTO{FacultyID: env.faculty.FacultyID, ProgramID: env.program.ProgramID, ShortName: "PI-103"},
+			wantErr:   domainErr.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groupID, err := env.service.Create(env.ctx, tt.principal, tt.dto)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			created, err := env.service.GetById(env.ctx, groupID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if created.ShortName != tt.dto.ShortName || created.NumberOfPeople != 0 || created.ExistsSchedule {
+				t.Fatalf("unexpected group %+v", created)
+			}
+		})
+	}
+}
+
+func TestService_Membership(t *testing.T) {
+	env := newMemoryEnv(t)
+
+	capacity := 2
+	groupID := env.group(t, "PI-101", &capacity)
+	otherID := env.group(t, "PI-102", nil)
+
+	alice, bob, carol, dave := env.user(t, user.Student), env.user(t, user.Student), env.user(t, user.Student), env.user(t, user.Student)
+
+	join := func(userID, groupID uint64) func() error {
+		return func() error { return env.service.JoinToGroup(env.ctx, userID, groupID) }
+	}
+
+	leave := func(userID uint64) func() error {
+		return func() error { return env.service.LeaveFromGroup(env.ctx, userID) }
+	}
+
+	wait := func(userID, groupID uint64, position int) func() error {
+		return func() error {
+			got, err := env.service.JoinWaitlist(env.ctx, userID, groupID)
+			if err == nil && got != position {
+				t.Fatalf("waitlist position of %d: got %d, want %d", userID, got, position)
+			}
+			return err
+		}
+	}
+
+	steps := []struct {
+		name       string
+		do         func() error
+		wantErr    error
+		wantPeople int
+	}{
+		{name: "alice joins", do: join(alice, groupID), wantPeople: 1},
+		{name: "alice joins again", do: join(alice, groupID), wantErr: domainErr.ErrAlreadyInGroup, wantPeople: 1},
+		{name: "alice joins another group", do: join(alice, otherID), wantErr: domainErr.ErrAlreadyInGroup, wantPeople: 1},
+		{name: "carol can not wait for a group with places", do: wait(carol, groupID, 0), wantErr: domainErr.ErrGroupNotFull, wantPeople: 1},
+		{name: "bob fills the group", do: join(bob, groupID), wantPeople: 2},
+		{name: "carol finds it full", do: join(carol, groupID), wantErr: domainErr.ErrGroupFull, wantPeople: 2},
+		{name: "carol waits", do: wait(carol, groupID, 1), wantPeople: 2},
+		{name: "carol waits again", do: wait(carol, groupID, 0), wantErr: domainErr.ErrAlreadyInWaitlist, wantPeople: 2},
+		{name: "bob can not wait as a member", do: wait(bob, groupID, 0), wantErr: domainErr.ErrAlreadyInGroup, wantPeople: 2},
+		{name: "dave waits", do: wait(dave, groupID, 2), wantPeople: 2},
+		{name: "alice leaves and carol takes the place", do: leave(alice), wantPeople: 2},
+		{name: "alice leaves again", do: leave(alice), wantErr: domainErr.ErrMemberNotFound, wantPeople: 2},
+		{name: "dave leaves the waitlist", do: func() error { return env.service.LeaveWaitlist(env.ctx, dave) }, wantPeople: 2},
+		{name: "dave leaves the waitlist again", do: func() error { return env.service.LeaveWaitlist(env.ctx, dave) }, wantErr: domainErr.ErrWaitlistEntryNotFound, wantPeople: 2},
+		{name: "bob leaves and nobody waits", do: leave(bob), wantPeople: 1},
+	}
+
+	for _, step := range steps {
+		if err := step.do(); !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: expected %v, got %v", step.name, step.wantErr, err)
+		}
+
+		entity, err := env.service.GetById(env.ctx, groupID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if entity.NumberOfPeople != step.wantPeople {
+			t.Fatalf("%s: number of people is %d, want %d", step.name, entity.NumberOfPeople, step.wantPeople)
+		}
+	}
+
+	memberOf, err := env.repos.Member.GetGroupIdByUserId(env.ctx, carol)
+	if err != nil || memberOf != groupID {
+		t.Fatalf("carol is not promoted to the group: %d, %v", memberOf, err)
+	}
+
+	events, err := env.repos.Outbox.GetPending(env.ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0].EventType != outbox.GroupWaitlistPromoted {
+		t.Fatalf("expected one %s event, got %+v", outbox.GroupWaitlistPromoted, events)
+	}
+
+	var payload group.WaitlistPromotedPayload
+	if err = json.Unmarshal(events[0].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.UserID != carol || payload.GroupID != groupID || payload.ShortName != "PI-101" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestService_SetCapacity(t *testing.T) {
+	env := newMemoryEnv(t)
+
+	one := 1
+	groupID := env.group(t, "PI-101", &one)
+
+	first, second, third := env.user(t, user.Student), env.user(t, user.Student), env.user(t, user.Student)
+
+	if err := env.service.JoinToGroup(env.ctx, first, groupID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, userID := range []uint64{second, third} {
+		if _, err := env.service.JoinWaitlist(env.ctx, userID, groupID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	zero, two := 0, 2
+
+	tests := []struct {
+		name       string
+		principal  access.Principal
+		capacity   *int
+		wantErr    error
+		wantPeople int
+	}{
+		{name: "student", principal: access.Principal{UserID: first, Role: user.Student}, capacity: &two, wantErr: domainErr.ErrForbidden, wantPeople: 1},
+		{name: "below the members", principal: env.admin, capacity: &zero, wantErr: domainErr.ErrCapacityBelowMembers, wantPeople: 1},
+		{name: "raised by one", principal: env.admin, capacity: &two, wantPeople: 2},
+		{name: "unlimited", principal: env.admin, wantPeople: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := env.service.SetCapacity(env.ctx, tt.principal, groupID, tt.capacity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			entity, err := env.service.GetById(env.ctx, groupID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if entity.NumberOfPeople != tt.wantPeople {
+				t.Fatalf("number of people is %d, want %d", entity.NumberOfPeople, tt.wantPeople)
+			}
+		})
+	}
+}
+
+func TestService_UploadSchedule(t *testing.T) {
+	env := newMemoryEnv(t)
+
+	typeOfSubject, err := env.repos.Edu.AddTypeOfSubject(env.ctx, "Лекция")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	building, err := env.repos.Edu.AddBuilding(env.ctx, edu.Building{Name: "Главный корпус"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lesson := func(buildingID uint64) []schedule.Schedule {
+		return []schedule.Schedule{{
+			BuildingsID:     buildingID,
+			TypeOfSubjectID: typeOfSubject.TypeOfSubjectID,
+			SubjectName:     "Алгебра",
+			EveryWeek:       true,
+			DayOfWeek:       1,
+			StartTime:       "09:00",
+			EndTime:         "10:30",
+		}}
+	}
+
+	groupID := env.group(t, "PI-101", nil)
+
+	tests := []struct {
+		name    string
+		lessons []schedule.Schedule
+		wantErr error
+	}{
+		{name: "missing building", lessons: lesson(building.BuildingID + 1000), wantErr: domainErr.ErrBuildingNotFound},
+		{name: "upload", lessons: lesson(building.BuildingID)},
+		{name: "second upload", lessons: lesson(building.BuildingID), wantErr: domainErr.ErrGroupAlreadyHasSchedule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.lessons {
+				tt.lessons[i].GroupID = groupID
+			}
+
+			err := env.service.UploadSchedule(env.ctx, env.admin, tt.lessons, groupID)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	entity, err := env.service.GetById(env.ctx, groupID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lessons, err := env.service.GetSchedulesByGroupId(env.ctx, schedule.FilterDTO{}, groupID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !entity.ExistsSchedule || len(lessons) != 1 {
+		t.Fatalf("expected the group to have one lesson, got %+v with %d lessons", entity, len(lessons))
+	}
+}
+
+func TestService_LeaderLeaves(t *testing.T) {
+	env := newMemoryEnv(t)
+
+	groupID := env.group(t, "PI-101", nil)
+	leader := env.user(t, user.Leader)
+
+	if err := env.service.JoinToGroup(env.ctx, leader, groupID); err != nil {
+		t.Fatal(err)
+	}
+
+	entity, err := env.service.GetById(env.ctx, groupID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entity.LeaderID = &leader
+
+	if err = env.service.Update(env.ctx, entity); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = env.service.LeaveFromGroup(env.ctx, leader); err != nil {
+		t.Fatal(err)
+	}
+
+	entity, err = env.service.GetById(env.ctx, groupID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entity.LeaderID != nil || entity.NumberOfPeople != 0 {
+		t.Fatalf("expected a group without leader and members, got %+v", entity)
+	}
+
+	former, err := env.repos.User.GetById(env.ctx, leader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if former.Role != user.Student {
+		t.Fatalf("former leader has role %s, want %s", former.Role, user.Student)
+	}
+}
@@ -0,0 +1,376 @@
+//go:build integration
+
+package group_test
+
+import (
+	"context"
+	"errors"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/repository"
+	"github.com/tclutin/classflow-api/internal/testutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Run(m))
+}
+
+// env is a group service on top of the emptied shared database
+type env struct {
+	ctx      context.Context
+	pg       *testutil.Postgres
+	service  *group.Service
+	repos    *repository.Repositories
+	fixtures *testutil.Fixtures
+}
+
+func setup(t *testing.T) env {
+	t.Helper()
+
+	pg := testutil.Database(t)
+	server := testutil.NewServer(pg, testutil.Config())
+	t.Cleanup(server.Close)
+
+	return env{
+		ctx:      context.Background(),
+		pg:       pg,
+		service:  server.Services.Group,
+		repos:    server.Repositories,
+		fixtures: server.Fixtures,
+	}
+}
+
+// students creates n students and returns their ids
+func (e env) students(t *testing.T, n int) []uint64 {
+	t.Helper()
+
+	userIDs := make([]uint64, 0, n)
+
+	for range n {
+		student, err := e.fixtures.Student(e.ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		userIDs = append(userIDs, student.UserID)
+	}
+
+	return userIDs
+}
+
+// check fails t unless number_of_people matches the membership rows and stays within the capacity
+func (e env) check(t *testing.T, groupID uint64) int {
+	t.Helper()
+
+	entity, err := e.repos.Group.GetById(e.ctx, groupID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	members := e.members(t, groupID)
+
+	if entity.NumberOfPeople != members {
+		t.Fatalf("number_of_people is %d, but the group has %d members", entity.NumberOfPeople, members)
+	}
+
+	if entity.Capacity != nil && members > *entity.Capacity {
+		t.Fatalf("group has %d members over the capacity of %d", members, *entity.Capacity)
+	}
+
+	return members
+}
+
+func (e env) members(t *testing.T, groupID uint64) int {
+	t.Helper()
+
+	var members int
+
+	err := e.pg.Pool.QueryRow(e.ctx, `SELECT COUNT(*) FROM public.members WHERE group_id = $1`, groupID).Scan(&members)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return members
+}
+
+// parallel runs fn for every index at once and returns the errors by index
+func parallel(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	for i := range n {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			<-start
+			errs[i] = fn(i)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+func TestService_ConcurrentJoins(t *testing.T) {
+	tests := []struct {
+		name     string
+		users    int
+		capacity *int
+		want     int
+	}{
+		{name: "unlimited group takes everybody", users: 40, want: 40},
+		{name: "limited group stops at the capacity", users: 40, capacity: ptr(7), want: 7},
+		{name: "group of one", users: 20, capacity: ptr(1), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := setup(t)
+
+			created, err := e.fixtures.Group(e.ctx, func(g *group.Group) { g.Capacity = tt.capacity })
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			userIDs := e.students(t, tt.users)
+
+			errs := parallel(len(userIDs), func(i int) error {
+				return e.service.JoinToGroup(e.ctx, userIDs[i], created.GroupID)
+			})
+
+			var joined int
+
+			for i, err := range errs {
+				switch {
+				case err == nil:
+					joined++
+				case errors.Is(err, domainErr.ErrGroupFull):
+				default:
+					t.Fatalf("user %d: unexpected error: %v", userIDs[i], err)
+				}
+			}
+
+			if joined != tt.want {
+				t.Fatalf("%d users joined, want %d", joined, tt.want)
+			}
+
+			if members := e.check(t, created.GroupID); members != tt.want {
+				t.Fatalf("group has %d members, want %d", members, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_ConcurrentDuplicateJoins(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups int
+	}{
+		{name: "same group", groups: 1},
+		{name: "different groups", groups: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := setup(t)
+
+			var groupIDs []uint64
+
+			for range tt.groups {
+				created, err := e.fixtures.Group(e.ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				groupIDs = append(groupIDs, created.GroupID)
+			}
+
+			userID := e.students(t, 1)[0]
+
+			const attempts = 20
+
+			errs := parallel(attempts, func(i int) error {
+				return e.service.JoinToGroup(e.ctx, userID, groupIDs[i%len(groupIDs)])
+			})
+
+			var joined int
+
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					joined++
+				case errors.Is(err, domainErr.ErrAlreadyInGroup):
+				default:
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if joined != 1 {
+				t.Fatalf("user joined %d times, want once", joined)
+			}
+
+			var total int
+			for _, groupID := range groupIDs {
+				total += e.check(t, groupID)
+			}
+
+			if total != 1 {
+				t.Fatalf("user is a member %d times, want once", total)
+			}
+		})
+	}
+}
+
+func TestService_ConcurrentJoinsAndLeaves(t *testing.T) {
+	e := setup(t)
+
+	const capacity = 5
+
+	created, err := e.fixtures.Group(e.ctx, func(g *group.Group) { g.Capacity = ptr(capacity) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userIDs := e.students(t, 15)
+
+	// every user joins and leaves a few times while the others do the same
+	const rounds = 5
+
+	var (
+		exceeded atomic.Int64
+		done     = make(chan struct{})
+		watcher  sync.WaitGroup
+	)
+
+	watcher.Add(1)
+
+	// the capacity must hold at every commit, not only at the end
+	go func() {
+		defer watcher.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+			}
+
+			var members int
+
+			err := e.pg.Pool.QueryRow(e.ctx, `SELECT COUNT(*) FROM public.members WHERE group_id = $1`, created.GroupID).Scan(&members)
+			if err == nil && members > capacity {
+				exceeded.Add(1)
+			}
+		}
+	}()
+
+	errs := parallel(len(userIDs), func(i int) error {
+		for range rounds {
+			err := e.service.JoinToGroup(e.ctx, userIDs[i], created.GroupID)
+
+			switch {
+			case err == nil:
+			case errors.Is(err, domainErr.ErrGroupFull):
+				continue
+			default:
+				return err
+			}
+
+			if err = e.service.LeaveFromGroup(e.ctx, userIDs[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	close(done)
+	watcher.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("user %d: unexpected error: %v", userIDs[i], err)
+		}
+	}
+
+	if n := exceeded.Load(); n > 0 {
+		t.Fatalf("capacity was exceeded %d times", n)
+	}
+
+	if members := e.check(t, created.GroupID); members != 0 {
+		t.Fatalf("group has %d members after everybody left, want 0", members)
+	}
+
+	// a leave of somebody who already left is rejected and changes nothing
+	errs = parallel(len(userIDs), func(i int) error {
+		return e.service.LeaveFromGroup(e.ctx, userIDs[i])
+	})
+
+	for _, err := range errs {
+		if !errors.Is(err, domainErr.ErrMemberNotFound) {
+			t.Fatalf("got %v, want %v", err, domainErr.ErrMemberNotFound)
+		}
+	}
+
+	e.check(t, created.GroupID)
+}
+
+func TestService_ConcurrentLeavesPromoteWaitlist(t *testing.T) {
+	e := setup(t)
+
+	const capacity = 5
+
+	created, err := e.fixtures.Group(e.ctx, func(g *group.Group) { g.Capacity = ptr(capacity) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	members := e.students(t, capacity)
+	waiting := e.students(t, 3)
+
+	for _, userID := range members {
+		if err = e.service.JoinToGroup(e.ctx, userID, created.GroupID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, userID := range waiting {
+		if _, err = e.service.JoinWaitlist(e.ctx, userID, created.GroupID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs := parallel(len(members), func(i int) error {
+		return e.service.LeaveFromGroup(e.ctx, members[i])
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// the waitlist got three of the five freed places
+	if got := e.check(t, created.GroupID); got != len(waiting) {
+		t.Fatalf("group has %d members, want the %d promoted", got, len(waiting))
+	}
+
+	if _, err = e.repos.Waitlist.GetFirstByGroupId(e.ctx, created.GroupID); err == nil {
+		t.Fatal("waitlist is not empty after every entry was promoted")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,48 @@
+package homework
+
+import (
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"time"
+)
+
+type CreateHomeworkDTO struct {
+	GroupID     uint64
+	ScheduleID  *uint64
+	SubjectName *string
+	Description string
+	DueDate     time.Time
+}
+
+type UpdateHomeworkDTO struct {
+	ScheduleID  *uint64
+	SubjectName *string
+	Description string
+	DueDate     time.Time
+}
+
+// DetailsHomeworkDTO is a homework as seen by one user, Done tells whether that user has finished it
+type DetailsHomeworkDTO struct {
+	Homework
+	Done bool
+}
+
+// FilterDTO selects homework due from From to To inclusive
+type FilterDTO struct {
+	From time.Time
+	To   time.Time
+}
+
+// AgendaLessonDTO is a lesson occurrence with the homework due on it
+type AgendaLessonDTO struct {
+	schedule.DetailsScheduleDTO
+	Homework []DetailsHomeworkDTO
+}
+
+// AgendaDayDTO is a day of the schedule, Homework holds the deadlines not tied to any lesson of the day
+type AgendaDayDTO struct {
+	Date     time.Time
+	IsEven   bool
+	Lessons  []AgendaLessonDTO
+	Exams    []schedule.DetailsExamDTO
+	Homework []DetailsHomeworkDTO
+}
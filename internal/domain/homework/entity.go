@@ -0,0 +1,16 @@
+package homework
+
+import "time"
+
+// Homework is assigned to a group, ScheduleID ties it to the lesson taking place on DueDate
+type Homework struct {
+	HomeworkID  uint64
+	GroupID     uint64
+	ScheduleID  *uint64
+	SubjectName *string
+	Description string
+	DueDate     time.Time
+	CreatedBy   *uint64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,363 @@
+package homework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/tracing"
+	"time"
+)
+
+// defaultDays is the length of the period shown when the caller does not pass one
+const defaultDays = 7
+
+type GroupService interface {
+	Authorize(ctx context.Context, principal access.Principal, permission string, groupID uint64) (group.Group, error)
+	AuthorizeRead(ctx context.Context, principal access.Principal, permission string, groupID uint64) (group.Group, error)
+}
+
+type ScheduleService interface {
+	GetById(ctx context.Context, scheduleID uint64) (schedule.Schedule, error)
+	GetDaysByGroupId(ctx context.Context, groupID uint64, from, to time.Time) ([]schedule.DayDTO, error)
+	OccursOn(lesson schedule.Schedule, date time.Time) bool
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...postgresql.TxOption) error
+}
+
+type Repository interface {
+	Create(ctx context.Context, homework Homework) (uint64, error)
+	Update(ctx context.Context, homework Homework) error
+	Delete(ctx context.Context, homeworkID uint64) error
+	GetById(ctx context.Context, homeworkID uint64) (Homework, error)
+	GetAllByGroupId(ctx context.Context, groupID uint64, userID uint64, filter FilterDTO) ([]DetailsHomeworkDTO, error)
+	MarkDone(ctx context.Context, homeworkID uint64, userID uint64, doneAt time.Time) error
+	UnmarkDone(ctx context.Context, homeworkID uint64, userID uint64) error
+}
+
+type Service struct {
+	groupService    GroupService
+	scheduleService ScheduleService
+	auditService    AuditService
+	txManager       TxManager
+	repo            Repository
+}
+
+func NewService(
+	repo Repository,
+	txManager TxManager,
+	groupService GroupService,
+	scheduleService ScheduleService,
+	auditService AuditService,
+) *Service {
+
+	return &Service{
+		groupService:    groupService,
+		scheduleService: scheduleService,
+		auditService:    auditService,
+		txManager:       txManager,
+		repo:            repo,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, principal access.Principal, dto CreateHomeworkDTO) (uint64, error) {
+	ctx, span := tracing.Start(ctx, "homework.Service.Create")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.HomeworkWrite, dto.GroupID); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+
+	entity := Homework{
+		GroupID:     dto.GroupID,
+		ScheduleID:  dto.ScheduleID,
+		SubjectName: dto.SubjectName,
+		Description: dto.Description,
+		DueDate:     schedule.Date(dto.DueDate),
+		CreatedBy:   &principal.UserID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.attachLesson(ctx, &entity); err != nil {
+		return 0, err
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		homeworkID, err := s.repo.Create(ctx, entity)
+		if err != nil {
+			return fmt.Errorf("failed to create homework: %w", err)
+		}
+
+		entity.HomeworkID = homeworkID
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.HomeworkCreate,
+			TargetType: audit.TargetHomework,
+			TargetID:   homeworkID,
+			After:      entity,
+		})
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return entity.HomeworkID, nil
+}
+
+func (s *Service) Update(ctx context.Context, principal access.Principal, groupID, homeworkID uint64, dto UpdateHomeworkDTO) error {
+	ctx, span := tracing.Start(ctx, "homework.Service.Update")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.HomeworkWrite, groupID); err != nil {
+		return err
+	}
+
+	before, err := s.getById(ctx, groupID, homeworkID)
+	if err != nil {
+		return err
+	}
+
+	entity := before
+	entity.ScheduleID = dto.ScheduleID
+	entity.SubjectName = dto.SubjectName
+	entity.Description = dto.Description
+	entity.DueDate = schedule.Date(dto.DueDate)
+	entity.UpdatedAt = time.Now()
+
+	if err = s.attachLesson(ctx, &entity); err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, entity); err != nil {
+			return fmt.Errorf("failed to update homework: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.HomeworkUpdate,
+			TargetType: audit.TargetHomework,
+			TargetID:   homeworkID,
+			Before:     before,
+			After:      entity,
+		})
+	})
+}
+
+func (s *Service) Delete(ctx context.Context, principal access.Principal, groupID, homeworkID uint64) error {
+	ctx, span := tracing.Start(ctx, "homework.Service.Delete")
+	defer span.End()
+
+	if _, err := s.groupService.Authorize(ctx, principal, access.HomeworkWrite, groupID); err != nil {
+		return err
+	}
+
+	before, err := s.getById(ctx, groupID, homeworkID)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, homeworkID); err != nil {
+			return fmt.Errorf("failed to delete homework: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.HomeworkDelete,
+			TargetType: audit.TargetHomework,
+			TargetID:   homeworkID,
+			Before:     before,
+		})
+	})
+}
+
+// GetAllByGroupId lists the homework due in the period ordered by due date,
+// without a period it returns the deadlines of the coming week
+func (s *Service) GetAllByGroupId(ctx context.Context, principal access.Principal, groupID uint64, filter FilterDTO) ([]DetailsHomeworkDTO, error) {
+	ctx, span := tracing.Start(ctx, "homework.Service.GetAllByGroupId")
+	defer span.End()
+
+	if _, err := s.groupService.AuthorizeRead(ctx, principal, access.HomeworkWrite, groupID); err != nil {
+		return nil, err
+	}
+
+	filter, err := period(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	homework, err := s.repo.GetAllByGroupId(ctx, groupID, principal.UserID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get homework: %w", err)
+	}
+
+	return homework, nil
+}
+
+// GetAgenda merges the deadlines into the date-based schedule with its lessons and exams, homework
+// tied to a lesson is put under the lesson, the rest is listed for the day
+func (s *Service) GetAgenda(ctx context.Context, principal access.Principal, groupID uint64, filter FilterDTO) ([]AgendaDayDTO, error) {
+	ctx, span := tracing.Start(ctx, "homework.Service.GetAgenda")
+	defer span.End()
+
+	if _, err := s.groupService.AuthorizeRead(ctx, principal, access.HomeworkWrite, groupID); err != nil {
+		return nil, err
+	}
+
+	filter, err := period(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.scheduleService.GetDaysByGroupId(ctx, groupID, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	homework, err := s.repo.GetAllByGroupId(ctx, groupID, principal.UserID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get homework: %w", err)
+	}
+
+	agenda := make([]AgendaDayDTO, 0, len(days))
+
+	for _, day := range days {
+		agendaDay := AgendaDayDTO{
+			Date:   day.Date,
+			IsEven: day.IsEven,
+			Exams:  day.Exams,
+		}
+
+		attached := make(map[uint64]bool)
+
+		for _, lesson := range day.Lessons {
+			agendaLesson := AgendaLessonDTO{DetailsScheduleDTO: lesson}
+
+			for _, item := range homework {
+				if item.DueDate.Equal(day.Date) && item.ScheduleID != nil && *item.ScheduleID == lesson.ScheduleID {
+					agendaLesson.Homework = append(agendaLesson.Homework, item)
+					attached[item.HomeworkID] = true
+				}
+			}
+
+			agendaDay.Lessons = append(agendaDay.Lessons, agendaLesson)
+		}
+
+		for _, item := range homework {
+			if item.DueDate.Equal(day.Date) && !attached[item.HomeworkID] {
+				agendaDay.Homework = append(agendaDay.Homework, item)
+			}
+		}
+
+		agenda = append(agenda, agendaDay)
+	}
+
+	return agenda, nil
+}
+
+// SetDone marks the homework as done or not done by the caller, repeating a mark is not an error
+func (s *Service) SetDone(ctx context.Context, principal access.Principal, groupID, homeworkID uint64, done bool) error {
+	ctx, span := tracing.Start(ctx, "homework.Service.SetDone")
+	defer span.End()
+
+	if _, err := s.groupService.AuthorizeRead(ctx, principal, access.HomeworkWrite, groupID); err != nil {
+		return err
+	}
+
+	if _, err := s.getById(ctx, groupID, homeworkID); err != nil {
+		return err
+	}
+
+	var err error
+	if done {
+		err = s.repo.MarkDone(ctx, homeworkID, principal.UserID, time.Now())
+	} else {
+		err = s.repo.UnmarkDone(ctx, homeworkID, principal.UserID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update homework mark: %w", err)
+	}
+
+	return nil
+}
+
+// attachLesson checks that the lesson belongs to the group and takes place on the due date,
+// the subject of the homework defaults to the subject of the lesson
+func (s *Service) attachLesson(ctx context.Context, entity *Homework) error {
+	if entity.ScheduleID == nil {
+		return nil
+	}
+
+	lesson, err := s.scheduleService.GetById(ctx, *entity.ScheduleID)
+	if err != nil {
+		return err
+	}
+
+	if lesson.GroupID != entity.GroupID {
+		return domainErr.ErrLessonNotFound
+	}
+
+	if !s.scheduleService.OccursOn(lesson, entity.DueDate) {
+		return domainErr.ErrLessonNotOnDate
+	}
+
+	if entity.SubjectName == nil {
+		entity.SubjectName = &lesson.SubjectName
+	}
+
+	return nil
+}
+
+func (s *Service) getById(ctx context.Context, groupID, homeworkID uint64) (Homework, error) {
+	entity, err := s.repo.GetById(ctx, homeworkID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Homework{}, domainErr.ErrHomeworkNotFound
+		}
+
+		return Homework{}, fmt.Errorf("failed to get homework: %w", err)
+	}
+
+	if entity.GroupID != groupID {
+		return Homework{}, domainErr.ErrHomeworkNotFound
+	}
+
+	return entity, nil
+}
+
+// period fills in the coming week when the filter has no dates and checks the range
+func period(filter FilterDTO) (FilterDTO, error) {
+	if filter.From.IsZero() {
+		filter.From = time.Now()
+	}
+
+	filter.From = schedule.Date(filter.From)
+
+	if filter.To.IsZero() {
+		filter.To = filter.From.AddDate(0, 0, defaultDays-1)
+	}
+
+	filter.To = schedule.Date(filter.To)
+
+	if filter.To.Before(filter.From) || filter.To.Sub(filter.From) >= schedule.MaxDays*24*time.Hour {
+		return FilterDTO{}, domainErr.ErrInvalidDateRange
+	}
+
+	return filter, nil
+}
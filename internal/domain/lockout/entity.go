@@ -0,0 +1,47 @@
+package lockout
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	KindIP           = "ip"
+	KindEmail        = "email"
+	KindTelegramChat = "tg"
+	KindCheckIn      = "checkin"
+)
+
+type Key struct {
+	Kind  string
+	Value string
+}
+
+func IP(ip string) Key {
+	return Key{Kind: KindIP, Value: ip}
+}
+
+func Email(email string) Key {
+	return Key{Kind: KindEmail, Value: strings.ToLower(email)}
+}
+
+func TelegramChat(telegramChatID int64) Key {
+	return Key{Kind: KindTelegramChat, Value: strconv.FormatInt(telegramChatID, 10)}
+}
+
+// CheckIn counts the wrong codes a user submits to one check-in window
+func CheckIn(userID, windowID uint64) Key {
+	return Key{Kind: KindCheckIn, Value: strconv.FormatUint(userID, 10) + ":" + strconv.FormatUint(windowID, 10)}
+}
+
+func (k Key) String() string {
+	return k.Kind + ":" + k.Value
+}
+
+type Attempt struct {
+	Key         string
+	Failures    int
+	LockedUntil *time.Time
+	UpdatedAt   time.Time
+}
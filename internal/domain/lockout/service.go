@@ -0,0 +1,165 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/config"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/pkg/tracing"
+	"slices"
+	"time"
+)
+
+type Repository interface {
+	GetByKey(ctx context.Context, key string) (Attempt, error)
+	Increment(ctx context.Context, key string, now time.Time, resetBefore time.Time) (Attempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+	DeleteStale(ctx context.Context, resetBefore time.Time) (int64, error)
+}
+
+// LockedError is returned while a key is locked out, RetryAfter tells when the next attempt is allowed.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return domainErr.ErrTooManyAttempts.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return domainErr.ErrTooManyAttempts
+}
+
+type Service struct {
+	repo Repository
+	cfg  *config.Config
+}
+
+func NewService(repo Repository, cfg *config.Config) *Service {
+	return &Service{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+func (s *Service) Check(ctx context.Context, keys ...Key) error {
+	ctx, span := tracing.Start(ctx, "lockout.Service.Check")
+	defer span.End()
+
+	now := time.Now()
+
+	var retryAfter time.Duration
+
+	for _, key := range s.filter(keys) {
+		attempt, err := s.repo.GetByKey(ctx, key.String())
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+
+			return fmt.Errorf("failed to get login attempt: %w", err)
+		}
+
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			retryAfter = max(retryAfter, attempt.LockedUntil.Sub(now))
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (s *Service) RegisterFailure(ctx context.Context, keys ...Key) error {
+	ctx, span := tracing.Start(ctx, "lockout.Service.RegisterFailure")
+	defer span.End()
+
+	now := time.Now()
+
+	for _, key := range s.filter(keys) {
+		attempt, err := s.repo.Increment(ctx, key.String(), now, now.Add(-s.cfg.Lockout.Window))
+		if err != nil {
+			return fmt.Errorf("failed to register login failure: %w", err)
+		}
+
+		delay := s.lockDelay(key, attempt.Failures)
+		if delay == 0 {
+			continue
+		}
+
+		if err = s.repo.Lock(ctx, key.String(), now.Add(delay)); err != nil {
+			return fmt.Errorf("failed to lock key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) Reset(ctx context.Context, keys ...Key) error {
+	ctx, span := tracing.Start(ctx, "lockout.Service.Reset")
+	defer span.End()
+
+	for _, key := range s.filter(keys) {
+		if err := s.repo.Delete(ctx, key.String()); err != nil {
+			return fmt.Errorf("failed to reset login attempts: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// PurgeStale removes attempts that would be reset on the next failure anyway
+func (s *Service) PurgeStale(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "lockout.Service.PurgeStale")
+	defer span.End()
+
+	deleted, err := s.repo.DeleteStale(ctx, time.Now().Add(-s.cfg.Lockout.Window))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge stale login attempts: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// lockDelay doubles the lock for every failure above the threshold, capped by MaxDelay
+func (s *Service) lockDelay(key Key, failures int) time.Duration {
+	threshold := s.cfg.Lockout.MaxAttempts
+	if key.Kind == KindIP {
+		threshold = s.cfg.Lockout.IPMaxAttempts
+	}
+
+	if failures < threshold {
+		return 0
+	}
+
+	delay := s.cfg.Lockout.BaseDelay
+	for i := threshold; i < failures && delay < s.cfg.Lockout.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.cfg.Lockout.MaxDelay)
+}
+
+// filter drops IP keys of trusted clients such as the telegram bot, which logs in on behalf of many users
+func (s *Service) filter(keys []Key) []Key {
+	filtered := make([]Key, 0, len(keys))
+
+	for _, key := range keys {
+		if key.Value == "" {
+			continue
+		}
+
+		if key.Kind == KindIP && slices.Contains(s.cfg.Lockout.TrustedIPs, key.Value) {
+			continue
+		}
+
+		filtered = append(filtered, key)
+	}
+
+	return filtered
+}
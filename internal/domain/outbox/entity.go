@@ -0,0 +1,21 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	GroupWaitlistPromoted = "group.waitlist.promoted"
+	AnnouncementCreated   = "group.announcement.created"
+)
+
+// Event is a notification written in the transaction of the change it describes,
+// consumers such as the telegram bot deliver it and acknowledge it afterwards
+type Event struct {
+	EventID     uint64
+	EventType   string
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+	ProcessedAt *time.Time
+}
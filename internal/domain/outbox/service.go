@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/tclutin/classflow-api/pkg/tracing"
+	"time"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+type Repository interface {
+	Create(ctx context.Context, event Event) error
+	GetPending(ctx context.Context, limit int) ([]Event, error)
+	MarkProcessed(ctx context.Context, eventIDs []uint64, processedAt time.Time) error
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Publish writes the event, call it within the transaction of the change it describes,
+// so the event is stored if and only if the change is committed
+func (s *Service) Publish(ctx context.Context, eventType string, payload any) error {
+	ctx, span := tracing.Start(ctx, "outbox.Service.Publish")
+	defer span.End()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	event := Event{
+		EventType: eventType,
+		Payload:   data,
+		CreatedAt: time.Now(),
+	}
+
+	if err = s.repo.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// GetPending returns unacknowledged events in publishing order, an event is delivered
+// again until it is acknowledged, so consumers must tolerate duplicates
+func (s *Service) GetPending(ctx context.Context, limit int) ([]Event, error) {
+	ctx, span := tracing.Start(ctx, "outbox.Service.GetPending")
+	defer span.End()
+
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	return s.repo.GetPending(ctx, min(limit, maxLimit))
+}
+
+func (s *Service) Acknowledge(ctx context.Context, eventIDs []uint64) error {
+	ctx, span := tracing.Start(ctx, "outbox.Service.Acknowledge")
+	defer span.End()
+
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	return s.repo.MarkProcessed(ctx, eventIDs, time.Now())
+}
@@ -2,20 +2,51 @@ package schedule
 
 import (
 	"github.com/tclutin/classflow-api/internal/domain/edu"
+	"time"
 )
 
 type DetailsScheduleDTO struct {
+	ScheduleID  uint64
 	Type        string
 	SubjectName string
 	Teacher     string
 	Room        string
 	IsEven      bool
+	EveryWeek   bool
 	DayOfWeek   int
 	StartTime   string
 	EndTime     string
 	Building    edu.Building
 }
 
+type DetailsExamDTO struct {
+	ExamID      uint64
+	Term        string
+	Kind        string
+	SubjectName string
+	Examiner    string
+	Room        string
+	Date        time.Time
+	StartTime   string
+	EndTime     *string
+	Building    edu.Building
+}
+
+// DayDTO is one calendar date of the schedule with the lessons and exams taking place on it
+type DayDTO struct {
+	Date    time.Time
+	IsEven  bool
+	Lessons []DetailsScheduleDTO
+	Exams   []DetailsExamDTO
+}
+
 type FilterDTO struct {
 	IsEven string
 }
+
+// ExamFilterDTO selects exams of the term taking place from From to To inclusive, empty fields match everything
+type ExamFilterDTO struct {
+	Term string
+	From *time.Time
+	To   *time.Time
+}
@@ -1,6 +1,16 @@
 package schedule
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	ExamKindExam         = "exam"
+	ExamKindConsultation = "consultation"
+	ExamKindRetake       = "retake"
+)
 
 type Schedule struct {
 	ScheduleID      uint64
@@ -11,8 +21,88 @@ type Schedule struct {
 	Teacher         string
 	Room            string
 	IsEven          bool
-	DayOfWeek       int
-	StartTime       string
-	EndTime         string
-	CreatedAt       time.Time
+	// EveryWeek is set for lessons of a single-week schedule, they take place whatever the week parity
+	EveryWeek bool
+	DayOfWeek int
+	StartTime string
+	EndTime   string
+	CreatedAt time.Time
+}
+
+// Exam is a one-off dated event of an exam session, a term groups the exams of one session
+type Exam struct {
+	ExamID      uint64
+	GroupID     uint64
+	BuildingsID uint64
+	Term        string
+	Kind        string
+	SubjectName string
+	Examiner    string
+	Room        string
+	Date        time.Time
+	StartTime   string
+	// EndTime is nil when only the start of the exam is known
+	EndTime   *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Term numbers the weeks for their parity: the week Start falls in is the first, odd one.
+// A zero Start means September 1 of the academic year the date falls in
+type Term struct {
+	Start time.Time
+}
+
+// IsEvenWeek tells the parity of the week the date falls in
+func (t Term) IsEvenWeek(date time.Time) bool {
+	date = Date(date)
+
+	start := t.Start
+	if start.IsZero() {
+		year := date.Year()
+		if date.Month() < time.September {
+			year--
+		}
+
+		start = time.Date(year, time.September, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	start = Date(start)
+	monday := start.AddDate(0, 0, 1-Weekday(start))
+
+	// weeks before the start are counted back from it, so the parity keeps alternating
+	week := int(math.Floor(date.Sub(monday).Hours() / 24 / 7))
+
+	return week%2 != 0
+}
+
+// OccursOn reports whether the lesson takes place on the date
+func (t Term) OccursOn(lesson Schedule, date time.Time) bool {
+	return lesson.DayOfWeek == Weekday(date) && (lesson.EveryWeek || lesson.IsEven == t.IsEvenWeek(date))
+}
+
+// Weekday numbers the days of the week from 1 for Monday to 7 for Sunday, like DayOfWeek
+func Weekday(date time.Time) int {
+	if date.Weekday() == time.Sunday {
+		return 7
+	}
+
+	return int(date.Weekday())
+}
+
+// Date truncates the time to midnight UTC, dates of lessons and deadlines carry no time zone
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// At combines a date with a time of day written as 15:04 or 15:04:05
+func At(date time.Time, clock string) (time.Time, error) {
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		t, err := time.Parse(layout, clock)
+		if err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time of day %q", clock)
 }
@@ -1,19 +1,133 @@
 package schedule
 
-import "context"
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/pkg/tracing"
+	"slices"
+	"time"
+)
+
+// MaxDays limits the date range of GetDaysByGroupId
+const MaxDays = 31
 
 type Repository interface {
+	GetById(ctx context.Context, scheduleID uint64) (Schedule, error)
 	GetSchedulesByGroupId(ctx context.Context, filter FilterDTO, groupID uint64) ([]DetailsScheduleDTO, error)
+	GetExamsByGroupId(ctx context.Context, filter ExamFilterDTO, groupID uint64) ([]DetailsExamDTO, error)
 }
 
 type Service struct {
 	repo Repository
+	term Term
+}
+
+func NewService(repo Repository, term Term) *Service {
+	return &Service{
+		repo: repo,
+		term: term,
+	}
 }
 
-func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+// OccursOn reports whether the lesson takes place on the date in the configured term
+func (s *Service) OccursOn(lesson Schedule, date time.Time) bool {
+	return s.term.OccursOn(lesson, date)
+}
+
+func (s *Service) GetById(ctx context.Context, scheduleID uint64) (Schedule, error) {
+	ctx, span := tracing.Start(ctx, "schedule.Service.GetById")
+	defer span.End()
+
+	lesson, err := s.repo.GetById(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Schedule{}, domainErr.ErrLessonNotFound
+		}
+
+		return Schedule{}, fmt.Errorf("failed to get lesson: %w", err)
+	}
+
+	return lesson, nil
 }
 
 func (s *Service) GetSchedulesByGroupId(ctx context.Context, filter FilterDTO, groupID uint64) ([]DetailsScheduleDTO, error) {
+	ctx, span := tracing.Start(ctx, "schedule.Service.GetSchedulesByGroupId")
+	defer span.End()
+
 	return s.repo.GetSchedulesByGroupId(ctx, filter, groupID)
 }
+
+// GetExamsByGroupId returns the exams ordered by date and start time
+func (s *Service) GetExamsByGroupId(ctx context.Context, filter ExamFilterDTO, groupID uint64) ([]DetailsExamDTO, error) {
+	ctx, span := tracing.Start(ctx, "schedule.Service.GetExamsByGroupId")
+	defer span.End()
+
+	return s.repo.GetExamsByGroupId(ctx, filter, groupID)
+}
+
+// GetDaysByGroupId lays the weekly schedule out on every date from "from" to "to" inclusive and adds
+// the exams of those dates, lessons and exams of a day are ordered by their start time.
+// A zero "from" is today and a zero "to" makes the longest range allowed
+func (s *Service) GetDaysByGroupId(ctx context.Context, groupID uint64, from, to time.Time) ([]DayDTO, error) {
+	ctx, span := tracing.Start(ctx, "schedule.Service.GetDaysByGroupId")
+	defer span.End()
+
+	if from.IsZero() {
+		from = time.Now()
+	}
+
+	from = Date(from)
+
+	if to.IsZero() {
+		to = from.AddDate(0, 0, MaxDays-1)
+	}
+
+	to = Date(to)
+
+	if to.Before(from) || to.Sub(from) >= MaxDays*24*time.Hour {
+		return nil, domainErr.ErrInvalidDateRange
+	}
+
+	lessons, err := s.repo.GetSchedulesByGroupId(ctx, FilterDTO{}, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	slices.SortFunc(lessons, func(a, b DetailsScheduleDTO) int {
+		return cmp.Or(cmp.Compare(a.StartTime, b.StartTime), cmp.Compare(a.ScheduleID, b.ScheduleID))
+	})
+
+	exams, err := s.repo.GetExamsByGroupId(ctx, ExamFilterDTO{From: &from, To: &to}, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exams: %w", err)
+	}
+
+	var days []DayDTO
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := DayDTO{
+			Date:   date,
+			IsEven: s.term.IsEvenWeek(date),
+		}
+
+		for _, lesson := range lessons {
+			if lesson.DayOfWeek == Weekday(date) && (lesson.EveryWeek || lesson.IsEven == day.IsEven) {
+				day.Lessons = append(day.Lessons, lesson)
+			}
+		}
+
+		for _, exam := range exams {
+			if exam.Date.Equal(date) {
+				day.Exams = append(day.Exams, exam)
+			}
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
@@ -0,0 +1,267 @@
+package schedule_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/internal/repository/memory"
+	"testing"
+	"time"
+)
+
+const groupID = 1
+
+// term starts on Monday 10 February 2025
+var term = schedule.Term{Start: date(2025, time.February, 10)}
+
+// setup returns a schedule service of the term on top of a fresh in-memory store holding the lessons and the exams
+func setup(t *testing.T, lessons []schedule.Schedule, exams []schedule.Exam) (context.Context, *schedule.Service) {
+	t.Helper()
+
+	ctx := context.Background()
+	repos := memory.NewRepositories(memory.NewStore())
+
+	typeOfSubject, err := repos.Edu.AddTypeOfSubject(ctx, "Лекция")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	building, err := repos.Edu.AddBuilding(ctx, edu.Building{Name: "Главный корпус"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range lessons {
+		lessons[i].GroupID = groupID
+		lessons[i].TypeOfSubjectID = typeOfSubject.TypeOfSubjectID
+		lessons[i].BuildingsID = building.BuildingID
+	}
+
+	for i := range exams {
+		exams[i].GroupID = groupID
+		exams[i].BuildingsID = building.BuildingID
+	}
+
+	if err = repos.Schedule.Create(ctx, lessons); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repos.Schedule.CreateExams(ctx, exams); err != nil {
+		t.Fatal(err)
+	}
+
+	return ctx, schedule.NewService(repos.Schedule, term)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestService_GetDaysByGroupId(t *testing.T) {
+	ctx, service := setup(t,
+		[]schedule.Schedule{
+			{SubjectName: "Физика", IsEven: true, DayOfWeek: 1, StartTime: "11:00", EndTime: "12:30"},
+			{SubjectName: "Алгебра", IsEven: true, DayOfWeek: 1, StartTime: "09:00", EndTime: "10:30"},
+			{SubjectName: "История", IsEven: false, DayOfWeek: 1, StartTime: "09:00", EndTime: "10:30"},
+			{SubjectName: "Химия", IsEven: true, DayOfWeek: 3, StartTime: "13:00", EndTime: "14:30"},
+			{SubjectName: "Английский", EveryWeek: true, DayOfWeek: 2, StartTime: "09:00", EndTime: "10:30"},
+		},
+		[]schedule.Exam{
+			{SubjectName: "Алгебра", Kind: schedule.ExamKindExam, Term: "spring", Date: date(2025, time.March, 5), StartTime: "10:00"},
+			{SubjectName: "Физика", Kind: schedule.ExamKindExam, Term: "spring", Date: date(2025, time.April, 1), StartTime: "10:00"},
+		})
+
+	// 3 March 2025 is Monday of the 4th week of the term
+	days, err := service.GetDaysByGroupId(ctx, groupID, date(2025, time.March, 3).Add(15*time.Hour), date(2025, time.March, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(days) != 14 {
+		t.Fatalf("got %d days, want 14", len(days))
+	}
+
+	subjects := func(day schedule.DayDTO) []string {
+		var names []string
+
+		for _, lesson := range day.Lessons {
+			names = append(names, lesson.SubjectName)
+		}
+
+		for _, exam := range day.Exams {
+			names = append(names, "exam "+exam.SubjectName)
+		}
+
+		return names
+	}
+
+	tests := []struct {
+		day    int
+		isEven bool
+		want   []string
+	}{
+		{day: 0, isEven: true, want: []string{"Алгебра", "Физика"}},
+		{day: 1, isEven: true, want: []string{"Английский"}},
+		{day: 2, isEven: true, want: []string{"Химия", "exam Алгебра"}},
+		{day: 7, isEven: false, want: []string{"История"}},
+		{day: 8, isEven: false, want: []string{"Английский"}},
+		{day: 9, isEven: false},
+		{day: 13, isEven: false},
+	}
+
+	for _, tt := range tests {
+		day := days[tt.day]
+
+		if !day.Date.Equal(date(2025, time.March, 3+tt.day)) || day.IsEven != tt.isEven {
+			t.Fatalf("day %d: got %s with is_even %t", tt.day, day.Date, day.IsEven)
+		}
+
+		got := subjects(day)
+
+		if len(got) != len(tt.want) {
+			t.Fatalf("day %d: got %v, want %v", tt.day, got, tt.want)
+		}
+
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("day %d: got %v, want %v", tt.day, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestService_GetDaysByGroupIdRange(t *testing.T) {
+	ctx, service := setup(t, nil, nil)
+
+	from := date(2025, time.March, 3)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		wantDays int
+		wantErr  error
+	}{
+		{name: "single day", from: from, to: from, wantDays: 1},
+		{name: "longest range", from: from, to: from.AddDate(0, 0, schedule.MaxDays-1), wantDays: schedule.MaxDays},
+		{name: "default end", from: from, wantDays: schedule.MaxDays},
+		{name: "end before start", from: from, to: from.AddDate(0, 0, -1), wantErr: domainErr.ErrInvalidDateRange},
+		{name: "too long", from: from, to: from.AddDate(0, 0, schedule.MaxDays), wantErr: domainErr.ErrInvalidDateRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, err := service.GetDaysByGroupId(ctx, groupID, tt.from, tt.to)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if len(days) != tt.wantDays {
+				t.Fatalf("got %d days, want %d", len(days), tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestService_GetById(t *testing.T) {
+	ctx, service := setup(t, []schedule.Schedule{{SubjectName: "Алгебра", DayOfWeek: 1, StartTime: "09:00", EndTime: "10:30"}}, nil)
+
+	lessons, err := service.GetSchedulesByGroupId(ctx, schedule.FilterDTO{}, groupID)
+	if err != nil || len(lessons) != 1 {
+		t.Fatalf("expected one lesson, got %v: %v", lessons, err)
+	}
+
+	lesson, err := service.GetById(ctx, lessons[0].ScheduleID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lesson.SubjectName != "Алгебра" {
+		t.Fatalf("got lesson %q", lesson.SubjectName)
+	}
+
+	if _, err = service.GetById(ctx, lessons[0].ScheduleID+1000); !errors.Is(err, domainErr.ErrLessonNotFound) {
+		t.Fatalf("expected %v, got %v", domainErr.ErrLessonNotFound, err)
+	}
+}
+
+func TestTerm_IsEvenWeek(t *testing.T) {
+	tests := []struct {
+		name string
+		term schedule.Term
+		date time.Time
+		want bool
+	}{
+		{name: "first week", term: term, date: date(2025, time.February, 16), want: false},
+		{name: "second week", term: term, date: date(2025, time.February, 17), want: true},
+		{name: "start in the middle of the week", term: schedule.Term{Start: date(2025, time.February, 12)}, date: date(2025, time.February, 10), want: false},
+		{name: "week before the start", term: term, date: date(2025, time.February, 9), want: true},
+		{name: "time of day is ignored", term: term, date: date(2025, time.February, 16).Add(23 * time.Hour), want: false},
+		// 1 September 2025 is Monday
+		{name: "default start", date: date(2025, time.September, 8), want: true},
+		{name: "spring counts from the previous September", date: date(2026, time.March, 9), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.term.IsEvenWeek(tt.date); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerm_OccursOn(t *testing.T) {
+	// 17 February 2025 is Monday of the second, even week and 24 February of the third, odd one
+	even, odd := date(2025, time.February, 17), date(2025, time.February, 24)
+
+	tests := []struct {
+		name   string
+		lesson schedule.Schedule
+		date   time.Time
+		want   bool
+	}{
+		{name: "even lesson in an even week", lesson: schedule.Schedule{IsEven: true, DayOfWeek: 1}, date: even, want: true},
+		{name: "even lesson in an odd week", lesson: schedule.Schedule{IsEven: true, DayOfWeek: 1}, date: odd},
+		{name: "odd lesson in an odd week", lesson: schedule.Schedule{DayOfWeek: 1}, date: odd, want: true},
+		{name: "every week lesson in an even week", lesson: schedule.Schedule{EveryWeek: true, DayOfWeek: 1}, date: even, want: true},
+		{name: "every week lesson in an odd week", lesson: schedule.Schedule{EveryWeek: true, DayOfWeek: 1}, date: odd, want: true},
+		{name: "other day", lesson: schedule.Schedule{EveryWeek: true, DayOfWeek: 2}, date: even},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := term.OccursOn(tt.lesson, tt.date); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAt(t *testing.T) {
+	day := date(2025, time.March, 3)
+
+	tests := []struct {
+		clock   string
+		want    time.Time
+		wantErr bool
+	}{
+		{clock: "09:30", want: day.Add(9*time.Hour + 30*time.Minute)},
+		{clock: "09:30:15", want: day.Add(9*time.Hour + 30*time.Minute + 15*time.Second)},
+		{clock: "9.30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := schedule.At(day, tt.clock)
+
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: unexpected error %v", tt.clock, err)
+		}
+
+		if !got.Equal(tt.want) {
+			t.Fatalf("%s: got %s, want %s", tt.clock, got, tt.want)
+		}
+	}
+}
@@ -2,49 +2,102 @@ package domain
 
 import (
 	"github.com/tclutin/classflow-api/internal/config"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
 	"github.com/tclutin/classflow-api/internal/domain/auth"
 	"github.com/tclutin/classflow-api/internal/domain/edu"
 	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"github.com/tclutin/classflow-api/internal/domain/lockout"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
 	"github.com/tclutin/classflow-api/internal/domain/schedule"
 	"github.com/tclutin/classflow-api/internal/domain/user"
 	"github.com/tclutin/classflow-api/internal/repository"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
 	"github.com/tclutin/classflow-api/pkg/jwt"
 	"log/slog"
 )
 
 type Services struct {
-	Auth     *auth.Service
-	User     *user.Service
-	Schedule *schedule.Service
-	Edu      *edu.Service
-	Group    *group.Service
+	Auth         *auth.Service
+	User         *user.Service
+	Schedule     *schedule.Service
+	Edu          *edu.Service
+	Group        *group.Service
+	Lockout      *lockout.Service
+	Access       *access.Service
+	Audit        *audit.Service
+	Outbox       *outbox.Service
+	Announcement *announcement.Service
+	Homework     *homework.Service
+	Attendance   *attendance.Service
 }
 
 func NewServices(
 	logger *slog.Logger,
 	tokenManager jwt.Manager,
 	repositories *repository.Repositories,
+	txManager *postgresql.TxManager,
 	cfg *config.Config,
 ) *Services {
 
-	userService := user.NewService(repositories.User)
-	authService := auth.NewService(userService, tokenManager, cfg)
-	scheduleService := schedule.NewService(repositories.Schedule)
+	auditService := audit.NewService(repositories.Audit)
+	outboxService := outbox.NewService(repositories.Outbox)
+	userService := user.NewService(repositories.User, txManager, auditService)
+	lockoutService := lockout.NewService(repositories.Lockout, cfg)
+	authService := auth.NewService(userService, lockoutService, tokenManager, cfg)
+	scheduleService := schedule.NewService(repositories.Schedule, schedule.Term{Start: cfg.Schedule.TermStart})
 	eduService := edu.NewService(repositories.Edu)
+	accessService := access.NewService(repositories.Access, txManager, userService, eduService, auditService)
 	groupService := group.NewService(logger,
 		repositories.Group,
+		txManager,
 		repositories.Member,
+		repositories.Waitlist,
 		repositories.User,
 		scheduleService,
 		repositories.Schedule,
 		userService,
-		eduService)
+		eduService,
+		accessService,
+		auditService,
+		outboxService)
+	announcementService := announcement.NewService(
+		repositories.Announcement,
+		txManager,
+		groupService,
+		auditService,
+		outboxService)
+	homeworkService := homework.NewService(
+		repositories.Homework,
+		txManager,
+		groupService,
+		scheduleService,
+		auditService)
+	attendanceService := attendance.NewService(
+		repositories.Attendance,
+		txManager,
+		repositories.Member,
+		groupService,
+		scheduleService,
+		eduService,
+		lockoutService,
+		auditService)
 
 	return &Services{
-		User:     userService,
-		Auth:     authService,
-		Schedule: scheduleService,
-		Edu:      eduService,
-		Group:    groupService,
+		User:         userService,
+		Auth:         authService,
+		Schedule:     scheduleService,
+		Edu:          eduService,
+		Group:        groupService,
+		Lockout:      lockoutService,
+		Access:       accessService,
+		Audit:        auditService,
+		Outbox:       outboxService,
+		Announcement: announcementService,
+		Homework:     homeworkService,
+		Attendance:   attendanceService,
 	}
 }
@@ -5,15 +5,19 @@ import (
 )
 
 const (
-	Admin   = "admin"
-	Leader  = "leader"
-	Student = "student"
+	Admin        = "admin"
+	FacultyAdmin = "faculty_admin"
+	Leader       = "leader"
+	Student      = "student"
+	// FormerAdmin is a faculty admin whose last faculty was revoked, it keeps the email sign-in
+	// without any permissions and may be assigned a faculty again
+	FormerAdmin = "former_admin"
 )
 
 type User struct {
 	UserID               uint64
 	Email                *string
-	PasswordHash         *string
+	PasswordHash         *string `json:"-"`
 	Role                 string
 	FullName             *string
 	TelegramUsername     *string
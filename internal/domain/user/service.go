@@ -5,9 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
 	domenErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/tracing"
 )
 
+type AuditService interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...postgresql.TxOption) error
+}
+
 type Repository interface {
 	Create(ctx context.Context, user User) (uint64, error)
 	Update(ctx context.Context, user User) error
@@ -17,29 +28,74 @@ type Repository interface {
 }
 
 type Service struct {
-	repo Repository
+	auditService AuditService
+	txManager    TxManager
+	repo         Repository
 }
 
-func NewService(repo Repository) *Service {
+func NewService(repo Repository, txManager TxManager, auditService AuditService) *Service {
 	return &Service{
-		repo: repo,
+		auditService: auditService,
+		txManager:    txManager,
+		repo:         repo,
 	}
 }
 
 func (s *Service) Create(ctx context.Context, user User) (uint64, error) {
-	userID, err := s.repo.Create(ctx, user)
+	ctx, span := tracing.Start(ctx, "user.Service.Create")
+	defer span.End()
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		userID, err := s.repo.Create(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		user.UserID = userID
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.UserCreate,
+			TargetType: audit.TargetUser,
+			TargetID:   userID,
+			After:      user,
+		})
+	})
+
 	if err != nil {
-		return 0, fmt.Errorf("failed to create user: %w", err)
+		return 0, err
 	}
 
-	return userID, nil
+	return user.UserID, nil
 }
 
 func (s *Service) Update(ctx context.Context, user User) error {
-	return s.repo.Update(ctx, user)
+	ctx, span := tracing.Start(ctx, "user.Service.Update")
+	defer span.End()
+
+	before, err := s.GetById(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		return s.auditService.Record(ctx, audit.Entry{
+			Action:     audit.UserUpdate,
+			TargetType: audit.TargetUser,
+			TargetID:   user.UserID,
+			Before:     before,
+			After:      user,
+		})
+	})
 }
 
 func (s *Service) UpdatePartial(ctx context.Context, dto PartialUpdateUserDTO, userID uint64) error {
+	ctx, span := tracing.Start(ctx, "user.Service.UpdatePartial")
+	defer span.End()
+
 	user, err := s.GetById(ctx, userID)
 	if err != nil {
 		return err
@@ -61,6 +117,9 @@ func (s *Service) UpdatePartial(ctx context.Context, dto PartialUpdateUserDTO, u
 }
 
 func (s *Service) GetByTelegramChatId(ctx context.Context, telegramChatID int64) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.GetByTelegramChatId")
+	defer span.End()
+
 	user, err := s.repo.GetByTelegramChatId(ctx, telegramChatID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -73,6 +132,9 @@ func (s *Service) GetByTelegramChatId(ctx context.Context, telegramChatID int64)
 }
 
 func (s *Service) GetByEmail(ctx context.Context, email string) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.GetByEmail")
+	defer span.End()
+
 	user, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -85,6 +147,9 @@ func (s *Service) GetByEmail(ctx context.Context, email string) (User, error) {
 }
 
 func (s *Service) GetById(ctx context.Context, userID uint64) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.GetById")
+	defer span.End()
+
 	user, err := s.repo.GetById(ctx, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -0,0 +1,176 @@
+package user_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	domainErr "github.com/tclutin/classflow-api/internal/domain/errors"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/internal/repository/memory"
+	"testing"
+	"time"
+)
+
+// setup returns a user service on top of a fresh in-memory store
+func setup() (context.Context, *user.Service, *memory.Repositories) {
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+
+	service := user.NewService(repos.User, memory.NewTxManager(store), audit.NewService(repos.Audit))
+
+	return context.Background(), service, repos
+}
+
+func history(t *testing.T, ctx context.Context, repos *memory.Repositories, userID uint64) []audit.Record {
+	t.Helper()
+
+	records, err := repos.Audit.GetAll(ctx, audit.FilterDTO{TargetType: audit.TargetUser, TargetID: &userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return records
+}
+
+func TestService_Create(t *testing.T) {
+	ctx, service, repos := setup()
+
+	email := "admin@classflow.test"
+
+	userID, err := service.Create(ctx, user.User{Email: &email, Role: user.Admin, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := service.GetByEmail(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.UserID != userID || created.Role != user.Admin {
+		t.Fatalf("got user %d with role %s, want %d with role %s", created.UserID, created.Role, userID, user.Admin)
+	}
+
+	records := history(t, ctx, repos, userID)
+	if len(records) != 1 || records[0].Action != audit.UserCreate {
+		t.Fatalf("expected one %s record, got %+v", audit.UserCreate, records)
+	}
+}
+
+func TestService_Lookup(t *testing.T) {
+	ctx, service, _ := setup()
+
+	email := "student@classflow.test"
+	chatID := int64(100500)
+
+	userID, err := service.Create(ctx, user.User{Email: &email, TelegramChatID: &chatID, Role: user.Student, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		lookup  func() (user.User, error)
+		wantErr error
+	}{
+		{
+			name:   "by id",
+			lookup: func() (user.User, error) { return service.GetById(ctx, userID) },
+		},
+		{
+			name:   "by email",
+			lookup: func() (user.User, error) { return service.GetByEmail(ctx, email) },
+		},
+		{
+			name:   "by telegram chat id",
+			lookup: func() (user.User, error) { return service.GetByTelegramChatId(ctx, chatID) },
+		},
+		{
+			name:    "missing id",
+			lookup:  func() (user.User, error) { return service.GetById(ctx, userID+1000) },
+			wantErr: domainErr.ErrUserNotFound,
+		},
+		{
+			name:    "missing email",
+			lookup:  func() (user.User, error) { return service.GetByEmail(ctx, "missing@classflow.test") },
+			wantErr: domainErr.ErrUserNotFound,
+		},
+		{
+			name:    "missing telegram chat id",
+			lookup:  func() (user.User, error) { return service.GetByTelegramChatId(ctx, chatID+1) },
+			wantErr: domainErr.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := tt.lookup()
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if found.UserID != userID {
+				t.Fatalf("got user %d, want %d", found.UserID, userID)
+			}
+		})
+	}
+}
+
+func TestService_UpdatePartial(t *testing.T) {
+	ctx, service, repos := setup()
+
+	fullName := "Иванов Иван"
+	delay := int64(15)
+
+	userID, err := service.Create(ctx, user.User{FullName: &fullName, NotificationDelay: &delay, Role: user.Student, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabled := true
+
+	if err = service.UpdatePartial(ctx, user.PartialUpdateUserDTO{NotificationsEnabled: &enabled}, userID); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := service.GetById(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.NotificationsEnabled == nil || !*updated.NotificationsEnabled {
+		t.Fatalf("notifications are not enabled: %v", updated.NotificationsEnabled)
+	}
+
+	if updated.FullName == nil || *updated.FullName != fullName || updated.NotificationDelay == nil || *updated.NotificationDelay != delay {
+		t.Fatalf("fields left out of the update have changed: %+v", updated)
+	}
+
+	records := history(t, ctx, repos, userID)
+	if len(records) != 2 || records[0].Action != audit.UserUpdate {
+		t.Fatalf("expected the update to be audited last, got %+v", records)
+	}
+
+	var before user.User
+	if err = json.Unmarshal(records[0].Before, &before); err != nil {
+		t.Fatal(err)
+	}
+
+	if before.NotificationsEnabled != nil {
+		t.Fatalf("audit record keeps the state after the update as the state before it")
+	}
+
+	err = service.UpdatePartial(ctx, user.PartialUpdateUserDTO{FullName: &fullName}, userID+1000)
+	if !errors.Is(err, domainErr.ErrUserNotFound) {
+		t.Fatalf("expected %v for a missing user, got %v", domainErr.ErrUserNotFound, err)
+	}
+}
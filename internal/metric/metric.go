@@ -2,37 +2,177 @@ package metric
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"sync"
+	"time"
 )
 
+const namespace = "classflow_api"
+
+// maxScheduleGroups caps the number of distinct group_id label values, the rest are counted as "other"
+const maxScheduleGroups = 200
+
+const otherLabel = "other"
+
 var httpRequestsCounter = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
-		Namespace: "classflow_api",
+		Namespace: namespace,
 		Subsystem: "http",
 		Name:      "requests_total",
-		Help:      "Total HTTP requests",
+		Help:      "Total HTTP requests by method, route and status code",
+	},
+	[]string{"method", "route", "status"},
+)
+
+var httpRequestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status code",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	},
+	[]string{"method", "route", "status"},
+)
+
+var httpRequestsInFlight = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served",
 	},
-	[]string{"endpoint"},
 )
 
 var scheduleRequestCounter = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
-		Namespace: "classflow_api",
+		Namespace: namespace,
 		Subsystem: "http",
-		Name:      "schedule_request_total",
-		Help:      "Total HTTP requests by path and group",
+		Name:      "schedule_requests_total",
+		Help:      "Total successful schedule requests by group, limited to a fixed number of groups",
+	},
+	[]string{"group_id"},
+)
+
+var rateLimitRejectedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limit_rejected_total",
+		Help:      "Total HTTP requests rejected by rate limiter by policy and principal kind",
+	},
+	[]string{"policy", "principal"},
+)
+
+var signupsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "signups_total",
+		Help:      "Total user signups by channel",
+	},
+	[]string{"channel"},
+)
+
+var groupMembershipCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "group_membership_changes_total",
+		Help:      "Total group joins and leaves",
+	},
+	[]string{"action"},
+)
+
+var scheduleUploadsCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "schedule_uploads_total",
+		Help:      "Total uploaded group schedules",
 	},
-	[]string{"endpoint", "group_name"},
 )
 
+var scheduleGroups = newBoundedLabels(maxScheduleGroups)
+
 func init() {
 	prometheus.MustRegister(httpRequestsCounter)
+	prometheus.MustRegister(httpRequestDuration)
+	prometheus.MustRegister(httpRequestsInFlight)
 	prometheus.MustRegister(scheduleRequestCounter)
+	prometheus.MustRegister(rateLimitRejectedCounter)
+	prometheus.MustRegister(signupsCounter)
+	prometheus.MustRegister(groupMembershipCounter)
+	prometheus.MustRegister(scheduleUploadsCounter)
+}
+
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+
+	httpRequestsCounter.WithLabelValues(method, route, code).Inc()
+	httpRequestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func IncRequestsInFlight() {
+	httpRequestsInFlight.Inc()
 }
 
-func IncRequestCounter(endpoint string) {
-	httpRequestsCounter.WithLabelValues(endpoint).Inc()
+func DecRequestsInFlight() {
+	httpRequestsInFlight.Dec()
 }
 
-func IncScheduleRequestCounter(endpoint, groupName string) {
-	scheduleRequestCounter.WithLabelValues(endpoint, groupName).Inc()
+func IncScheduleRequestCounter(groupID uint64) {
+	scheduleRequestCounter.WithLabelValues(scheduleGroups.label(strconv.FormatUint(groupID, 10))).Inc()
+}
+
+func IncRateLimitRejectedCounter(policy, principal string) {
+	rateLimitRejectedCounter.WithLabelValues(policy, principal).Inc()
+}
+
+func IncSignupsCounter(channel string) {
+	signupsCounter.WithLabelValues(channel).Inc()
+}
+
+func IncGroupJoinsCounter() {
+	groupMembershipCounter.WithLabelValues("join").Inc()
+}
+
+func IncGroupLeavesCounter() {
+	groupMembershipCounter.WithLabelValues("leave").Inc()
+}
+
+func IncScheduleUploadsCounter() {
+	scheduleUploadsCounter.Inc()
+}
+
+// boundedLabels remembers the first max label values it sees and maps any other value to "other",
+// which keeps the number of series fixed no matter how many groups exist
+type boundedLabels struct {
+	mu     sync.Mutex
+	max    int
+	values map[string]struct{}
+}
+
+func newBoundedLabels(max int) *boundedLabels {
+	return &boundedLabels{
+		max:    max,
+		values: make(map[string]struct{}, max),
+	}
+}
+
+func (b *boundedLabels) label(value string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.values[value]; ok {
+		return value
+	}
+
+	if len(b.values) >= b.max {
+		return otherLabel
+	}
+
+	b.values[value] = struct{}{}
+
+	return value
 }
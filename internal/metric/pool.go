@@ -0,0 +1,72 @@
+package metric
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exports pgxpool statistics, the values are read from the pool on every scrape
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+	newConnsCount        *prometheus.Desc
+	maxLifetimeDestroy   *prometheus.Desc
+	maxIdleDestroy       *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool, name string) *PoolCollector {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "db_pool", metric),
+			help,
+			nil,
+			prometheus.Labels{"pool": name},
+		)
+	}
+
+	return &PoolCollector{
+		pool:                 pool,
+		acquiredConns:        desc("acquired_conns", "Connections currently acquired from the pool"),
+		idleConns:            desc("idle_conns", "Idle connections in the pool"),
+		constructingConns:    desc("constructing_conns", "Connections currently being established"),
+		totalConns:           desc("total_conns", "Total connections in the pool"),
+		maxConns:             desc("max_conns", "Maximum size of the pool"),
+		acquireCount:         desc("acquires_total", "Total successful acquires from the pool"),
+		acquireDuration:      desc("acquire_wait_seconds_total", "Total time spent waiting for successful acquires"),
+		emptyAcquireCount:    desc("empty_acquires_total", "Total acquires that had to wait for a connection"),
+		canceledAcquireCount: desc("canceled_acquires_total", "Total acquires canceled by their context"),
+		newConnsCount:        desc("new_conns_total", "Total connections opened"),
+		maxLifetimeDestroy:   desc("max_lifetime_destroys_total", "Total connections closed because of MaxConnLifetime"),
+		maxIdleDestroy:       desc("max_idle_destroys_total", "Total connections closed because of MaxConnIdleTime"),
+	}
+}
+
+func (p *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(p, ch)
+}
+
+func (p *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := p.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(p.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(p.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(p.constructingConns, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(p.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(p.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(p.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(p.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.canceledAcquireCount, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.newConnsCount, prometheus.CounterValue, float64(stat.NewConnsCount()))
+	ch <- prometheus.MustNewConstMetric(p.maxLifetimeDestroy, prometheus.CounterValue, float64(stat.MaxLifetimeDestroyCount()))
+	ch <- prometheus.MustNewConstMetric(p.maxIdleDestroy, prometheus.CounterValue, float64(stat.MaxIdleDestroyCount()))
+}
@@ -3,97 +3,227 @@ package migrator
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
 	"github.com/tclutin/classflow-api/internal/domain/user"
 	"github.com/tclutin/classflow-api/pkg/hash"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path"
+	"sort"
 	"time"
 )
 
+const (
+	MigrationsDir = "migrations"
+	SeedsDir      = "seeds"
+)
+
+// Migration applies schema migrations through goose. Changes are made under a postgres advisory lock,
+// so replicas starting at the same time apply every migration exactly once
 type Migration struct {
-	logger *slog.Logger
-	pool   *pgxpool.Pool
+	logger   *slog.Logger
+	pool     *pgxpool.Pool
+	provider *goose.Provider
+	seedsDir string
 }
 
-func New(pool *pgxpool.Pool, logger *slog.Logger) *Migration {
+func New(pool *pgxpool.Pool, logger *slog.Logger) (*Migration, error) {
+	return NewWithDirs(pool, logger, MigrationsDir, SeedsDir)
+}
+
+// NewWithDirs reads migrations and seeds from the given directories instead of the ones relative
+// to the working directory, e.g. when running from a package directory
+func NewWithDirs(pool *pgxpool.Pool, logger *slog.Logger, migrationsDir, seedsDir string) (*Migration, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+
+	provider, err := goose.NewProvider(
+		goose.DialectPostgres,
+		stdlib.OpenDBFromPool(pool),
+		os.DirFS(migrationsDir),
+		goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration provider: %w", err)
+	}
+
 	return &Migration{
-		logger: logger,
-		pool:   pool,
+		logger:   logger,
+		pool:     pool,
+		provider: provider,
+		seedsDir: seedsDir,
+	}, nil
+}
+
+// Init migrates the schema, applies seeds and creates the admin user
+func (m *Migration) Init(ctx context.Context, email string, password string) error {
+	if err := m.Up(ctx); err != nil {
+		return err
+	}
+
+	if err := m.Seed(ctx); err != nil {
+		return err
 	}
+
+	return m.CreateAdminUser(ctx, email, password)
 }
 
-func (m *Migration) Init(ctx context.Context, email string, password string) {
-	m.Up()
-	m.CreateAdminUser(ctx, email, password)
+// CheckVersion fails until the database is migrated to the latest migration shipped with the app,
+// it does not take the migration lock
+func (m *Migration) CheckVersion(ctx context.Context) error {
+	current, target, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database version: %w", err)
+	}
+
+	if current != target {
+		return fmt.Errorf("database version %d, expected %d", current, target)
+	}
+
+	return nil
 }
 
-// TODO: проверять на ластовую миграцию/заспидранил
-func (m *Migration) Up() {
-	db := stdlib.OpenDBFromPool(m.pool)
+func (m *Migration) Up(ctx context.Context) error {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
 
-	if err := goose.SetDialect("postgres"); err != nil {
-		m.logger.Error("error to set db dialect",
-			"error", err,
-		)
-		os.Exit(1)
+	for _, result := range results {
+		m.logResult(result)
 	}
 
-	if err := goose.Up(db, "migrations"); err != nil {
-		m.logger.Error("error applying migrations",
-			"error", err,
-		)
-		os.Exit(1)
+	return nil
+}
+
+// Down rolls back the latest applied migration
+func (m *Migration) Down(ctx context.Context) error {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to roll back migration: %w", err)
 	}
+
+	m.logResult(result)
+
+	return nil
 }
 
-func (m *Migration) CreateAdminUser(ctx context.Context, email string, password string) {
-	conn, err := m.pool.Acquire(ctx)
+// Redo rolls back the latest applied migration and applies it again
+func (m *Migration) Redo(ctx context.Context) error {
+	if err := m.Down(ctx); err != nil {
+		return err
+	}
+
+	result, err := m.provider.UpByOne(ctx)
 	if err != nil {
-		m.logger.Error("error acquiring connection from pool",
-			"error", err,
-		)
-		os.Exit(1)
+		return fmt.Errorf("failed to reapply migration: %w", err)
 	}
-	defer conn.Release()
 
-	password_hash, err := hash.NewBcryptHash(password)
+	m.logResult(result)
+
+	return nil
+}
+
+func (m *Migration) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
 	if err != nil {
-		m.logger.Error("error hashing password",
-			"error", err,
-		)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to get migration status: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// Seed runs every file from the seeds directory in name order within one transaction,
+// seeds hold reference data and must be idempotent
+func (m *Migration) Seed(ctx context.Context) error {
+	files, err := fs.Glob(os.DirFS(m.seedsDir), "*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list seeds: %w", err)
+	}
+
+	sort.Strings(files)
+
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin seed transaction: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	for _, file := range files {
+		content, err := os.ReadFile(path.Join(m.seedsDir, file))
+		if err != nil {
+			return fmt.Errorf("failed to read seed %s: %w", file, err)
+		}
+
+		if _, err = tx.Exec(ctx, string(content)); err != nil {
+			return fmt.Errorf("failed to apply seed %s: %w", file, err)
+		}
+
+		m.logger.Info("Seed applied", "file", file)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit seeds: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migration) Close() error {
+	return m.provider.Close()
+}
+
+func (m *Migration) CreateAdminUser(ctx context.Context, email string, password string) error {
+	passwordHash, err := hash.NewBcryptHash(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash admin password: %w", err)
 	}
 
 	var existingEmail string
 
 	sql := `SELECT email FROM public.users WHERE email = $1`
 
-	row := conn.QueryRow(ctx, sql, email)
+	row := m.pool.QueryRow(ctx, sql, email)
 
-	if err = row.Scan(&existingEmail); err != nil {
-		if !errors.Is(err, pgx.ErrNoRows) {
-			m.logger.Error("error querying for existing email", "error", err)
-			os.Exit(1)
-		}
+	if err = row.Scan(&existingEmail); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to query admin user: %w", err)
 	}
+
 	if existingEmail != "" {
 		m.logger.Warn("admin account with this email already exists", "email", existingEmail)
-		return
+		return nil
 	}
 
 	sql = `INSERT INTO public.users (email, password_hash, role, created_at) VALUES ($1, $2, $3, $4)`
-	_, err = conn.Exec(ctx, sql, email, password_hash, user.Admin, time.Now())
-	if err != nil {
-		m.logger.Error("error inserting user",
-			"error", err,
-		)
-		os.Exit(1)
+
+	if _, err = m.pool.Exec(ctx, sql, email, passwordHash, user.Admin, time.Now()); err != nil {
+		return fmt.Errorf("failed to create admin user: %w", err)
 	}
 
 	m.logger.Info("admin user created successfully", "email", email)
 
+	return nil
+}
+
+// Create adds an empty SQL migration with a timestamp version to dir
+func Create(dir string, name string) error {
+	return goose.Create(nil, dir, name, "sql")
+}
+
+func (m *Migration) logResult(result *goose.MigrationResult) {
+	m.logger.Info("Migration applied",
+		"version", result.Source.Version,
+		"direction", result.Direction,
+		"duration", result.Duration,
+	)
 }
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"log/slog"
+)
+
+type AccessRepository struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewAccessRepository(pool *pgxpool.Pool, logger *slog.Logger) *AccessRepository {
+	return &AccessRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+func (a *AccessRepository) GetGrants(ctx context.Context, role string) ([]access.Grant, error) {
+	ctx = postgresql.WithQueryName(ctx, "AccessRepository.GetGrants")
+
+	sql := `
+		SELECT
+			role_name,
+			permission_name,
+			scope_type
+		FROM
+			public.role_permissions
+		WHERE
+			role_name = $1
+		`
+
+	rows, err := postgresql.Conn(ctx, a.pool).Query(ctx, sql, role)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to get grants",
+			"error", err,
+			"role", role,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grants []access.Grant
+
+	for rows.Next() {
+		var grant access.Grant
+		if err = rows.Scan(&grant.Role, &grant.Permission, &grant.ScopeType); err != nil {
+			a.logger.ErrorContext(ctx, "Failed to scan grant row",
+				"error", err,
+				"role", role,
+			)
+			return nil, err
+		}
+
+		grants = append(grants, grant)
+	}
+
+	return grants, nil
+}
+
+// GetScopes returns the assigned scopes of the user along with the groups the user leads,
+// the latter follow groups.leader_id so appointing or replacing a leader needs no extra writes
+func (a *AccessRepository) GetScopes(ctx context.Context, userID uint64) ([]access.Scope, error) {
+	ctx = postgresql.WithQueryName(ctx, "AccessRepository.GetScopes")
+
+	sql := `
+		SELECT scope_type, scope_id FROM public.user_scopes WHERE user_id = $1
+		UNION
+		SELECT 'group', group_id FROM public.groups WHERE leader_id = $1
+		`
+
+	rows, err := postgresql.Conn(ctx, a.pool).Query(ctx, sql, userID)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to get user scopes",
+			"error", err,
+			"userID", userID,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scopes []access.Scope
+
+	for rows.Next() {
+		var scope access.Scope
+		if err = rows.Scan(&scope.Type, &scope.ID); err != nil {
+			a.logger.ErrorContext(ctx, "Failed to scan user scope row",
+				"error", err,
+				"userID", userID,
+			)
+			return nil, err
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
+
+func (a *AccessRepository) CreateScope(ctx context.Context, userID uint64, scope access.Scope) error {
+	ctx = postgresql.WithQueryName(ctx, "AccessRepository.CreateScope")
+
+	sql := `
+		INSERT INTO public.user_scopes
+		    (user_id, scope_type, scope_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		`
+
+	_, err := postgresql.Conn(ctx, a.pool).Exec(ctx, sql, userID, scope.Type, scope.ID)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to create user scope",
+			"error", err,
+			"userID", userID,
+			"scopeType", scope.Type,
+			"scopeID", scope.ID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (a *AccessRepository) DeleteScope(ctx context.Context, userID uint64, scope access.Scope) error {
+	ctx = postgresql.WithQueryName(ctx, "AccessRepository.DeleteScope")
+
+	sql := `DELETE FROM public.user_scopes WHERE user_id = $1 AND scope_type = $2 AND scope_id = $3`
+
+	_, err := postgresql.Conn(ctx, a.pool).Exec(ctx, sql, userID, scope.Type, scope.ID)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to delete user scope",
+			"error", err,
+			"userID", userID,
+			"scopeType", scope.Type,
+			"scopeID", scope.ID,
+		)
+		return err
+	}
+
+	return nil
+}
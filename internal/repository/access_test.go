@@ -0,0 +1,118 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"slices"
+	"testing"
+)
+
+func TestAccessRepository_GetGrants(t *testing.T) {
+	ctx, repos, _ := setup(t)
+
+	tests := []struct {
+		role       string
+		permission string
+		scopeType  *string
+		granted    bool
+	}{
+		{role: user.Admin, permission: access.GroupCreate, granted: true},
+		{role: user.FacultyAdmin, permission: access.GroupCreate, scopeType: ptr(access.ScopeFaculty), granted: true},
+		{role: user.Leader, permission: access.MembersManage, scopeType: ptr(access.ScopeGroup), granted: true},
+		{role: user.Student, permission: access.GroupJoin, granted: true},
+		{role: user.Student, permission: access.GroupCreate, granted: false},
+		{role: "unknown", permission: access.GroupJoin, granted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+" "+tt.permission, func(t *testing.T) {
+			grants, err := repos.Access.GetGrants(ctx, tt.role)
+			mustNoErr(t, err)
+
+			index := slices.IndexFunc(grants, func(grant access.Grant) bool { return grant.Permission == tt.permission })
+			if (index >= 0) != tt.granted {
+				t.Fatalf("got granted %v, want %v", index >= 0, tt.granted)
+			}
+
+			if !tt.granted {
+				return
+			}
+
+			got := grants[index].ScopeType
+			if (got == nil) != (tt.scopeType == nil) || (got != nil && *got != *tt.scopeType) {
+				t.Fatalf("got scope type %v, want %v", got, tt.scopeType)
+			}
+		})
+	}
+}
+
+func TestAccessRepository_Scopes(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	iit := faculty(t, repos, "ИИТ")
+
+	admin, err := fixtures.Admin(ctx, func(u *user.User) { u.Role = user.FacultyAdmin })
+	mustNoErr(t, err)
+
+	leader, err := fixtures.Student(ctx, func(u *user.User) { u.Role = user.Leader })
+	mustNoErr(t, err)
+
+	led, err := fixtures.Group(ctx, func(g *group.Group) { g.LeaderID = &leader.UserID })
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name   string
+		userID uint64
+		run    func() error
+		want   []access.Scope
+	}{
+		{
+			name:   "assign a faculty",
+			userID: admin.UserID,
+			run:    func() error { return repos.Access.CreateScope(ctx, admin.UserID, access.Faculty(iit.FacultyID)) },
+			want:   []access.Scope{access.Faculty(iit.FacultyID)},
+		},
+		{
+			name:   "assign the same faculty again",
+			userID: admin.UserID,
+			run:    func() error { return repos.Access.CreateScope(ctx, admin.UserID, access.Faculty(iit.FacultyID)) },
+			want:   []access.Scope{access.Faculty(iit.FacultyID)},
+		},
+		{
+			name:   "revoke the faculty",
+			userID: admin.UserID,
+			run:    func() error { return repos.Access.DeleteScope(ctx, admin.UserID, access.Faculty(iit.FacultyID)) },
+			want:   nil,
+		},
+		{
+			name:   "leader gets the led group",
+			userID: leader.UserID,
+			run:    func() error { return nil },
+			want:   []access.Scope{access.Group(led.GroupID)},
+		},
+		{
+			name:   "replaced leader loses the group",
+			userID: leader.UserID,
+			run: func() error {
+				led.LeaderID = nil
+				return repos.Group.Update(ctx, led)
+			},
+			want: nil,
+		},
+	}
+
+	// the steps build on each other, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		mustNoErr(t, tt.run())
+
+		got, err := repos.Access.GetScopes(ctx, tt.userID)
+		mustNoErr(t, err)
+
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("%s: got scopes %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
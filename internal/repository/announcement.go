@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"log/slog"
+	"time"
+)
+
+type AnnouncementRepository struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewAnnouncementRepository(pool *pgxpool.Pool, logger *slog.Logger) *AnnouncementRepository {
+	return &AnnouncementRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+func (a *AnnouncementRepository) Create(ctx context.Context, entity announcement.Announcement) (uint64, error) {
+	ctx = postgresql.WithQueryName(ctx, "AnnouncementRepository.Create")
+
+	sql := `
+		INSERT INTO public.announcements
+		(group_id, author_id, title, body, attachment_url, pinned, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING announcement_id
+		`
+
+	row := postgresql.Conn(ctx, a.pool).QueryRow(
+		ctx,
+		sql,
+		entity.GroupID,
+		entity.AuthorID,
+		entity.Title,
+		entity.Body,
+		entity.AttachmentURL,
+		entity.Pinned,
+		entity.ExpiresAt,
+		entity.CreatedAt,
+		entity.UpdatedAt)
+
+	var announcementID uint64
+
+	if err := row.Scan(&announcementID); err != nil {
+		a.logger.ErrorContext(ctx, "Failed to create announcement",
+			"error", err,
+			"group_id", entity.GroupID,
+		)
+		return 0, err
+	}
+
+	return announcementID, nil
+}
+
+func (a *AnnouncementRepository) Update(ctx context.Context, entity announcement.Announcement) error {
+	ctx = postgresql.WithQueryName(ctx, "AnnouncementRepository.Update")
+
+	sql := `
+		UPDATE public.announcements
+		SET
+			title = $1,
+			body = $2,
+			attachment_url = $3,
+			pinned = $4,
+			expires_at = $5,
+			updated_at = $6
+		WHERE announcement_id = $7
+		`
+
+	_, err := postgresql.Conn(ctx, a.pool).Exec(
+		ctx,
+		sql,
+		entity.Title,
+		entity.Body,
+		entity.AttachmentURL,
+		entity.Pinned,
+		entity.ExpiresAt,
+		entity.UpdatedAt,
+		entity.AnnouncementID)
+
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to update announcement",
+			"error", err,
+			"announcement_id", entity.AnnouncementID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (a *AnnouncementRepository) Delete(ctx context.Context, announcementID uint64) error {
+	ctx = postgresql.WithQueryName(ctx, "AnnouncementRepository.Delete")
+
+	sql := `DELETE FROM public.announcements WHERE announcement_id = $1`
+
+	_, err := postgresql.Conn(ctx, a.pool).Exec(ctx, sql, announcementID)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to delete announcement",
+			"error", err,
+			"announcement_id", announcementID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (a *AnnouncementRepository) GetById(ctx context.Context, announcementID uint64) (announcement.Announcement, error) {
+	ctx = postgresql.WithQueryName(ctx, "AnnouncementRepository.GetById")
+
+	sql := `
+		SELECT
+			announcement_id,
+			group_id,
+			author_id,
+			title,
+			body,
+			attachment_url,
+			pinned,
+			expires_at,
+			created_at,
+			updated_at
+		FROM
+			public.announcements
+		WHERE
+			announcement_id = $1
+		`
+
+	row := postgresql.Conn(ctx, a.pool).QueryRow(ctx, sql, announcementID)
+
+	var entity announcement.Announcement
+
+	err := row.Scan(
+		&entity.AnnouncementID,
+		&entity.GroupID,
+		&entity.AuthorID,
+		&entity.Title,
+		&entity.Body,
+		&entity.AttachmentURL,
+		&entity.Pinned,
+		&entity.ExpiresAt,
+		&entity.CreatedAt,
+		&entity.UpdatedAt)
+
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			a.logger.ErrorContext(ctx, "Failed to get announcement by id",
+				"error", err,
+				"announcement_id", announcementID,
+			)
+		}
+		return entity, err
+	}
+
+	return entity, nil
+}
+
+// GetAllByGroupId returns the board of the group with read receipts counted and the read flag of the user,
+// expired announcements are skipped unless the filter includes them
+func (a *AnnouncementRepository) GetAllByGroupId(ctx context.Context, groupID uint64, userID uint64, filter announcement.FilterDTO) ([]announcement.DetailsAnnouncementDTO, int, error) {
+	ctx = postgresql.WithQueryName(ctx, "AnnouncementRepository.GetAllByGroupId")
+
+	countSql := `
+		SELECT
+			COUNT(*)
+		FROM
+			public.announcements AS a
+		WHERE
+			a.group_id = $1 AND ($2 OR a.expires_at IS NULL OR a.expires_at > $3)
+		`
+
+	var total int
+
+	err := postgresql.Conn(ctx, a.pool).
+		QueryRow(ctx, countSql, groupID, filter.IncludeExpired, filter.Now).
+		Scan(&total)
+
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to count announcements",
+			"error", err,
+			"group_id", groupID,
+		)
+		return nil, 0, err
+	}
+
+	sql := `
+		SELECT
+			a.announcement_id,
+			a.group_id,
+			a.author_id,
+			a.title,
+			a.body,
+			a.attachment_url,
+			a.pinned,
+			a.expires_at,
+			a.created_at,
+			a.updated_at,
+			(SELECT COUNT(*) FROM public.announcement_reads AS r WHERE r.announcement_id = a.announcement_id),
+			EXISTS (
+				SELECT 1 FROM public.announcement_reads AS r
+				WHERE r.announcement_id = a.announcement_id AND r.user_id = $4
+			)
+		FROM
+			public.announcements AS a
+		WHERE
+			a.group_id = $1 AND ($2 OR a.expires_at IS NULL OR a.expires_at > $3)
+		ORDER BY
+			a.pinned DESC,
+			a.created_at DESC,
+			a.announcement_id DESC
+		LIMIT $5 OFFSET $6
+		`
+
+	rows, err := postgresql.Conn(ctx, a.pool).Query(
+		ctx,
+		sql,
+		groupID,
+		filter.IncludeExpired,
+		filter.Now,
+		userID,
+		filter.Limit,
+		filter.Offset)
+
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to get announcements",
+			"error", err,
+			"group_id", groupID,
+		)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var announcements []announcement.DetailsAnnouncementDTO
+
+	for rows.Next() {
+		var entity announcement.DetailsAnnouncementDTO
+		err = rows.Scan(
+			&entity.AnnouncementID,
+			&entity.GroupID,
+			&entity.AuthorID,
+			&entity.Title,
+			&entity.Body,
+			&entity.AttachmentURL,
+			&entity.Pinned,
+			&entity.ExpiresAt,
+			&entity.CreatedAt,
+			&entity.UpdatedAt,
+			&entity.ReadCount,
+			&entity.Read)
+
+		if err != nil {
+			a.logger.ErrorContext(ctx, "Failed to scan row in GetAllByGroupId",
+				"error", err,
+			)
+			return nil, 0, err
+		}
+
+		announcements = append(announcements, entity)
+	}
+
+	return announcements, total, nil
+}
+
+func (a *AnnouncementRepository) MarkRead(ctx context.Context, announcementID uint64, userID uint64, readAt time.Time) error {
+	ctx = postgresql.WithQueryName(ctx, "AnnouncementRepository.MarkRead")
+
+	sql := `
+		INSERT INTO public.announcement_reads
+			(announcement_id, user_id, read_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		`
+
+	_, err := postgresql.Conn(ctx, a.pool).Exec(ctx, sql, announcementID, userID, readAt)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to mark announcement as read",
+			"error", err,
+			"announcement_id", announcementID,
+			"user_id", userID,
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAnnouncementRepository_GetAllByGroupId(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	reader, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	post := func(title string, pinned bool, age time.Duration, expiresAt *time.Time) uint64 {
+		t.Helper()
+
+		announcementID, err := repos.Announcement.Create(ctx, announcement.Announcement{
+			GroupID:   created.GroupID,
+			Title:     title,
+			Body:      title,
+			Pinned:    pinned,
+			ExpiresAt: expiresAt,
+			CreatedAt: now.Add(-age),
+			UpdatedAt: now.Add(-age),
+		})
+		mustNoErr(t, err)
+
+		return announcementID
+	}
+
+	post("old", false, 3*time.Hour, nil)
+	post("pinned", true, 4*time.Hour, nil)
+	post("expired", false, time.Hour, ptr(now.Add(-time.Minute)))
+	read := post("new", false, 2*time.Hour, ptr(now.Add(time.Hour)))
+
+	mustNoErr(t, repos.Announcement.MarkRead(ctx, read, reader.UserID, now))
+	mustNoErr(t, repos.Announcement.MarkRead(ctx, read, reader.UserID, now))
+
+	tests := []struct {
+		name      string
+		filter    announcement.FilterDTO
+		want      []string
+		wantTotal int
+	}{
+		{
+			name:      "pinned first then newest",
+			filter:    announcement.FilterDTO{Now: now, Limit: 10},
+			want:      []string{"pinned", "new", "old"},
+			wantTotal: 3,
+		},
+		{
+			name:      "now in another zone",
+			filter:    announcement.FilterDTO{Now: now.In(time.FixedZone("UTC-5", -5*60*60)), Limit: 10},
+			want:      []string{"pinned", "new", "old"},
+			wantTotal: 3,
+		},
+		{
+			name:      "with expired",
+			filter:    announcement.FilterDTO{IncludeExpired: true, Now: now, Limit: 10},
+			want:      []string{"pinned", "expired", "new", "old"},
+			wantTotal: 4,
+		},
+		{
+			name:      "page",
+			filter:    announcement.FilterDTO{Now: now, Limit: 1, Offset: 1},
+			want:      []string{"new"},
+			wantTotal: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, total, err := repos.Announcement.GetAllByGroupId(ctx, created.GroupID, reader.UserID, tt.filter)
+			mustNoErr(t, err)
+
+			var got []string
+			for _, item := range items {
+				got = append(got, item.Title)
+
+				wantRead := item.AnnouncementID == read
+				if item.Read != wantRead {
+					t.Fatalf("%s: got read %v, want %v", item.Title, item.Read, wantRead)
+				}
+
+				if wantRead && item.ReadCount != 1 {
+					t.Fatalf("%s: got %d reads, want 1", item.Title, item.ReadCount)
+				}
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got announcements %v, want %v", got, tt.want)
+			}
+
+			if total != tt.wantTotal {
+				t.Fatalf("got total %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestAnnouncementRepository_Changes(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	author, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	entity := announcement.Announcement{
+		GroupID:   created.GroupID,
+		AuthorID:  &author.UserID,
+		Title:     "Перенос пары",
+		Body:      "Пара переносится на среду",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	entity.AnnouncementID, err = repos.Announcement.Create(ctx, entity)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name    string
+		run     func() error
+		check   func(announcement.Announcement) bool
+		wantErr error
+	}{
+		{
+			name: "create",
+			run:  func() error { return nil },
+			check: func(found announcement.Announcement) bool {
+				return found.Title == entity.Title && *found.AuthorID == author.UserID
+			},
+		},
+		{
+			name: "update",
+			run: func() error {
+				entity.Title = "Пара отменена"
+				entity.Pinned = true
+				entity.AttachmentURL = ptr("https://classflow.test/notice.pdf")
+				return repos.Announcement.Update(ctx, entity)
+			},
+			check: func(found announcement.Announcement) bool {
+				return found.Title == "Пара отменена" && found.Pinned && found.AttachmentURL != nil
+			},
+		},
+		{
+			name:    "delete",
+			run:     func() error { return repos.Announcement.Delete(ctx, entity.AnnouncementID) },
+			wantErr: pgx.ErrNoRows,
+		},
+	}
+
+	// the steps share the announcement, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		mustNoErr(t, tt.run())
+
+		found, err := repos.Announcement.GetById(ctx, entity.AnnouncementID)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: got error %v, want %v", tt.name, err, tt.wantErr)
+		}
+
+		if err == nil && !tt.check(found) {
+			t.Fatalf("%s: unexpected announcement %+v", tt.name, found)
+		}
+	}
+}
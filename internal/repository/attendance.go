@@ -0,0 +1,460 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/sqlbuilder"
+	"log/slog"
+	"time"
+)
+
+type AttendanceRepository struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewAttendanceRepository(pool *pgxpool.Pool, logger *slog.Logger) *AttendanceRepository {
+	return &AttendanceRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+func (a *AttendanceRepository) Upsert(ctx context.Context, records []attendance.Record) error {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.Upsert")
+
+	sql := `
+		INSERT INTO public.attendance
+		(group_id, schedule_id, user_id, lesson_date, status, source, marked_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (schedule_id, lesson_date, user_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			source = EXCLUDED.source,
+			marked_by = EXCLUDED.marked_by,
+			updated_at = EXCLUDED.updated_at
+		`
+
+	for _, record := range records {
+		_, err := postgresql.Conn(ctx, a.pool).Exec(
+			ctx,
+			sql,
+			record.GroupID,
+			record.ScheduleID,
+			record.UserID,
+			record.LessonDate,
+			record.Status,
+			record.Source,
+			record.MarkedBy,
+			record.CreatedAt,
+			record.UpdatedAt)
+
+		if err != nil {
+			a.logger.ErrorContext(ctx, "Failed to upsert attendance",
+				"error", err,
+				"schedule_id", record.ScheduleID,
+				"user_id", record.UserID,
+			)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetSheet returns every member of the group with the status at the lesson occurrence
+func (a *AttendanceRepository) GetSheet(ctx context.Context, groupID, scheduleID uint64, lessonDate time.Time) ([]attendance.SheetEntryDTO, error) {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.GetSheet")
+
+	sql := `
+		SELECT
+			m.user_id,
+			u.fullname,
+			u.email,
+			at.status
+		FROM
+			public.members AS m
+		INNER JOIN
+			public.users AS u ON m.user_id = u.user_id
+		LEFT JOIN
+			public.attendance AS at ON at.user_id = m.user_id AND at.schedule_id = $2 AND at.lesson_date = $3
+		WHERE
+			m.group_id = $1
+		ORDER BY
+			u.fullname,
+			m.user_id
+		`
+
+	rows, err := postgresql.Conn(ctx, a.pool).Query(ctx, sql, groupID, scheduleID, lessonDate)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to get attendance sheet",
+			"error", err,
+			"group_id", groupID,
+			"schedule_id", scheduleID,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sheet []attendance.SheetEntryDTO
+
+	for rows.Next() {
+		var entry attendance.SheetEntryDTO
+		if err = rows.Scan(&entry.UserID, &entry.FullName, &entry.Email, &entry.Status); err != nil {
+			a.logger.ErrorContext(ctx, "Failed to scan row in GetSheet",
+				"error", err,
+			)
+			return nil, err
+		}
+
+		sheet = append(sheet, entry)
+	}
+
+	return sheet, nil
+}
+
+func (a *AttendanceRepository) GetAllByUserId(ctx context.Context, userID uint64, filter attendance.FilterDTO) ([]attendance.DetailsRecordDTO, error) {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.GetAllByUserId")
+
+	sql, args := sqlbuilder.Select(`
+		SELECT
+			at.attendance_id,
+			at.group_id,
+			at.schedule_id,
+			at.user_id,
+			at.lesson_date,
+			at.status,
+			at.source,
+			at.marked_by,
+			at.created_at,
+			at.updated_at,
+			s.subject_name,
+			s.start_time
+		FROM
+			public.attendance AS at
+		INNER JOIN
+			public.schedule AS s ON at.schedule_id = s.schedule_id
+	`).
+		Where("at.user_id = ?", userID).
+		WhereIf(filter.From != nil, "at.lesson_date >= ?", filter.From).
+		WhereIf(filter.To != nil, "at.lesson_date <= ?", filter.To).
+		WhereIf(filter.SubjectName != "", "s.subject_name = ?", filter.SubjectName).
+		OrderBy("at.lesson_date").
+		OrderBy("s.start_time").
+		Build()
+
+	rows, err := postgresql.Conn(ctx, a.pool).Query(ctx, sql, args...)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to get attendance",
+			"error", err,
+			"args", args,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []attendance.DetailsRecordDTO
+
+	for rows.Next() {
+		var record attendance.DetailsRecordDTO
+		err = rows.Scan(
+			&record.RecordID,
+			&record.GroupID,
+			&record.ScheduleID,
+			&record.UserID,
+			&record.LessonDate,
+			&record.Status,
+			&record.Source,
+			&record.MarkedBy,
+			&record.CreatedAt,
+			&record.UpdatedAt,
+			&record.SubjectName,
+			&record.StartTime)
+
+		if err != nil {
+			a.logger.ErrorContext(ctx, "Failed to scan row in GetAllByUserId",
+				"error", err,
+			)
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// GetSummary counts the records of the group per member and subject
+func (a *AttendanceRepository) GetSummary(ctx context.Context, groupID uint64, filter attendance.FilterDTO) ([]attendance.SummaryDTO, error) {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.GetSummary")
+
+	sql := `
+		SELECT
+			at.user_id,
+			u.fullname,
+			u.email,
+			s.subject_name,
+			COUNT(*) FILTER (WHERE at.status = 'present'),
+			COUNT(*) FILTER (WHERE at.status = 'absent'),
+			COUNT(*) FILTER (WHERE at.status = 'excused')
+		FROM
+			public.attendance AS at
+		INNER JOIN
+			public.schedule AS s ON at.schedule_id = s.schedule_id
+		INNER JOIN
+			public.users AS u ON at.user_id = u.user_id
+		WHERE
+			at.group_id = $1
+			AND ($2::date IS NULL OR at.lesson_date >= $2)
+			AND ($3::date IS NULL OR at.lesson_date <= $3)
+			AND ($4 = '' OR s.subject_name = $4)
+		GROUP BY
+			at.user_id,
+			u.fullname,
+			u.email,
+			s.subject_name
+		ORDER BY
+			u.fullname,
+			at.user_id,
+			s.subject_name
+		`
+
+	rows, err := postgresql.Conn(ctx, a.pool).Query(ctx, sql, groupID, filter.From, filter.To, filter.SubjectName)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to get attendance summary",
+			"error", err,
+			"group_id", groupID,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summary []attendance.SummaryDTO
+
+	for rows.Next() {
+		var item attendance.SummaryDTO
+		err = rows.Scan(
+			&item.UserID,
+			&item.FullName,
+			&item.Email,
+			&item.SubjectName,
+			&item.Present,
+			&item.Absent,
+			&item.Excused)
+
+		if err != nil {
+			a.logger.ErrorContext(ctx, "Failed to scan row in GetSummary",
+				"error", err,
+			)
+			return nil, err
+		}
+
+		summary = append(summary, item)
+	}
+
+	return summary, nil
+}
+
+// CheckIn keeps the record already set for the lesson occurrence
+func (a *AttendanceRepository) CheckIn(ctx context.Context, record attendance.Record) error {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.CheckIn")
+
+	sql := `
+		INSERT INTO public.attendance
+		(group_id, schedule_id, user_id, lesson_date, status, source, marked_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (schedule_id, lesson_date, user_id) DO NOTHING
+		`
+
+	_, err := postgresql.Conn(ctx, a.pool).Exec(
+		ctx,
+		sql,
+		record.GroupID,
+		record.ScheduleID,
+		record.UserID,
+		record.LessonDate,
+		record.Status,
+		record.Source,
+		record.MarkedBy,
+		record.CreatedAt,
+		record.UpdatedAt)
+
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to check in",
+			"error", err,
+			"schedule_id", record.ScheduleID,
+			"user_id", record.UserID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+// OpenWindow replaces the window of the lesson occurrence when there is one
+func (a *AttendanceRepository) OpenWindow(ctx context.Context, window attendance.Window) (uint64, error) {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.OpenWindow")
+
+	sql := `
+		INSERT INTO public.checkin_windows
+		(group_id, schedule_id, lesson_date, secret, radius, opened_by, opens_at, closes_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (schedule_id, lesson_date) DO UPDATE SET
+			secret = EXCLUDED.secret,
+			radius = EXCLUDED.radius,
+			opened_by = EXCLUDED.opened_by,
+			opens_at = EXCLUDED.opens_at,
+			closes_at = EXCLUDED.closes_at
+		RETURNING window_id
+		`
+
+	row := postgresql.Conn(ctx, a.pool).QueryRow(
+		ctx,
+		sql,
+		window.GroupID,
+		window.ScheduleID,
+		window.LessonDate,
+		window.Secret,
+		window.Radius,
+		window.OpenedBy,
+		window.OpensAt,
+		window.ClosesAt,
+		window.CreatedAt)
+
+	var windowID uint64
+
+	if err := row.Scan(&windowID); err != nil {
+		a.logger.ErrorContext(ctx, "Failed to open check-in window",
+			"error", err,
+			"schedule_id", window.ScheduleID,
+		)
+		return 0, err
+	}
+
+	return windowID, nil
+}
+
+func (a *AttendanceRepository) CloseWindow(ctx context.Context, windowID uint64, closesAt time.Time) error {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.CloseWindow")
+
+	sql := `UPDATE public.checkin_windows SET closes_at = $1 WHERE window_id = $2`
+
+	_, err := postgresql.Conn(ctx, a.pool).Exec(ctx, sql, closesAt, windowID)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to close check-in window",
+			"error", err,
+			"window_id", windowID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (a *AttendanceRepository) GetWindowById(ctx context.Context, windowID uint64) (attendance.Window, error) {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.GetWindowById")
+
+	sql := `
+		SELECT
+			window_id,
+			group_id,
+			schedule_id,
+			lesson_date,
+			secret,
+			radius,
+			opened_by,
+			opens_at,
+			closes_at,
+			created_at
+		FROM
+			public.checkin_windows
+		WHERE
+			window_id = $1
+		`
+
+	row := postgresql.Conn(ctx, a.pool).QueryRow(ctx, sql, windowID)
+
+	window, err := scanWindow(row)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			a.logger.ErrorContext(ctx, "Failed to get check-in window by id",
+				"error", err,
+				"window_id", windowID,
+			)
+		}
+		return window, err
+	}
+
+	return window, nil
+}
+
+func (a *AttendanceRepository) GetOpenWindowsByGroupId(ctx context.Context, groupID uint64, at time.Time) ([]attendance.Window, error) {
+	ctx = postgresql.WithQueryName(ctx, "AttendanceRepository.GetOpenWindowsByGroupId")
+
+	sql := `
+		SELECT
+			window_id,
+			group_id,
+			schedule_id,
+			lesson_date,
+			secret,
+			radius,
+			opened_by,
+			opens_at,
+			closes_at,
+			created_at
+		FROM
+			public.checkin_windows
+		WHERE
+			group_id = $1 AND opens_at <= $2 AND closes_at > $2
+		ORDER BY
+			window_id
+		`
+
+	rows, err := postgresql.Conn(ctx, a.pool).Query(ctx, sql, groupID, at)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to get open check-in windows",
+			"error", err,
+			"group_id", groupID,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []attendance.Window
+
+	for rows.Next() {
+		window, err := scanWindow(rows)
+		if err != nil {
+			a.logger.ErrorContext(ctx, "Failed to scan row in GetOpenWindowsByGroupId",
+				"error", err,
+			)
+			return nil, err
+		}
+
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+func scanWindow(row pgx.Row) (attendance.Window, error) {
+	var window attendance.Window
+
+	err := row.Scan(
+		&window.WindowID,
+		&window.GroupID,
+		&window.ScheduleID,
+		&window.LessonDate,
+		&window.Secret,
+		&window.Radius,
+		&window.OpenedBy,
+		&window.OpensAt,
+		&window.ClosesAt,
+		&window.CreatedAt)
+
+	return window, err
+}
@@ -0,0 +1,287 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/internal/repository"
+	"github.com/tclutin/classflow-api/internal/testutil"
+	"slices"
+	"testing"
+	"time"
+)
+
+// lesson creates a group with a schedule and the named members, it returns the group, its odd week lesson and the members
+func lesson(t *testing.T, ctx context.Context, repos *repository.Repositories, fixtures *testutil.Fixtures, names ...string) (uint64, uint64, []uint64) {
+	t.Helper()
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	_, err = fixtures.Schedule(ctx, created.GroupID)
+	mustNoErr(t, err)
+
+	lessons, err := repos.Schedule.GetSchedulesByGroupId(ctx, schedule.FilterDTO{IsEven: "false"}, created.GroupID)
+	mustNoErr(t, err)
+
+	var members []uint64
+
+	for _, name := range names {
+		student, err := fixtures.Student(ctx, func(u *user.User) { u.FullName = ptr(name) })
+		mustNoErr(t, err)
+
+		mustNoErr(t, fixtures.Member(ctx, student.UserID, created.GroupID))
+
+		members = append(members, student.UserID)
+	}
+
+	return created.GroupID, lessons[0].ScheduleID, members
+}
+
+func record(groupID, scheduleID, userID uint64, lessonDate time.Time, status, source string) attendance.Record {
+	return attendance.Record{
+		GroupID:    groupID,
+		ScheduleID: scheduleID,
+		UserID:     userID,
+		LessonDate: lessonDate,
+		Status:     status,
+		Source:     source,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+func TestAttendanceRepository_Marks(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	groupID, scheduleID, members := lesson(t, ctx, repos, fixtures, "Борисов", "Алексеев", "Васильев")
+	boris, alex, vasya := members[0], members[1], members[2]
+
+	// 2026-10-19 is a Monday of an odd week, like the lesson
+	monday := date(2026, time.October, 19)
+
+	tests := []struct {
+		name string
+		run  func() error
+		want map[uint64]string
+	}{
+		{
+			name: "nobody is marked",
+			run:  func() error { return nil },
+			want: map[uint64]string{},
+		},
+		{
+			name: "mark two members",
+			run: func() error {
+				return repos.Attendance.Upsert(ctx, []attendance.Record{
+					record(groupID, scheduleID, boris, monday, attendance.Present, attendance.SourceManual),
+					record(groupID, scheduleID, alex, monday, attendance.Absent, attendance.SourceManual),
+				})
+			},
+			want: map[uint64]string{boris: attendance.Present, alex: attendance.Absent},
+		},
+		{
+			name: "check in keeps a manual mark",
+			run: func() error {
+				return repos.Attendance.CheckIn(ctx, record(groupID, scheduleID, alex, monday, attendance.Present, attendance.SourceCheckin))
+			},
+			want: map[uint64]string{boris: attendance.Present, alex: attendance.Absent},
+		},
+		{
+			name: "check in of an unmarked member",
+			run: func() error {
+				return repos.Attendance.CheckIn(ctx, record(groupID, scheduleID, vasya, monday, attendance.Present, attendance.SourceCheckin))
+			},
+			want: map[uint64]string{boris: attendance.Present, alex: attendance.Absent, vasya: attendance.Present},
+		},
+		{
+			name: "mark again overrides",
+			run: func() error {
+				return repos.Attendance.Upsert(ctx, []attendance.Record{
+					record(groupID, scheduleID, alex, monday, attendance.Excused, attendance.SourceManual),
+				})
+			},
+			want: map[uint64]string{boris: attendance.Present, alex: attendance.Excused, vasya: attendance.Present},
+		},
+	}
+
+	// the steps share the lesson, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		mustNoErr(t, tt.run())
+
+		sheet, err := repos.Attendance.GetSheet(ctx, groupID, scheduleID, monday)
+		mustNoErr(t, err)
+
+		var order []uint64
+		for _, entry := range sheet {
+			order = append(order, entry.UserID)
+
+			want, marked := tt.want[entry.UserID]
+			if marked != (entry.Status != nil) || (marked && *entry.Status != want) {
+				t.Fatalf("%s: got status %v of %d, want %q", tt.name, entry.Status, entry.UserID, want)
+			}
+		}
+
+		if want := []uint64{alex, boris, vasya}; !slices.Equal(order, want) {
+			t.Fatalf("%s: got members %v, want %v ordered by name", tt.name, order, want)
+		}
+	}
+}
+
+func TestAttendanceRepository_Reports(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	groupID, scheduleID, members := lesson(t, ctx, repos, fixtures, "Алексеев", "Борисов")
+	alex, boris := members[0], members[1]
+
+	// the odd weeks of the lesson two weeks apart
+	first, second := date(2026, time.October, 5), date(2026, time.October, 19)
+
+	mustNoErr(t, repos.Attendance.Upsert(ctx, []attendance.Record{
+		record(groupID, scheduleID, alex, first, attendance.Present, attendance.SourceManual),
+		record(groupID, scheduleID, alex, second, attendance.Absent, attendance.SourceManual),
+		record(groupID, scheduleID, boris, first, attendance.Excused, attendance.SourceManual),
+	}))
+
+	history := []struct {
+		name   string
+		filter attendance.FilterDTO
+		want   []string
+	}{
+		{name: "all lessons", filter: attendance.FilterDTO{}, want: []string{attendance.Present, attendance.Absent}},
+		{name: "from inclusive", filter: attendance.FilterDTO{From: ptr(second)}, want: []string{attendance.Absent}},
+		{name: "to inclusive", filter: attendance.FilterDTO{To: ptr(first)}, want: []string{attendance.Present}},
+		{name: "other subject", filter: attendance.FilterDTO{SubjectName: "Физика"}, want: nil},
+	}
+
+	for _, tt := range history {
+		t.Run("history "+tt.name, func(t *testing.T) {
+			records, err := repos.Attendance.GetAllByUserId(ctx, alex, tt.filter)
+			mustNoErr(t, err)
+
+			var got []string
+			for _, record := range records {
+				got = append(got, record.Status)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got statuses %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	summary := []struct {
+		name   string
+		filter attendance.FilterDTO
+		want   map[uint64][3]int
+	}{
+		{
+			name:   "all lessons",
+			filter: attendance.FilterDTO{},
+			want:   map[uint64][3]int{alex: {1, 1, 0}, boris: {0, 0, 1}},
+		},
+		{
+			name:   "last lesson",
+			filter: attendance.FilterDTO{From: ptr(second), To: ptr(second)},
+			want:   map[uint64][3]int{alex: {0, 1, 0}},
+		},
+	}
+
+	for _, tt := range summary {
+		t.Run("summary "+tt.name, func(t *testing.T) {
+			items, err := repos.Attendance.GetSummary(ctx, groupID, tt.filter)
+			mustNoErr(t, err)
+
+			if len(items) != len(tt.want) {
+				t.Fatalf("got %d members, want %d", len(items), len(tt.want))
+			}
+
+			for _, item := range items {
+				if got := [3]int{item.Present, item.Absent, item.Excused}; got != tt.want[item.UserID] {
+					t.Fatalf("got counts %v of %d, want %v", got, item.UserID, tt.want[item.UserID])
+				}
+			}
+		})
+	}
+}
+
+func TestAttendanceRepository_Windows(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	groupID, scheduleID, _ := lesson(t, ctx, repos, fixtures)
+
+	monday := date(2026, time.October, 19)
+	opensAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	window := attendance.Window{
+		GroupID:    groupID,
+		ScheduleID: scheduleID,
+		LessonDate: monday,
+		Secret:     []byte("first"),
+		OpensAt:    opensAt,
+		ClosesAt:   opensAt.Add(10 * time.Minute),
+		CreatedAt:  opensAt,
+	}
+
+	windowID, err := repos.Attendance.OpenWindow(ctx, window)
+	mustNoErr(t, err)
+
+	window.Secret = []byte("second")
+	window.Radius = ptr(100)
+	window.ClosesAt = opensAt.Add(20 * time.Minute)
+
+	reopenedID, err := repos.Attendance.OpenWindow(ctx, window)
+	mustNoErr(t, err)
+
+	if reopenedID != windowID {
+		t.Fatalf("reopening created window %d, want %d replaced", reopenedID, windowID)
+	}
+
+	found, err := repos.Attendance.GetWindowById(ctx, windowID)
+	mustNoErr(t, err)
+
+	if string(found.Secret) != "second" || found.Radius == nil || !found.ClosesAt.Equal(window.ClosesAt) {
+		t.Fatalf("window was not replaced: %+v", found)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		open bool
+	}{
+		{name: "before opening", at: opensAt.Add(-time.Second), open: false},
+		{name: "at opening", at: opensAt, open: true},
+		{name: "at opening in another zone", at: opensAt.In(time.FixedZone("MSK", 3*60*60)), open: true},
+		{name: "within the replaced duration", at: opensAt.Add(15 * time.Minute), open: true},
+		{name: "at closing", at: window.ClosesAt, open: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := repos.Attendance.GetOpenWindowsByGroupId(ctx, groupID, tt.at)
+			mustNoErr(t, err)
+
+			if (len(windows) == 1) != tt.open {
+				t.Fatalf("got %d open windows, want open %v", len(windows), tt.open)
+			}
+		})
+	}
+
+	mustNoErr(t, repos.Attendance.CloseWindow(ctx, windowID, opensAt.Add(5*time.Minute)))
+
+	windows, err := repos.Attendance.GetOpenWindowsByGroupId(ctx, groupID, opensAt.Add(6*time.Minute))
+	mustNoErr(t, err)
+
+	if len(windows) != 0 {
+		t.Fatalf("closed window is still open: %+v", windows)
+	}
+
+	if _, err = repos.Attendance.GetWindowById(ctx, windowID+100); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows, got %v", err)
+	}
+}
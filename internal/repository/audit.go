@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/sqlbuilder"
+	"log/slog"
+)
+
+type AuditRepository struct {
+	pool    *pgxpool.Pool
+	replica *pgxpool.Pool
+	logger  *slog.Logger
+}
+
+func NewAuditRepository(pool *pgxpool.Pool, replica *pgxpool.Pool, logger *slog.Logger) *AuditRepository {
+	return &AuditRepository{
+		pool:    pool,
+		replica: replica,
+		logger:  logger,
+	}
+}
+
+func (a *AuditRepository) Create(ctx context.Context, record audit.Record) error {
+	ctx = postgresql.WithQueryName(ctx, "AuditRepository.Create")
+
+	sql := `
+		INSERT INTO public.audit_log
+		(actor_id, action, target_type, target_id, request_id, ip, before, after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`
+
+	_, err := postgresql.Conn(ctx, a.pool).Exec(
+		ctx,
+		sql,
+		record.ActorID,
+		record.Action,
+		record.TargetType,
+		record.TargetID,
+		record.RequestID,
+		record.IP,
+		record.Before,
+		record.After,
+		record.CreatedAt)
+
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to create audit record",
+			"error", err,
+			"action", record.Action,
+			"target_type", record.TargetType,
+			"target_id", record.TargetID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (a *AuditRepository) GetAll(ctx context.Context, filter audit.FilterDTO) ([]audit.Record, error) {
+	ctx = postgresql.WithQueryName(ctx, "AuditRepository.GetAll")
+
+	sql, args := sqlbuilder.Select(`
+		SELECT
+			audit_id,
+			actor_id,
+			action,
+			target_type,
+			target_id,
+			request_id,
+			ip,
+			before,
+			after,
+			created_at
+		FROM
+			public.audit_log
+	`).
+		WhereIf(filter.ActorID != nil, "actor_id = ?", filter.ActorID).
+		WhereIf(filter.TargetType != "", "target_type = ?", filter.TargetType).
+		WhereIf(filter.TargetID != nil, "target_id = ?", filter.TargetID).
+		WhereIf(filter.From != nil, "created_at >= ?", filter.From).
+		WhereIf(filter.To != nil, "created_at < ?", filter.To).
+		OrderBy("created_at DESC").
+		OrderBy("audit_id DESC").
+		Limit(filter.Limit).
+		Build()
+
+	rows, err := postgresql.Conn(ctx, a.replica).Query(ctx, sql, args...)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to get audit records",
+			"error", err,
+			"args", args,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []audit.Record
+
+	for rows.Next() {
+		var record audit.Record
+		err = rows.Scan(
+			&record.RecordID,
+			&record.ActorID,
+			&record.Action,
+			&record.TargetType,
+			&record.TargetID,
+			&record.RequestID,
+			&record.IP,
+			&record.Before,
+			&record.After,
+			&record.CreatedAt)
+
+		if err != nil {
+			a.logger.ErrorContext(ctx, "Failed to scan audit record row",
+				"error", err,
+			)
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,121 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/repository"
+	"github.com/tclutin/classflow-api/internal/testutil"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAuditRepository_GetAll(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	admin, err := fixtures.Admin(ctx)
+	mustNoErr(t, err)
+
+	other, err := fixtures.Admin(ctx)
+	mustNoErr(t, err)
+
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	records := []audit.Record{
+		{ActorID: &admin.UserID, Action: audit.GroupCreate, TargetType: audit.TargetGroup, TargetID: 1, CreatedAt: start},
+		{ActorID: &admin.UserID, Action: audit.GroupUpdate, TargetType: audit.TargetGroup, TargetID: 1, CreatedAt: start.Add(time.Hour)},
+		{ActorID: &other.UserID, Action: audit.GroupCreate, TargetType: audit.TargetGroup, TargetID: 2, CreatedAt: start.Add(2 * time.Hour)},
+		{ActorID: &other.UserID, Action: audit.UserUpdate, TargetType: audit.TargetUser, TargetID: admin.UserID, CreatedAt: start.Add(3 * time.Hour)},
+	}
+
+	for _, record := range records {
+		record.After = json.RawMessage(`{"short_name": "PI-101"}`)
+		mustNoErr(t, repos.Audit.Create(ctx, record))
+	}
+
+	tests := []struct {
+		name   string
+		filter audit.FilterDTO
+		want   []string
+	}{
+		{
+			name:   "newest first",
+			filter: audit.FilterDTO{},
+			want:   []string{audit.UserUpdate, audit.GroupCreate, audit.GroupUpdate, audit.GroupCreate},
+		},
+		{
+			name:   "by actor",
+			filter: audit.FilterDTO{ActorID: &admin.UserID},
+			want:   []string{audit.GroupUpdate, audit.GroupCreate},
+		},
+		{
+			name:   "by target",
+			filter: audit.FilterDTO{TargetType: audit.TargetGroup, TargetID: ptr(uint64(1))},
+			want:   []string{audit.GroupUpdate, audit.GroupCreate},
+		},
+		{
+			name:   "to is exclusive",
+			filter: audit.FilterDTO{From: ptr(start.Add(time.Hour)), To: ptr(start.Add(3 * time.Hour))},
+			want:   []string{audit.GroupCreate, audit.GroupUpdate},
+		},
+		{
+			name:   "range in another zone",
+			filter: audit.FilterDTO{From: ptr(start.Add(time.Hour).In(time.FixedZone("MSK", 3*60*60))), To: ptr(start.Add(3 * time.Hour))},
+			want:   []string{audit.GroupCreate, audit.GroupUpdate},
+		},
+		{
+			name:   "limit",
+			filter: audit.FilterDTO{Limit: 1},
+			want:   []string{audit.UserUpdate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repos.Audit.GetAll(ctx, tt.filter)
+			mustNoErr(t, err)
+
+			var got []string
+			for _, record := range found {
+				got = append(got, record.Action)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got actions %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditRepository_AppendOnly(t *testing.T) {
+	ctx := context.Background()
+
+	pg := testutil.Database(t)
+	repos := repository.NewRepositories(pg.Pool, pg.Pool, testutil.Logger())
+
+	mustNoErr(t, repos.Audit.Create(ctx, audit.Record{
+		Action:     audit.GroupDelete,
+		TargetType: audit.TargetGroup,
+		TargetID:   1,
+		CreatedAt:  time.Now(),
+	}))
+
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{name: "update", sql: `UPDATE public.audit_log SET action = 'group.create'`},
+		{name: "delete", sql: `DELETE FROM public.audit_log`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := pg.Pool.Exec(ctx, tt.sql); err == nil {
+				t.Fatal("expected the trigger to reject the statement")
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package contract
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"testing"
+	"time"
+)
+
+func AnnouncementRepository(t *testing.T, deps ContentDeps) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	newPost := func(t *testing.T, groupID uint64, createdAt time.Time, modify ...func(*announcement.Announcement)) announcement.Announcement {
+		t.Helper()
+
+		entity := announcement.Announcement{
+			GroupID:   groupID,
+			Title:     unique("title"),
+			Body:      "body",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+
+		for _, fn := range modify {
+			fn(&entity)
+		}
+
+		announcementID, err := deps.Announcements.Create(ctx, entity)
+		mustNoErr(t, err)
+
+		entity.AnnouncementID = announcementID
+
+		return entity
+	}
+
+	filter := announcement.FilterDTO{Now: now, Limit: 10}
+
+	t.Run("create, update and delete", func(t *testing.T) {
+		groupID := deps.group(t)
+		authorID := deps.member(t, groupID, "Author")
+
+		created := newPost(t, groupID, now, func(a *announcement.Announcement) { a.AuthorID = &authorID })
+
+		byId, err := deps.Announcements.GetById(ctx, created.AnnouncementID)
+		mustNoErr(t, err)
+		mustEqual(t, "title", byId.Title, created.Title)
+		mustEqual(t, "group", byId.GroupID, groupID)
+
+		if byId.AuthorID == nil || *byId.AuthorID != authorID {
+			t.Fatalf("author: got %v, want %d", byId.AuthorID, authorID)
+		}
+
+		byId.Title = "updated"
+		byId.Pinned = true
+		mustNoErr(t, deps.Announcements.Update(ctx, byId))
+
+		updated, err := deps.Announcements.GetById(ctx, created.AnnouncementID)
+		mustNoErr(t, err)
+		mustEqual(t, "title", updated.Title, "updated")
+		mustEqual(t, "pinned", updated.Pinned, true)
+
+		mustNoErr(t, deps.Announcements.Delete(ctx, created.AnnouncementID))
+
+		_, err = deps.Announcements.GetById(ctx, created.AnnouncementID)
+		mustNoRows(t, err)
+	})
+
+	t.Run("board is pinned first then newest first", func(t *testing.T) {
+		groupID := deps.group(t)
+
+		old := newPost(t, groupID, now.Add(-2*time.Hour))
+		pinned := newPost(t, groupID, now.Add(-3*time.Hour), func(a *announcement.Announcement) { a.Pinned = true })
+		recent := newPost(t, groupID, now.Add(-time.Hour))
+		newPost(t, deps.group(t), now)
+
+		posts, total, err := deps.Announcements.GetAllByGroupId(ctx, groupID, 0, filter)
+		mustNoErr(t, err)
+		mustEqual(t, "total", total, 3)
+		mustEqual(t, "posts", len(posts), 3)
+
+		for i, want := range []announcement.Announcement{pinned, recent, old} {
+			mustEqual(t, "post", posts[i].AnnouncementID, want.AnnouncementID)
+		}
+
+		page := filter
+		page.Limit, page.Offset = 1, 1
+
+		posts, total, err = deps.Announcements.GetAllByGroupId(ctx, groupID, 0, page)
+		mustNoErr(t, err)
+		mustEqual(t, "total", total, 3)
+		mustEqual(t, "page size", len(posts), 1)
+		mustEqual(t, "post on page", posts[0].AnnouncementID, recent.AnnouncementID)
+	})
+
+	t.Run("expired posts are hidden unless asked for", func(t *testing.T) {
+		groupID := deps.group(t)
+
+		expiresAt := now.Add(-time.Minute)
+		newPost(t, groupID, now.Add(-time.Hour), func(a *announcement.Announcement) { a.ExpiresAt = &expiresAt })
+		newPost(t, groupID, now.Add(-time.Hour))
+
+		_, total, err := deps.Announcements.GetAllByGroupId(ctx, groupID, 0, filter)
+		mustNoErr(t, err)
+		mustEqual(t, "active", total, 1)
+
+		withExpired := filter
+		withExpired.IncludeExpired = true
+
+		_, total, err = deps.Announcements.GetAllByGroupId(ctx, groupID, 0, withExpired)
+		mustNoErr(t, err)
+		mustEqual(t, "all", total, 2)
+	})
+
+	t.Run("read receipts are counted once per user", func(t *testing.T) {
+		groupID := deps.group(t)
+		reader := deps.member(t, groupID, "Reader")
+		other := deps.member(t, groupID, "Other")
+
+		post := newPost(t, groupID, now)
+
+		mustNoErr(t, deps.Announcements.MarkRead(ctx, post.AnnouncementID, reader, now))
+		mustNoErr(t, deps.Announcements.MarkRead(ctx, post.AnnouncementID, reader, now.Add(time.Minute)))
+		mustNoErr(t, deps.Announcements.MarkRead(ctx, post.AnnouncementID, other, now))
+
+		posts, _, err := deps.Announcements.GetAllByGroupId(ctx, groupID, reader, filter)
+		mustNoErr(t, err)
+		mustEqual(t, "read count", posts[0].ReadCount, 2)
+		mustEqual(t, "read by reader", posts[0].Read, true)
+
+		posts, _, err = deps.Announcements.GetAllByGroupId(ctx, groupID, deps.member(t, groupID, "Unread"), filter)
+		mustNoErr(t, err)
+		mustEqual(t, "read by another member", posts[0].Read, false)
+	})
+
+	t.Run("missing announcement", func(t *testing.T) {
+		_, err := deps.Announcements.GetById(ctx, 1<<62)
+		mustNoRows(t, err)
+	})
+}
@@ -0,0 +1,193 @@
+package contract
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"testing"
+	"time"
+)
+
+func AttendanceRepository(t *testing.T, deps ContentDeps) {
+	ctx := context.Background()
+	monday := day(2025, time.March, 3)
+
+	newRecord := func(groupID, scheduleID, userID uint64, lessonDate time.Time, status string) attendance.Record {
+		return attendance.Record{
+			GroupID:    groupID,
+			ScheduleID: scheduleID,
+			UserID:     userID,
+			LessonDate: lessonDate,
+			Status:     status,
+			Source:     attendance.SourceManual,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+	}
+
+	status := func(t *testing.T, entry attendance.SheetEntryDTO) string {
+		t.Helper()
+
+		if entry.Status == nil {
+			return ""
+		}
+
+		return *entry.Status
+	}
+
+	t.Run("sheet lists every member with the status", func(t *testing.T) {
+		groupID := deps.group(t)
+		lessons := deps.lessons(t, groupID, "Algebra")
+		bob := deps.member(t, groupID, "Bob")
+		alice := deps.member(t, groupID, "Alice")
+
+		mustNoErr(t, deps.Attendance.Upsert(ctx, []attendance.Record{
+			newRecord(groupID, lessons[0].ScheduleID, bob, monday, attendance.Absent),
+		}))
+
+		sheet, err := deps.Attendance.GetSheet(ctx, groupID, lessons[0].ScheduleID, monday)
+		mustNoErr(t, err)
+		mustEqual(t, "entries", len(sheet), 2)
+		mustEqual(t, "first", sheet[0].UserID, alice)
+		mustEqual(t, "status of unmarked", status(t, sheet[0]), "")
+		mustEqual(t, "status of marked", status(t, sheet[1]), attendance.Absent)
+
+		sheet, err = deps.Attendance.GetSheet(ctx, groupID, lessons[0].ScheduleID, monday.AddDate(0, 0, 14))
+		mustNoErr(t, err)
+		mustEqual(t, "status on another date", status(t, sheet[1]), "")
+	})
+
+	t.Run("upsert replaces the mark and check in keeps it", func(t *testing.T) {
+		groupID := deps.group(t)
+		lessons := deps.lessons(t, groupID, "Algebra")
+		student := deps.member(t, groupID, "Student")
+
+		mustNoErr(t, deps.Attendance.Upsert(ctx, []attendance.Record{
+			newRecord(groupID, lessons[0].ScheduleID, student, monday, attendance.Absent),
+		}))
+		mustNoErr(t, deps.Attendance.Upsert(ctx, []attendance.Record{
+			newRecord(groupID, lessons[0].ScheduleID, student, monday, attendance.Excused),
+		}))
+
+		checkIn := newRecord(groupID, lessons[0].ScheduleID, student, monday, attendance.Present)
+		checkIn.Source = attendance.SourceCheckin
+		mustNoErr(t, deps.Attendance.CheckIn(ctx, checkIn))
+
+		records, err := deps.Attendance.GetAllByUserId(ctx, student, attendance.FilterDTO{})
+		mustNoErr(t, err)
+		mustEqual(t, "records", len(records), 1)
+		mustEqual(t, "status", records[0].Status, attendance.Excused)
+		mustEqual(t, "source", records[0].Source, attendance.SourceManual)
+		mustEqual(t, "subject", records[0].SubjectName, "Algebra")
+	})
+
+	t.Run("records of a user are filtered", func(t *testing.T) {
+		groupID := deps.group(t)
+		lessons := deps.lessons(t, groupID, "Algebra", "Physics")
+		student := deps.member(t, groupID, "Student")
+
+		var records []attendance.Record
+
+		for week := range 3 {
+			for _, lesson := range lessons {
+				records = append(records, newRecord(groupID, lesson.ScheduleID, student, monday.AddDate(0, 0, 14*week), attendance.Present))
+			}
+		}
+
+		mustNoErr(t, deps.Attendance.Upsert(ctx, records))
+
+		all, err := deps.Attendance.GetAllByUserId(ctx, student, attendance.FilterDTO{})
+		mustNoErr(t, err)
+		mustEqual(t, "records", len(all), 6)
+		mustEqual(t, "first subject", all[0].SubjectName, "Algebra")
+		mustEqual(t, "second subject", all[1].SubjectName, "Physics")
+		mustEqual(t, "last date", all[5].LessonDate.Equal(monday.AddDate(0, 0, 28)), true)
+
+		from, to := monday.AddDate(0, 0, 14), monday.AddDate(0, 0, 28)
+
+		filtered, err := deps.Attendance.GetAllByUserId(ctx, student, attendance.FilterDTO{From: &from, To: &to, SubjectName: "Physics"})
+		mustNoErr(t, err)
+		mustEqual(t, "filtered records", len(filtered), 2)
+	})
+
+	t.Run("summary counts per member and subject", func(t *testing.T) {
+		groupID := deps.group(t)
+		lessons := deps.lessons(t, groupID, "Algebra", "Physics")
+		alice := deps.member(t, groupID, "Alice")
+		bob := deps.member(t, groupID, "Bob")
+
+		algebra, physics := lessons[0].ScheduleID, lessons[1].ScheduleID
+
+		mustNoErr(t, deps.Attendance.Upsert(ctx, []attendance.Record{
+			newRecord(groupID, algebra, alice, monday, attendance.Present),
+			newRecord(groupID, algebra, alice, monday.AddDate(0, 0, 14), attendance.Absent),
+			newRecord(groupID, physics, alice, monday, attendance.Excused),
+			newRecord(groupID, algebra, bob, monday, attendance.Present),
+		}))
+
+		summary, err := deps.Attendance.GetSummary(ctx, groupID, attendance.FilterDTO{})
+		mustNoErr(t, err)
+		mustEqual(t, "rows", len(summary), 3)
+
+		mustEqual(t, "member", summary[0].UserID, alice)
+		mustEqual(t, "subject", summary[0].SubjectName, "Algebra")
+		mustEqual(t, "present", summary[0].Present, 1)
+		mustEqual(t, "absent", summary[0].Absent, 1)
+		mustEqual(t, "excused in physics", summary[1].Excused, 1)
+		mustEqual(t, "member", summary[2].UserID, bob)
+		mustEqual(t, "total", summary[2].Total(), 1)
+
+		summary, err = deps.Attendance.GetSummary(ctx, groupID, attendance.FilterDTO{SubjectName: "Physics"})
+		mustNoErr(t, err)
+		mustEqual(t, "rows of physics", len(summary), 1)
+	})
+
+	t.Run("windows open, reopen and close", func(t *testing.T) {
+		groupID := deps.group(t)
+		lessons := deps.lessons(t, groupID, "Algebra")
+		now := time.Now().UTC().Truncate(time.Microsecond)
+
+		window := attendance.Window{
+			GroupID:    groupID,
+			ScheduleID: lessons[0].ScheduleID,
+			LessonDate: monday,
+			Secret:     []byte("secret"),
+			OpensAt:    now.Add(-time.Minute),
+			ClosesAt:   now.Add(10 * time.Minute),
+			CreatedAt:  now,
+		}
+
+		windowID, err := deps.Attendance.OpenWindow(ctx, window)
+		mustNoErr(t, err)
+
+		radius := 100
+		window.Radius = &radius
+		window.ClosesAt = now.Add(20 * time.Minute)
+
+		reopenedID, err := deps.Attendance.OpenWindow(ctx, window)
+		mustNoErr(t, err)
+		mustEqual(t, "window of the same lesson", reopenedID, windowID)
+
+		byId, err := deps.Attendance.GetWindowById(ctx, windowID)
+		mustNoErr(t, err)
+		mustEqual(t, "closes at", byId.ClosesAt.Equal(window.ClosesAt), true)
+
+		if byId.Radius == nil || *byId.Radius != radius {
+			t.Fatalf("radius: got %v, want %d", byId.Radius, radius)
+		}
+
+		open, err := deps.Attendance.GetOpenWindowsByGroupId(ctx, groupID, now)
+		mustNoErr(t, err)
+		mustEqual(t, "open windows", len(open), 1)
+
+		mustNoErr(t, deps.Attendance.CloseWindow(ctx, windowID, now))
+
+		open, err = deps.Attendance.GetOpenWindowsByGroupId(ctx, groupID, now)
+		mustNoErr(t, err)
+		mustEqual(t, "open windows after close", len(open), 0)
+	})
+
+	t.Run("missing window", func(t *testing.T) {
+		_, err := deps.Attendance.GetWindowById(ctx, 1<<62)
+		mustNoRows(t, err)
+	})
+}
@@ -0,0 +1,109 @@
+package contract
+
+import (
+	"cmp"
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Schedules is the schedule repository as read by the schedule service and written by the group service
+type Schedules interface {
+	schedule.Repository
+	group.ScheduleRepository
+}
+
+// ContentDeps are the repositories of one store holding what a group publishes, BuildingID and
+// TypeOfSubjectID must reference existing reference data like the IDs of GroupDeps
+type ContentDeps struct {
+	GroupDeps
+	Schedules       Schedules
+	Announcements   announcement.Repository
+	Homework        homework.Repository
+	Attendance      attendance.Repository
+	BuildingID      uint64
+	TypeOfSubjectID uint64
+}
+
+func (d ContentDeps) group(t *testing.T) uint64 {
+	t.Helper()
+
+	groupID, err := d.Groups.Create(context.Background(), group.Group{
+		FacultyID: d.FacultyID,
+		ProgramID: d.ProgramID,
+		ShortName: unique("C"),
+		CreatedAt: time.Now(),
+	})
+	mustNoErr(t, err)
+
+	return groupID
+}
+
+// member creates a student with the full name in the group
+func (d ContentDeps) member(t *testing.T, groupID uint64, fullName string) uint64 {
+	t.Helper()
+
+	ctx := context.Background()
+	chatID := time.Now().UnixNano()
+
+	userID, err := d.Users.Create(ctx, user.User{
+		Role:           user.Student,
+		FullName:       &fullName,
+		TelegramChatID: &chatID,
+		CreatedAt:      time.Now(),
+	})
+	mustNoErr(t, err)
+
+	_, err = d.Members.Create(ctx, userID, groupID)
+	mustNoErr(t, err)
+
+	return userID
+}
+
+// lessons uploads the subjects on Monday of the odd week and returns them ordered like the input
+func (d ContentDeps) lessons(t *testing.T, groupID uint64, subjects ...string) []schedule.DetailsScheduleDTO {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var schedules []schedule.Schedule
+
+	for i, subject := range subjects {
+		schedules = append(schedules, schedule.Schedule{
+			GroupID:         groupID,
+			BuildingsID:     d.BuildingID,
+			TypeOfSubjectID: d.TypeOfSubjectID,
+			SubjectName:     subject,
+			Teacher:         "Teacher",
+			Room:            "101",
+			DayOfWeek:       1,
+			StartTime:       time.Date(0, 1, 1, 9+2*i, 0, 0, 0, time.UTC).Format("15:04"),
+			EndTime:         time.Date(0, 1, 1, 10+2*i, 30, 0, 0, time.UTC).Format("15:04"),
+			CreatedAt:       time.Now(),
+		})
+	}
+
+	mustNoErr(t, d.Schedules.Create(ctx, schedules))
+
+	lessons, err := d.Schedules.GetSchedulesByGroupId(ctx, schedule.FilterDTO{}, groupID)
+	mustNoErr(t, err)
+	mustEqual(t, "lessons", len(lessons), len(subjects))
+
+	slices.SortFunc(lessons, func(a, b schedule.DetailsScheduleDTO) int {
+		return cmp.Compare(a.StartTime, b.StartTime)
+	})
+
+	return lessons
+}
+
+// day returns midnight UTC of the day, the way lesson dates and deadlines are stored
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,44 @@
+// Package contract holds behaviour suites every implementation of a repository interface must pass,
+// the postgres repositories with a database from testutil and the in-memory ones from package memory.
+// Call a suite from a test with fresh repositories, e.g. contract.UserRepository(t, memory.NewUserRepository(memory.NewStore()))
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var seq atomic.Int64
+
+// unique returns a value not used by any other suite run against the same database
+func unique(prefix string) string {
+	return fmt.Sprintf("%s%d%d", prefix, time.Now().UnixNano()%1_000_000, seq.Add(1))
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func mustNoRows(t *testing.T, err error) {
+	t.Helper()
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows, got %v", err)
+	}
+}
+
+func mustEqual[T comparable](t *testing.T, name string, got, want T) {
+	t.Helper()
+
+	if got != want {
+		t.Fatalf("%s: got %v, want %v", name, got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package contract
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	"testing"
+)
+
+// EduRepository checks the reference data is served consistently, the repository must hold
+// at least one faculty with a program, a type of subject and a building
+func EduRepository(t *testing.T, repo edu.Repository) {
+	ctx := context.Background()
+
+	t.Run("faculties and their programs", func(t *testing.T) {
+		faculties, err := repo.GetAllFaculty(ctx)
+		mustNoErr(t, err)
+
+		if len(faculties) == 0 {
+			t.Fatal("no faculties")
+		}
+
+		var programs int
+
+		for _, faculty := range faculties {
+			byId, err := repo.GetFacultyById(ctx, faculty.FacultyID)
+			mustNoErr(t, err)
+			mustEqual(t, "faculty", byId, faculty)
+
+			items, err := repo.GetAllProgramsByFacultyId(ctx, faculty.FacultyID)
+			mustNoErr(t, err)
+
+			for _, program := range items {
+				mustEqual(t, "faculty of program", program.FacultyID, faculty.FacultyID)
+
+				byId, err := repo.GetProgramById(ctx, program.ProgramID)
+				mustNoErr(t, err)
+				mustEqual(t, "program", byId, program)
+			}
+
+			programs += len(items)
+		}
+
+		if programs == 0 {
+			t.Fatal("no programs")
+		}
+
+		items, err := repo.GetAllProgramsByFacultyId(ctx, 1<<62)
+		mustNoErr(t, err)
+		mustEqual(t, "programs of a missing faculty", len(items), 0)
+	})
+
+	t.Run("types of subject", func(t *testing.T) {
+		types, err := repo.GetAllTypesOfSubject(ctx)
+		mustNoErr(t, err)
+
+		if len(types) == 0 {
+			t.Fatal("no types of subject")
+		}
+
+		for _, typeOfSubject := range types {
+			byId, err := repo.GetTypeOfSubjectById(ctx, typeOfSubject.TypeOfSubjectID)
+			mustNoErr(t, err)
+			mustEqual(t, "type of subject", byId, typeOfSubject)
+		}
+	})
+
+	t.Run("buildings", func(t *testing.T) {
+		buildings, err := repo.GetAllBuildings(ctx)
+		mustNoErr(t, err)
+
+		if len(buildings) == 0 {
+			t.Fatal("no buildings")
+		}
+
+		for _, building := range buildings {
+			byId, err := repo.GetBuildingById(ctx, building.BuildingID)
+			mustNoErr(t, err)
+			mustEqual(t, "building", byId, building)
+		}
+	})
+
+	t.Run("missing reference data", func(t *testing.T) {
+		_, err := repo.GetFacultyById(ctx, 1<<62)
+		mustNoRows(t, err)
+
+		_, err = repo.GetProgramById(ctx, 1<<62)
+		mustNoRows(t, err)
+
+		_, err = repo.GetTypeOfSubjectById(ctx, 1<<62)
+		mustNoRows(t, err)
+
+		_, err = repo.GetBuildingById(ctx, 1<<62)
+		mustNoRows(t, err)
+	})
+}
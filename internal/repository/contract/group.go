@@ -0,0 +1,219 @@
+package contract
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"testing"
+	"time"
+)
+
+// GroupDeps are the repositories of one store, FacultyID and ProgramID must reference existing
+// reference data, programs belong to the faculty
+type GroupDeps struct {
+	Groups    group.Repository
+	Members   group.MemberRepository
+	Waitlist  group.WaitlistRepository
+	Users     user.Repository
+	FacultyID uint64
+	ProgramID uint64
+}
+
+func GroupRepository(t *testing.T, deps GroupDeps) {
+	ctx := context.Background()
+
+	newGroup := func(t *testing.T, capacity *int) group.Group {
+		t.Helper()
+
+		entity := group.Group{
+			FacultyID: deps.FacultyID,
+			ProgramID: deps.ProgramID,
+			ShortName: unique("G"),
+			Capacity:  capacity,
+			CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		}
+
+		groupID, err := deps.Groups.Create(ctx, entity)
+		mustNoErr(t, err)
+
+		entity.GroupID = groupID
+
+		return entity
+	}
+
+	newUser := func(t *testing.T) uint64 {
+		t.Helper()
+
+		chatID := time.Now().UnixNano()
+
+		userID, err := deps.Users.Create(ctx, user.User{Role: user.Student, TelegramChatID: &chatID, CreatedAt: time.Now()})
+		mustNoErr(t, err)
+
+		return userID
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		capacity := 30
+		created := newGroup(t, &capacity)
+
+		byId, err := deps.Groups.GetById(ctx, created.GroupID)
+		mustNoErr(t, err)
+		mustEqual(t, "short name", byId.ShortName, created.ShortName)
+		mustEqual(t, "capacity", *byId.Capacity, capacity)
+		mustEqual(t, "number of people", byId.NumberOfPeople, 0)
+
+		byName, err := deps.Groups.GetByShortName(ctx, created.ShortName)
+		mustNoErr(t, err)
+		mustEqual(t, "group id", byName.GroupID, created.GroupID)
+
+		locked, err := deps.Groups.GetByIdForUpdate(ctx, created.GroupID)
+		mustNoErr(t, err)
+		mustEqual(t, "group id", locked.GroupID, created.GroupID)
+
+		details, err := deps.Groups.GetDetailsGroupById(ctx, created.GroupID)
+		mustNoErr(t, err)
+		mustEqual(t, "short name", details.ShortName, created.ShortName)
+	})
+
+	t.Run("duplicate short name is a unique violation", func(t *testing.T) {
+		created := newGroup(t, nil)
+		created.GroupID = 0
+
+		_, err := deps.Groups.Create(ctx, created)
+		if !postgresql.IsUniqueViolation(err) {
+			t.Fatalf("expected a unique violation, got %v", err)
+		}
+	})
+
+	t.Run("update keeps number of people", func(t *testing.T) {
+		created := newGroup(t, nil)
+
+		count, err := deps.Groups.AddMembers(ctx, created.GroupID, 2)
+		mustNoErr(t, err)
+		mustEqual(t, "number of people", count, 2)
+
+		created.ExistsSchedule = true
+		created.NumberOfPeople = 100
+		mustNoErr(t, deps.Groups.Update(ctx, created))
+
+		found, err := deps.Groups.GetById(ctx, created.GroupID)
+		mustNoErr(t, err)
+		mustEqual(t, "exists schedule", found.ExistsSchedule, true)
+		mustEqual(t, "number of people", found.NumberOfPeople, 2)
+	})
+
+	t.Run("number of people cannot go below zero", func(t *testing.T) {
+		created := newGroup(t, nil)
+
+		if _, err := deps.Groups.AddMembers(ctx, created.GroupID, -1); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("members", func(t *testing.T) {
+		created := newGroup(t, nil)
+		userID := newUser(t)
+
+		_, err := deps.Members.Create(ctx, userID, created.GroupID)
+		mustNoErr(t, err)
+
+		_, err = deps.Members.Create(ctx, userID, created.GroupID)
+		if !postgresql.IsUniqueViolation(err) {
+			t.Fatalf("expected a unique violation, got %v", err)
+		}
+
+		groupID, err := deps.Members.GetGroupIdByUserId(ctx, userID)
+		mustNoErr(t, err)
+		mustEqual(t, "group id", groupID, created.GroupID)
+
+		mustNoErr(t, deps.Members.Delete(ctx, userID))
+		mustNoRows(t, deps.Members.Delete(ctx, userID))
+
+		_, err = deps.Members.GetGroupIdByUserId(ctx, userID)
+		mustNoRows(t, err)
+	})
+
+	t.Run("waitlist keeps order", func(t *testing.T) {
+		created := newGroup(t, nil)
+		first, second := newUser(t), newUser(t)
+
+		firstID, err := deps.Waitlist.Create(ctx, group.WaitlistEntry{GroupID: created.GroupID, UserID: first, CreatedAt: time.Now()})
+		mustNoErr(t, err)
+
+		secondID, err := deps.Waitlist.Create(ctx, group.WaitlistEntry{GroupID: created.GroupID, UserID: second, CreatedAt: time.Now()})
+		mustNoErr(t, err)
+
+		_, err = deps.Waitlist.Create(ctx, group.WaitlistEntry{GroupID: created.GroupID, UserID: first, CreatedAt: time.Now()})
+		if !postgresql.IsUniqueViolation(err) {
+			t.Fatalf("expected a unique violation, got %v", err)
+		}
+
+		position, err := deps.Waitlist.Position(ctx, created.GroupID, secondID)
+		mustNoErr(t, err)
+		mustEqual(t, "position", position, 2)
+
+		entry, err := deps.Waitlist.GetFirstByGroupId(ctx, created.GroupID)
+		mustNoErr(t, err)
+		mustEqual(t, "first user", entry.UserID, first)
+
+		mustNoErr(t, deps.Waitlist.Delete(ctx, firstID))
+		mustNoErr(t, deps.Waitlist.DeleteByUserId(ctx, second))
+		mustNoRows(t, deps.Waitlist.DeleteByUserId(ctx, second))
+
+		_, err = deps.Waitlist.GetFirstByGroupId(ctx, created.GroupID)
+		mustNoRows(t, err)
+	})
+
+	t.Run("delete removes members", func(t *testing.T) {
+		created := newGroup(t, nil)
+		userID := newUser(t)
+
+		_, err := deps.Members.Create(ctx, userID, created.GroupID)
+		mustNoErr(t, err)
+
+		mustNoErr(t, deps.Groups.Delete(ctx, created.GroupID))
+
+		_, err = deps.Groups.GetById(ctx, created.GroupID)
+		mustNoRows(t, err)
+
+		_, err = deps.Members.GetGroupIdByUserId(ctx, userID)
+		mustNoRows(t, err)
+	})
+
+	t.Run("summary pages by search", func(t *testing.T) {
+		prefix := unique("S")
+
+		for i := 0; i < 3; i++ {
+			_, err := deps.Groups.Create(ctx, group.Group{
+				FacultyID: deps.FacultyID,
+				ProgramID: deps.ProgramID,
+				ShortName: prefix + string(rune('a'+i)),
+				CreatedAt: time.Now(),
+			})
+			mustNoErr(t, err)
+		}
+
+		groups, total, err := deps.Groups.GetSummaryGroups(ctx, group.FilterDTO{
+			Search: prefix,
+			SortBy: group.SortByName,
+			Limit:  2,
+			Offset: 1,
+		})
+		mustNoErr(t, err)
+		mustEqual(t, "total", total, 3)
+		mustEqual(t, "page size", len(groups), 2)
+		mustEqual(t, "first on page", groups[0].ShortName, prefix+"b")
+	})
+
+	t.Run("missing group", func(t *testing.T) {
+		_, err := deps.Groups.GetById(ctx, 1<<62)
+		mustNoRows(t, err)
+
+		_, err = deps.Groups.GetByShortName(ctx, unique("missing"))
+		mustNoRows(t, err)
+
+		_, err = deps.Groups.AddMembers(ctx, 1<<62, 1)
+		mustNoRows(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+package contract
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"testing"
+	"time"
+)
+
+func HomeworkRepository(t *testing.T, deps ContentDeps) {
+	ctx := context.Background()
+
+	newHomework := func(t *testing.T, groupID uint64, dueDate time.Time, modify ...func(*homework.Homework)) homework.Homework {
+		t.Helper()
+
+		entity := homework.Homework{
+			GroupID:     groupID,
+			Description: unique("homework"),
+			DueDate:     dueDate,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		for _, fn := range modify {
+			fn(&entity)
+		}
+
+		homeworkID, err := deps.Homework.Create(ctx, entity)
+		mustNoErr(t, err)
+
+		entity.HomeworkID = homeworkID
+
+		return entity
+	}
+
+	t.Run("create, update and delete", func(t *testing.T) {
+		groupID := deps.group(t)
+		lessons := deps.lessons(t, groupID, "Algebra")
+		subject := "Algebra"
+
+		created := newHomework(t, groupID, day(2025, time.March, 3), func(h *homework.Homework) {
+			h.ScheduleID = &lessons[0].ScheduleID
+			h.SubjectName = &subject
+		})
+
+		byId, err := deps.Homework.GetById(ctx, created.HomeworkID)
+		mustNoErr(t, err)
+		mustEqual(t, "description", byId.Description, created.Description)
+		mustEqual(t, "due date", byId.DueDate.Equal(created.DueDate), true)
+
+		if byId.ScheduleID == nil || *byId.ScheduleID != lessons[0].ScheduleID {
+			t.Fatalf("schedule: got %v, want %d", byId.ScheduleID, lessons[0].ScheduleID)
+		}
+
+		byId.Description = "updated"
+		byId.ScheduleID = nil
+		mustNoErr(t, deps.Homework.Update(ctx, byId))
+
+		updated, err := deps.Homework.GetById(ctx, created.HomeworkID)
+		mustNoErr(t, err)
+		mustEqual(t, "description", updated.Description, "updated")
+
+		if updated.ScheduleID != nil {
+			t.Fatalf("schedule: got %d, want none", *updated.ScheduleID)
+		}
+
+		mustNoErr(t, deps.Homework.Delete(ctx, created.HomeworkID))
+
+		_, err = deps.Homework.GetById(ctx, created.HomeworkID)
+		mustNoRows(t, err)
+	})
+
+	t.Run("homework is selected by due date", func(t *testing.T) {
+		groupID := deps.group(t)
+
+		later := newHomework(t, groupID, day(2025, time.March, 5))
+		first := newHomework(t, groupID, day(2025, time.March, 3))
+		second := newHomework(t, groupID, day(2025, time.March, 3))
+		newHomework(t, groupID, day(2025, time.March, 10))
+		newHomework(t, deps.group(t), day(2025, time.March, 3))
+
+		items, err := deps.Homework.GetAllByGroupId(ctx, groupID, 0, homework.FilterDTO{
+			From: day(2025, time.March, 3),
+			To:   day(2025, time.March, 5),
+		})
+		mustNoErr(t, err)
+		mustEqual(t, "homework", len(items), 3)
+
+		for i, want := range []homework.Homework{first, second, later} {
+			mustEqual(t, "homework", items[i].HomeworkID, want.HomeworkID)
+		}
+	})
+
+	t.Run("done marks are per user", func(t *testing.T) {
+		groupID := deps.group(t)
+		student := deps.member(t, groupID, "Student")
+		other := deps.member(t, groupID, "Other")
+
+		item := newHomework(t, groupID, day(2025, time.March, 3))
+		filter := homework.FilterDTO{From: item.DueDate, To: item.DueDate}
+
+		mustNoErr(t, deps.Homework.MarkDone(ctx, item.HomeworkID, student, time.Now()))
+		mustNoErr(t, deps.Homework.MarkDone(ctx, item.HomeworkID, student, time.Now()))
+
+		items, err := deps.Homework.GetAllByGroupId(ctx, groupID, student, filter)
+		mustNoErr(t, err)
+		mustEqual(t, "done by student", items[0].Done, true)
+
+		items, err = deps.Homework.GetAllByGroupId(ctx, groupID, other, filter)
+		mustNoErr(t, err)
+		mustEqual(t, "done by another member", items[0].Done, false)
+
+		mustNoErr(t, deps.Homework.UnmarkDone(ctx, item.HomeworkID, student))
+		mustNoErr(t, deps.Homework.UnmarkDone(ctx, item.HomeworkID, student))
+
+		items, err = deps.Homework.GetAllByGroupId(ctx, groupID, student, filter)
+		mustNoErr(t, err)
+		mustEqual(t, "done after unmark", items[0].Done, false)
+	})
+
+	t.Run("missing homework", func(t *testing.T) {
+		_, err := deps.Homework.GetById(ctx, 1<<62)
+		mustNoRows(t, err)
+	})
+}
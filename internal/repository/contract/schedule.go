@@ -0,0 +1,161 @@
+package contract
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ScheduleRepository(t *testing.T, deps ContentDeps) {
+	ctx := context.Background()
+
+	newExam := func(groupID uint64, term string, date time.Time, clock string) schedule.Exam {
+		return schedule.Exam{
+			GroupID:     groupID,
+			BuildingsID: deps.BuildingID,
+			Term:        term,
+			Kind:        schedule.ExamKindExam,
+			SubjectName: unique("exam"),
+			Examiner:    "Examiner",
+			Room:        "201",
+			Date:        date,
+			StartTime:   clock,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+	}
+
+	t.Run("create and get lessons", func(t *testing.T) {
+		groupID := deps.group(t)
+		lessons := deps.lessons(t, groupID, "Algebra", "Physics")
+
+		mustEqual(t, "subject", lessons[0].SubjectName, "Algebra")
+		mustEqual(t, "building", lessons[0].Building.BuildingID, deps.BuildingID)
+
+		if !strings.HasPrefix(lessons[0].StartTime, "09:00") {
+			t.Fatalf("start time: got %q, want 09:00", lessons[0].StartTime)
+		}
+
+		byId, err := deps.Schedules.GetById(ctx, lessons[1].ScheduleID)
+		mustNoErr(t, err)
+		mustEqual(t, "group", byId.GroupID, groupID)
+		mustEqual(t, "subject", byId.SubjectName, "Physics")
+		mustEqual(t, "type of subject", byId.TypeOfSubjectID, deps.TypeOfSubjectID)
+	})
+
+	t.Run("lessons are filtered by week parity", func(t *testing.T) {
+		groupID := deps.group(t)
+
+		var schedules []schedule.Schedule
+
+		for _, isEven := range []bool{false, true, true} {
+			schedules = append(schedules, schedule.Schedule{
+				GroupID:         groupID,
+				BuildingsID:     deps.BuildingID,
+				TypeOfSubjectID: deps.TypeOfSubjectID,
+				SubjectName:     "Parity",
+				IsEven:          isEven,
+				DayOfWeek:       2,
+				StartTime:       "12:00",
+				EndTime:         "13:30",
+				CreatedAt:       time.Now(),
+			})
+		}
+
+		// a lesson of a single-week schedule matches both parities
+		everyWeek := schedules[0]
+		everyWeek.EveryWeek = true
+		schedules = append(schedules, everyWeek)
+
+		mustNoErr(t, deps.Schedules.Create(ctx, schedules))
+
+		for filter, want := range map[string]int{"": 4, "true": 3, "false": 2} {
+			lessons, err := deps.Schedules.GetSchedulesByGroupId(ctx, schedule.FilterDTO{IsEven: filter}, groupID)
+			mustNoErr(t, err)
+			mustEqual(t, "lessons with is_even "+filter, len(lessons), want)
+		}
+
+		other, err := deps.Schedules.GetSchedulesByGroupId(ctx, schedule.FilterDTO{}, deps.group(t))
+		mustNoErr(t, err)
+		mustEqual(t, "lessons of another group", len(other), 0)
+	})
+
+	t.Run("exams are ordered and filtered", func(t *testing.T) {
+		groupID := deps.group(t)
+
+		first := newExam(groupID, "winter", day(2025, time.January, 10), "09:00")
+		second := newExam(groupID, "winter", day(2025, time.January, 10), "14:00")
+		third := newExam(groupID, "winter", day(2025, time.January, 20), "09:00")
+		summer := newExam(groupID, "summer", day(2025, time.June, 15), "09:00")
+
+		mustNoErr(t, deps.Schedules.CreateExams(ctx, []schedule.Exam{third, summer, second, first}))
+
+		exams, err := deps.Schedules.GetExamsByGroupId(ctx, schedule.ExamFilterDTO{}, groupID)
+		mustNoErr(t, err)
+		mustEqual(t, "exams", len(exams), 4)
+
+		for i, want := range []schedule.Exam{first, second, third, summer} {
+			mustEqual(t, "subject of exam", exams[i].SubjectName, want.SubjectName)
+		}
+
+		mustEqual(t, "building", exams[0].Building.BuildingID, deps.BuildingID)
+
+		exams, err = deps.Schedules.GetExamsByGroupId(ctx, schedule.ExamFilterDTO{Term: "winter"}, groupID)
+		mustNoErr(t, err)
+		mustEqual(t, "winter exams", len(exams), 3)
+
+		from, to := day(2025, time.January, 10), day(2025, time.January, 10)
+
+		exams, err = deps.Schedules.GetExamsByGroupId(ctx, schedule.ExamFilterDTO{From: &from, To: &to}, groupID)
+		mustNoErr(t, err)
+		mustEqual(t, "exams on the day", len(exams), 2)
+	})
+
+	t.Run("update and delete exams", func(t *testing.T) {
+		groupID := deps.group(t)
+
+		mustNoErr(t, deps.Schedules.CreateExams(ctx, []schedule.Exam{
+			newExam(groupID, "winter", day(2025, time.January, 10), "09:00"),
+			newExam(groupID, "winter", day(2025, time.January, 12), "09:00"),
+			newExam(groupID, "summer", day(2025, time.June, 15), "09:00"),
+		}))
+
+		exams, err := deps.Schedules.GetExamsByGroupId(ctx, schedule.ExamFilterDTO{}, groupID)
+		mustNoErr(t, err)
+
+		exam, err := deps.Schedules.GetExamById(ctx, exams[0].ExamID)
+		mustNoErr(t, err)
+		mustEqual(t, "group", exam.GroupID, groupID)
+
+		exam.Room = "301"
+		exam.Kind = schedule.ExamKindRetake
+		mustNoErr(t, deps.Schedules.UpdateExam(ctx, exam))
+
+		updated, err := deps.Schedules.GetExamById(ctx, exam.ExamID)
+		mustNoErr(t, err)
+		mustEqual(t, "room", updated.Room, "301")
+		mustEqual(t, "kind", updated.Kind, schedule.ExamKindRetake)
+
+		mustNoErr(t, deps.Schedules.DeleteExam(ctx, exam.ExamID))
+
+		_, err = deps.Schedules.GetExamById(ctx, exam.ExamID)
+		mustNoRows(t, err)
+
+		mustNoErr(t, deps.Schedules.DeleteExamsByTerm(ctx, groupID, "winter"))
+
+		exams, err = deps.Schedules.GetExamsByGroupId(ctx, schedule.ExamFilterDTO{}, groupID)
+		mustNoErr(t, err)
+		mustEqual(t, "exams left", len(exams), 1)
+		mustEqual(t, "term left", exams[0].Term, "summer")
+	})
+
+	t.Run("missing schedule and exam", func(t *testing.T) {
+		_, err := deps.Schedules.GetById(ctx, 1<<62)
+		mustNoRows(t, err)
+
+		_, err = deps.Schedules.GetExamById(ctx, 1<<62)
+		mustNoRows(t, err)
+	})
+}
@@ -0,0 +1,88 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"testing"
+	"time"
+)
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...postgresql.TxOption) error
+}
+
+// Transactions checks that writes of repositories sharing the store of tx are committed together
+// and rolled back together
+func Transactions(t *testing.T, tx TxManager, users user.Repository) {
+	ctx := context.Background()
+
+	create := func(ctx context.Context, t *testing.T) uint64 {
+		t.Helper()
+
+		email := unique("tx") + "@classflow.test"
+
+		userID, err := users.Create(ctx, user.User{Email: &email, Role: user.Admin, CreatedAt: time.Now()})
+		mustNoErr(t, err)
+
+		return userID
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		var userID uint64
+
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			userID = create(ctx, t)
+			return nil
+		})
+		mustNoErr(t, err)
+
+		_, err = users.GetById(ctx, userID)
+		mustNoErr(t, err)
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		var userID uint64
+		failure := errors.New("failure")
+
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			userID = create(ctx, t)
+
+			if _, err := users.GetById(ctx, userID); err != nil {
+				t.Fatalf("write is not visible within the transaction: %v", err)
+			}
+
+			return failure
+		})
+
+		if !errors.Is(err, failure) {
+			t.Fatalf("expected the error of fn, got %v", err)
+		}
+
+		_, err = users.GetById(ctx, userID)
+		mustNoRows(t, err)
+	})
+
+	t.Run("nested call joins the outer transaction", func(t *testing.T) {
+		var userID uint64
+		failure := errors.New("failure")
+
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			err := tx.WithinTx(ctx, func(ctx context.Context) error {
+				userID = create(ctx, t)
+				return nil
+			})
+			mustNoErr(t, err)
+
+			return failure
+		})
+
+		if !errors.Is(err, failure) {
+			t.Fatalf("expected the error of fn, got %v", err)
+		}
+
+		_, err = users.GetById(ctx, userID)
+		mustNoRows(t, err)
+	})
+}
@@ -0,0 +1,102 @@
+package contract
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"testing"
+	"time"
+)
+
+func UserRepository(t *testing.T, repo user.Repository) {
+	ctx := context.Background()
+
+	newStudent := func(t *testing.T) user.User {
+		t.Helper()
+
+		chatID := time.Now().UnixNano()
+		username := unique("student_")
+
+		entity := user.User{
+			Role:             user.Student,
+			TelegramUsername: &username,
+			TelegramChatID:   &chatID,
+			CreatedAt:        time.Now().UTC().Truncate(time.Microsecond),
+		}
+
+		userID, err := repo.Create(ctx, entity)
+		mustNoErr(t, err)
+
+		entity.UserID = userID
+
+		return entity
+	}
+
+	t.Run("create and get by id and telegram chat id", func(t *testing.T) {
+		created := newStudent(t)
+
+		byId, err := repo.GetById(ctx, created.UserID)
+		mustNoErr(t, err)
+		mustEqual(t, "role", byId.Role, user.Student)
+		mustEqual(t, "telegram username", *byId.TelegramUsername, *created.TelegramUsername)
+
+		byChat, err := repo.GetByTelegramChatId(ctx, *created.TelegramChatID)
+		mustNoErr(t, err)
+		mustEqual(t, "user id", byChat.UserID, created.UserID)
+	})
+
+	t.Run("get by email", func(t *testing.T) {
+		email := unique("user") + "@classflow.test"
+		passwordHash := "hash"
+
+		userID, err := repo.Create(ctx, user.User{
+			Email:        &email,
+			PasswordHash: &passwordHash,
+			Role:         user.Admin,
+			CreatedAt:    time.Now(),
+		})
+		mustNoErr(t, err)
+
+		found, err := repo.GetByEmail(ctx, email)
+		mustNoErr(t, err)
+		mustEqual(t, "user id", found.UserID, userID)
+	})
+
+	t.Run("duplicate email is a unique violation", func(t *testing.T) {
+		email := unique("dup") + "@classflow.test"
+
+		_, err := repo.Create(ctx, user.User{Email: &email, Role: user.Admin, CreatedAt: time.Now()})
+		mustNoErr(t, err)
+
+		_, err = repo.Create(ctx, user.User{Email: &email, Role: user.Admin, CreatedAt: time.Now()})
+		if !postgresql.IsUniqueViolation(err) {
+			t.Fatalf("expected a unique violation, got %v", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		created := newStudent(t)
+
+		fullname := "Updated Name"
+		created.FullName = &fullname
+		created.Role = user.Leader
+
+		mustNoErr(t, repo.Update(ctx, created))
+
+		found, err := repo.GetById(ctx, created.UserID)
+		mustNoErr(t, err)
+		mustEqual(t, "role", found.Role, user.Leader)
+		mustEqual(t, "fullname", *found.FullName, fullname)
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		_, err := repo.GetById(ctx, 1<<62)
+		mustNoRows(t, err)
+
+		_, err = repo.GetByEmail(ctx, unique("missing")+"@classflow.test")
+		mustNoRows(t, err)
+
+		_, err = repo.GetByTelegramChatId(ctx, -time.Now().UnixNano())
+		mustNoRows(t, err)
+	})
+}
@@ -0,0 +1,102 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/repository"
+	"github.com/tclutin/classflow-api/internal/repository/contract"
+	"github.com/tclutin/classflow-api/internal/testutil"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"testing"
+)
+
+// contractDeps empties the shared database and returns the dependencies of the contract suites
+// referencing the first seeded faculty with programs, type of subject and building
+func contractDeps(t *testing.T) (*testutil.Postgres, *repository.Repositories, contract.ContentDeps) {
+	t.Helper()
+
+	ctx := context.Background()
+	pg := testutil.Database(t)
+	repos := repository.NewRepositories(pg.Pool, pg.Pool, testutil.Logger())
+
+	deps := contract.ContentDeps{
+		GroupDeps: contract.GroupDeps{
+			Groups:   repos.Group,
+			Members:  repos.Member,
+			Waitlist: repos.Waitlist,
+			Users:    repos.User,
+		},
+		Schedules:     repos.Schedule,
+		Announcements: repos.Announcement,
+		Homework:      repos.Homework,
+		Attendance:    repos.Attendance,
+	}
+
+	faculties, err := repos.Edu.GetAllFaculty(ctx)
+	mustNoErr(t, err)
+
+	for _, faculty := range faculties {
+		programs, err := repos.Edu.GetAllProgramsByFacultyId(ctx, faculty.FacultyID)
+		mustNoErr(t, err)
+
+		if len(programs) > 0 {
+			deps.FacultyID, deps.ProgramID = faculty.FacultyID, programs[0].ProgramID
+			break
+		}
+	}
+
+	types, err := repos.Edu.GetAllTypesOfSubject(ctx)
+	mustNoErr(t, err)
+
+	buildings, err := repos.Edu.GetAllBuildings(ctx)
+	mustNoErr(t, err)
+
+	if deps.ProgramID == 0 || len(types) == 0 || len(buildings) == 0 {
+		t.Fatal("reference data is not seeded")
+	}
+
+	deps.TypeOfSubjectID, deps.BuildingID = types[0].TypeOfSubjectID, buildings[0].BuildingID
+
+	return pg, repos, deps
+}
+
+func TestUserRepository_Contract(t *testing.T) {
+	_, repos, _ := contractDeps(t)
+	contract.UserRepository(t, repos.User)
+}
+
+func TestGroupRepository_Contract(t *testing.T) {
+	_, _, deps := contractDeps(t)
+	contract.GroupRepository(t, deps.GroupDeps)
+}
+
+func TestTransactions_Contract(t *testing.T) {
+	pg, repos, _ := contractDeps(t)
+	contract.Transactions(t, postgresql.NewTxManager(pg.Pool), repos.User)
+}
+
+func TestEduRepository_Contract(t *testing.T) {
+	_, repos, _ := contractDeps(t)
+	contract.EduRepository(t, repos.Edu)
+}
+
+func TestScheduleRepository_Contract(t *testing.T) {
+	_, _, deps := contractDeps(t)
+	contract.ScheduleRepository(t, deps)
+}
+
+func TestAnnouncementRepository_Contract(t *testing.T) {
+	_, _, deps := contractDeps(t)
+	contract.AnnouncementRepository(t, deps)
+}
+
+func TestHomeworkRepository_Contract(t *testing.T) {
+	_, _, deps := contractDeps(t)
+	contract.HomeworkRepository(t, deps)
+}
+
+func TestAttendanceRepository_Contract(t *testing.T) {
+	_, _, deps := contractDeps(t)
+	contract.AttendanceRepository(t, deps)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tclutin/classflow-api/internal/domain/edu"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
 	"log/slog"
 )
 
@@ -20,11 +21,13 @@ func NewEduRepository(pool *pgxpool.Pool, logger *slog.Logger) *EduRepository {
 }
 
 func (f *EduRepository) GetAllProgramsByFacultyId(ctx context.Context, facultyID uint64) ([]edu.Program, error) {
+	ctx = postgresql.WithQueryName(ctx, "EduRepository.GetAllProgramsByFacultyId")
+
 	sql := `SELECT * FROM public.programs WHERE faculty_id = $1`
 
-	rows, err := f.pool.Query(ctx, sql, facultyID)
+	rows, err := postgresql.Conn(ctx, f.pool).Query(ctx, sql, facultyID)
 	if err != nil {
-		f.logger.Error("Failed to get programs by faculty ID",
+		f.logger.ErrorContext(ctx, "Failed to get programs by faculty ID",
 			"error", err,
 			"facultyID", facultyID,
 		)
@@ -37,7 +40,7 @@ func (f *EduRepository) GetAllProgramsByFacultyId(ctx context.Context, facultyID
 	for rows.Next() {
 		var program edu.Program
 		if err = rows.Scan(&program.ProgramID, &program.FacultyID, &program.Name); err != nil {
-			f.logger.Error("Failed to scan program row",
+			f.logger.ErrorContext(ctx, "Failed to scan program row",
 				"error", err,
 				"facultyID", facultyID,
 			)
@@ -51,11 +54,13 @@ func (f *EduRepository) GetAllProgramsByFacultyId(ctx context.Context, facultyID
 }
 
 func (f *EduRepository) GetAllFaculty(ctx context.Context) ([]edu.Faculty, error) {
+	ctx = postgresql.WithQueryName(ctx, "EduRepository.GetAllFaculty")
+
 	sql := `SELECT * FROM public.faculties`
 
-	rows, err := f.pool.Query(ctx, sql)
+	rows, err := postgresql.Conn(ctx, f.pool).Query(ctx, sql)
 	if err != nil {
-		f.logger.Error("Failed to get all faculties",
+		f.logger.ErrorContext(ctx, "Failed to get all faculties",
 			"error", err,
 		)
 		return nil, err
@@ -67,7 +72,7 @@ func (f *EduRepository) GetAllFaculty(ctx context.Context) ([]edu.Faculty, error
 	for rows.Next() {
 		var faculty edu.Faculty
 		if err = rows.Scan(&faculty.FacultyID, &faculty.Name); err != nil {
-			f.logger.Error("Failed to scan faculty row",
+			f.logger.ErrorContext(ctx, "Failed to scan faculty row",
 				"error", err,
 			)
 			return nil, err
@@ -80,11 +85,13 @@ func (f *EduRepository) GetAllFaculty(ctx context.Context) ([]edu.Faculty, error
 }
 
 func (f *EduRepository) GetAllBuildings(ctx context.Context) ([]edu.Building, error) {
+	ctx = postgresql.WithQueryName(ctx, "EduRepository.GetAllBuildings")
+
 	sql := `SELECT * FROM public.buildings`
 
-	rows, err := f.pool.Query(ctx, sql)
+	rows, err := postgresql.Conn(ctx, f.pool).Query(ctx, sql)
 	if err != nil {
-		f.logger.Error("Failed to get all buildings",
+		f.logger.ErrorContext(ctx, "Failed to get all buildings",
 			"error", err,
 		)
 		return nil, err
@@ -96,7 +103,7 @@ func (f *EduRepository) GetAllBuildings(ctx context.Context) ([]edu.Building, er
 	for rows.Next() {
 		var building edu.Building
 		if err = rows.Scan(&building.BuildingID, &building.Name, &building.Latitude, &building.Longitude, &building.Address); err != nil {
-			f.logger.Error("Failed to scan building row",
+			f.logger.ErrorContext(ctx, "Failed to scan building row",
 				"error", err,
 			)
 			return nil, err
@@ -109,14 +116,16 @@ func (f *EduRepository) GetAllBuildings(ctx context.Context) ([]edu.Building, er
 }
 
 func (f *EduRepository) GetBuildingById(ctx context.Context, buildingID uint64) (edu.Building, error) {
+	ctx = postgresql.WithQueryName(ctx, "EduRepository.GetBuildingById")
+
 	sql := `SELECT * FROM public.buildings WHERE buildings_id = $1`
 
-	row := f.pool.QueryRow(ctx, sql, buildingID)
+	row := postgresql.Conn(ctx, f.pool).QueryRow(ctx, sql, buildingID)
 
 	var building edu.Building
 
 	if err := row.Scan(&building.BuildingID, &building.Name, &building.Latitude, &building.Longitude, &building.Address); err != nil {
-		f.logger.Error("Failed to get building by ID",
+		f.logger.ErrorContext(ctx, "Failed to get building by ID",
 			"error", err,
 			"buildingID", buildingID,
 		)
@@ -127,11 +136,13 @@ func (f *EduRepository) GetBuildingById(ctx context.Context, buildingID uint64)
 }
 
 func (f *EduRepository) GetAllTypesOfSubject(ctx context.Context) ([]edu.TypeOfSubject, error) {
+	ctx = postgresql.WithQueryName(ctx, "EduRepository.GetAllTypesOfSubject")
+
 	sql := `SELECT * FROM public.type_of_subject`
 
-	rows, err := f.pool.Query(ctx, sql)
+	rows, err := postgresql.Conn(ctx, f.pool).Query(ctx, sql)
 	if err != nil {
-		f.logger.Error("Failed to query types of subjects",
+		f.logger.ErrorContext(ctx, "Failed to query types of subjects",
 			"error", err,
 		)
 		return nil, err
@@ -143,7 +154,7 @@ func (f *EduRepository) GetAllTypesOfSubject(ctx context.Context) ([]edu.TypeOfS
 	for rows.Next() {
 		var typeOfSubject edu.TypeOfSubject
 		if err = rows.Scan(&typeOfSubject.TypeOfSubjectID, &typeOfSubject.Name); err != nil {
-			f.logger.Error("Failed to scan type of subject",
+			f.logger.ErrorContext(ctx, "Failed to scan type of subject",
 				"error", err,
 			)
 			return nil, err
@@ -156,15 +167,17 @@ func (f *EduRepository) GetAllTypesOfSubject(ctx context.Context) ([]edu.TypeOfS
 }
 
 func (f *EduRepository) GetTypeOfSubjectById(ctx context.Context, typeOfSubjectId uint64) (edu.TypeOfSubject, error) {
+	ctx = postgresql.WithQueryName(ctx, "EduRepository.GetTypeOfSubjectById")
+
 	sql := `SELECT * FROM public.type_of_subject WHERE type_of_subject_id = $1`
 
-	row := f.pool.QueryRow(ctx, sql, typeOfSubjectId)
+	row := postgresql.Conn(ctx, f.pool).QueryRow(ctx, sql, typeOfSubjectId)
 
 	var typeOfSubject edu.TypeOfSubject
 
 	err := row.Scan(&typeOfSubject.TypeOfSubjectID, &typeOfSubject.Name)
 	if err != nil {
-		f.logger.Error("Failed to get type of subject by ID",
+		f.logger.ErrorContext(ctx, "Failed to get type of subject by ID",
 			"error", err,
 			"typeOfSubjectId", typeOfSubjectId,
 		)
@@ -175,15 +188,17 @@ func (f *EduRepository) GetTypeOfSubjectById(ctx context.Context, typeOfSubjectI
 }
 
 func (f *EduRepository) GetFacultyById(ctx context.Context, facultyID uint64) (edu.Faculty, error) {
+	ctx = postgresql.WithQueryName(ctx, "EduRepository.GetFacultyById")
+
 	sql := `SELECT * FROM public.faculties WHERE faculty_id = $1`
 
-	row := f.pool.QueryRow(ctx, sql, facultyID)
+	row := postgresql.Conn(ctx, f.pool).QueryRow(ctx, sql, facultyID)
 
 	var faculty edu.Faculty
 
 	err := row.Scan(&faculty.FacultyID, &faculty.Name)
 	if err != nil {
-		f.logger.Error("Failed to get faculty by ID",
+		f.logger.ErrorContext(ctx, "Failed to get faculty by ID",
 			"error", err,
 			"facultyID", facultyID,
 		)
@@ -194,15 +209,17 @@ func (f *EduRepository) GetFacultyById(ctx context.Context, facultyID uint64) (e
 }
 
 func (f *EduRepository) GetProgramById(ctx context.Context, programID uint64) (edu.Program, error) {
+	ctx = postgresql.WithQueryName(ctx, "EduRepository.GetProgramById")
+
 	sql := `SELECT * FROM public.programs WHERE program_id = $1`
 
-	row := f.pool.QueryRow(ctx, sql, programID)
+	row := postgresql.Conn(ctx, f.pool).QueryRow(ctx, sql, programID)
 
 	var program edu.Program
 
 	err := row.Scan(&program.ProgramID, &program.FacultyID, &program.Name)
 	if err != nil {
-		f.logger.Error("Failed to get program by ID",
+		f.logger.ErrorContext(ctx, "Failed to get program by ID",
 			"error", err,
 			"programID", programID,
 		)
@@ -0,0 +1,174 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"testing"
+)
+
+func TestEduRepository_Seeds(t *testing.T) {
+	ctx, repos, _ := setup(t)
+
+	faculties, err := repos.Edu.GetAllFaculty(ctx)
+	mustNoErr(t, err)
+
+	buildings, err := repos.Edu.GetAllBuildings(ctx)
+	mustNoErr(t, err)
+
+	types, err := repos.Edu.GetAllTypesOfSubject(ctx)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{name: "faculties", got: len(faculties), want: 3},
+		{name: "buildings", got: len(buildings), want: 4},
+		{name: "types of subject", got: len(types), want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatalf("got %d seeded %s, want %d", tt.got, tt.name, tt.want)
+			}
+		})
+	}
+}
+
+func TestEduRepository_Programs(t *testing.T) {
+	ctx, repos, _ := setup(t)
+
+	tests := []struct {
+		faculty string
+		want    []string
+	}{
+		{faculty: "ИИТ", want: []string{"Программная инженерия", "Прикладная информатика"}},
+		{faculty: "Математический факультет", want: []string{"Прикладная математика"}},
+		{faculty: "Другое", want: []string{"Другое"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.faculty, func(t *testing.T) {
+			faculty := faculty(t, repos, tt.faculty)
+
+			programs, err := repos.Edu.GetAllProgramsByFacultyId(ctx, faculty.FacultyID)
+			mustNoErr(t, err)
+
+			if len(programs) != len(tt.want) {
+				t.Fatalf("got %d programs, want %d", len(programs), len(tt.want))
+			}
+
+			for _, program := range programs {
+				found, err := repos.Edu.GetProgramById(ctx, program.ProgramID)
+				mustNoErr(t, err)
+
+				if found.FacultyID != faculty.FacultyID || found.Name != program.Name {
+					t.Fatalf("got program %+v, want %+v", found, program)
+				}
+			}
+		})
+	}
+}
+
+func TestEduRepository_GetById(t *testing.T) {
+	ctx, repos, _ := setup(t)
+
+	buildings, err := repos.Edu.GetAllBuildings(ctx)
+	mustNoErr(t, err)
+
+	types, err := repos.Edu.GetAllTypesOfSubject(ctx)
+	mustNoErr(t, err)
+
+	iit := faculty(t, repos, "ИИТ")
+
+	const unknown = 1_000_000
+
+	tests := []struct {
+		name    string
+		get     func(id uint64) (string, error)
+		id      uint64
+		want    string
+		wantErr error
+	}{
+		{
+			name: "building",
+			get: func(id uint64) (string, error) {
+				building, err := repos.Edu.GetBuildingById(ctx, id)
+				return building.Name, err
+			},
+			id:   buildings[0].BuildingID,
+			want: buildings[0].Name,
+		},
+		{
+			name: "type of subject",
+			get: func(id uint64) (string, error) {
+				typeOfSubject, err := repos.Edu.GetTypeOfSubjectById(ctx, id)
+				return typeOfSubject.Name, err
+			},
+			id:   types[0].TypeOfSubjectID,
+			want: types[0].Name,
+		},
+		{
+			name: "faculty",
+			get: func(id uint64) (string, error) {
+				faculty, err := repos.Edu.GetFacultyById(ctx, id)
+				return faculty.Name, err
+			},
+			id:   iit.FacultyID,
+			want: iit.Name,
+		},
+		{
+			name: "unknown building",
+			get: func(id uint64) (string, error) {
+				building, err := repos.Edu.GetBuildingById(ctx, id)
+				return building.Name, err
+			},
+			id:      unknown,
+			wantErr: pgx.ErrNoRows,
+		},
+		{
+			name: "unknown type of subject",
+			get: func(id uint64) (string, error) {
+				typeOfSubject, err := repos.Edu.GetTypeOfSubjectById(ctx, id)
+				return typeOfSubject.Name, err
+			},
+			id:      unknown,
+			wantErr: pgx.ErrNoRows,
+		},
+		{
+			name: "unknown faculty",
+			get: func(id uint64) (string, error) {
+				faculty, err := repos.Edu.GetFacultyById(ctx, id)
+				return faculty.Name, err
+			},
+			id:      unknown,
+			wantErr: pgx.ErrNoRows,
+		},
+		{
+			name: "unknown program",
+			get: func(id uint64) (string, error) {
+				program, err := repos.Edu.GetProgramById(ctx, id)
+				return program.Name, err
+			},
+			id:      unknown,
+			wantErr: pgx.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,33 +2,37 @@ package repository
 
 import (
 	"context"
-	"fmt"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/sqlbuilder"
 	"log/slog"
 	"strings"
 )
 
 type GroupRepository struct {
-	pool   *pgxpool.Pool
-	logger *slog.Logger
+	pool    *pgxpool.Pool
+	replica *pgxpool.Pool
+	logger  *slog.Logger
 }
 
-func NewGroupRepository(pool *pgxpool.Pool, logger *slog.Logger) *GroupRepository {
+func NewGroupRepository(pool *pgxpool.Pool, replica *pgxpool.Pool, logger *slog.Logger) *GroupRepository {
 	return &GroupRepository{
-		pool:   pool,
-		logger: logger,
+		pool:    pool,
+		replica: replica,
+		logger:  logger,
 	}
 }
 
 func (g *GroupRepository) Create(ctx context.Context, group group.Group) (uint64, error) {
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.Create")
+
 	sql := `
 	INSERT INTO public.groups
-    (leader_id, faculty_id, program_id, short_name, exists_schedule, number_of_people, created_at)
-    VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING group_id;`
+    (leader_id, faculty_id, program_id, short_name, exists_schedule, number_of_people, capacity, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING group_id;`
 
-	row := g.pool.QueryRow(
+	row := postgresql.Conn(ctx, g.pool).QueryRow(
 		ctx,
 		sql,
 		group.LeaderID,
@@ -37,12 +41,13 @@ func (g *GroupRepository) Create(ctx context.Context, group group.Group) (uint64
 		group.ShortName,
 		group.ExistsSchedule,
 		group.NumberOfPeople,
+		group.Capacity,
 		group.CreatedAt)
 
 	var groupId uint64
 
 	if err := row.Scan(&groupId); err != nil {
-		g.logger.Error("Failed to create group",
+		g.logger.ErrorContext(ctx, "Failed to create group",
 			"error", err,
 			"faculty_id", group.FacultyID,
 			"program_id", group.ProgramID,
@@ -55,6 +60,8 @@ func (g *GroupRepository) Create(ctx context.Context, group group.Group) (uint64
 }
 
 func (g *GroupRepository) Update(ctx context.Context, group group.Group) error {
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.Update")
+
 	sql := `
 		UPDATE
 			public.groups
@@ -63,27 +70,27 @@ func (g *GroupRepository) Update(ctx context.Context, group group.Group) error {
 			faculty_id = $2,
 			program_id = $3,
 			short_name = $4,
-			number_of_people = $5,
-			exists_schedule = $6,
+			exists_schedule = $5,
+			capacity = $6,
 			created_at = $7
 		WHERE
 		    group_id = $8
 		`
 
-	_, err := g.pool.Exec(
+	_, err := postgresql.Conn(ctx, g.pool).Exec(
 		ctx,
 		sql,
 		group.LeaderID,
 		group.FacultyID,
 		group.ProgramID,
 		group.ShortName,
-		group.NumberOfPeople,
 		group.ExistsSchedule,
+		group.Capacity,
 		group.CreatedAt,
 		group.GroupID)
 
 	if err != nil {
-		g.logger.Error("Failed to update group",
+		g.logger.ErrorContext(ctx, "Failed to update group",
 			"error", err,
 			"group_id", group.GroupID,
 		)
@@ -93,56 +100,42 @@ func (g *GroupRepository) Update(ctx context.Context, group group.Group) error {
 	return nil
 }
 
-func (g *GroupRepository) BeginTx(ctx context.Context) (pgx.Tx, error) {
-	return g.pool.Begin(ctx)
-}
+// AddMembers changes the number of people in SQL, so concurrent joins and leaves never overwrite each other
+func (g *GroupRepository) AddMembers(ctx context.Context, groupID uint64, delta int) (int, error) {
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.AddMembers")
 
-func (g *GroupRepository) UpdateTx(ctx context.Context, tx pgx.Tx, group group.Group) error {
 	sql := `
-		UPDATE
-			public.groups
-		SET
-			leader_id = $1,
-			faculty_id = $2,
-			program_id = $3,
-			short_name = $4,
-			number_of_people = $5,
-			exists_schedule = $6,
-			created_at = $7
-		WHERE
-		    group_id = $8
+		UPDATE public.groups
+		SET number_of_people = number_of_people + $1
+		WHERE group_id = $2
+		RETURNING number_of_people
 		`
 
-	_, err := tx.Exec(
-		ctx,
-		sql,
-		group.LeaderID,
-		group.FacultyID,
-		group.ProgramID,
-		group.ShortName,
-		group.NumberOfPeople,
-		group.ExistsSchedule,
-		group.CreatedAt,
-		group.GroupID)
+	row := postgresql.Conn(ctx, g.pool).QueryRow(ctx, sql, delta, groupID)
 
-	if err != nil {
-		g.logger.Error("Failed to update group",
+	var numberOfPeople int
+
+	if err := row.Scan(&numberOfPeople); err != nil {
+		g.logger.ErrorContext(ctx, "Failed to update number of people",
 			"error", err,
-			"group_id", group.GroupID,
+			"group_id", groupID,
+			"delta", delta,
 		)
-		return err
+		return 0, err
 	}
 
-	return nil
+	return numberOfPeople, nil
 }
 
-func (g *GroupRepository) DeleteTx(ctx context.Context, tx pgx.Tx, groupID uint64) error {
+func (g *GroupRepository) Delete(ctx context.Context, groupID uint64) error {
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.Delete")
+
 	sql := `DELETE FROM public.groups WHERE group_id = $1`
 
-	_, err := tx.Exec(ctx, sql, groupID)
+	_, err := postgresql.Conn(ctx, g.pool).Exec(ctx, sql, groupID)
 
 	if err != nil {
-		g.logger.Error("Failed to delete group",
+		g.logger.ErrorContext(ctx, "Failed to delete group",
 			"error", err,
 			"group_id", groupID,
 		)
@@ -152,51 +145,81 @@ func (g *GroupRepository) DeleteTx(ctx context.Context, tx pgx.Tx, groupID uint6
 	return nil
 }
 
-// GetSummaryGroups TODO: needs sql builder/string builder
-func (g *GroupRepository) GetSummaryGroups(ctx context.Context, filter group.FilterDTO) ([]group.SummaryGroupDTO, error) {
-	sql := `
+var summaryGroupsSort = map[string]string{
+	group.SortByName:      "g.short_name",
+	group.SortBySize:      "g.number_of_people",
+	group.SortByCreatedAt: "g.created_at",
+}
+
+func (g *GroupRepository) GetSummaryGroups(ctx context.Context, filter group.FilterDTO) ([]group.SummaryGroupDTO, int, error) {
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.GetSummaryGroups")
+
+	builder := sqlbuilder.Select(`
 		SELECT
 		    g.group_id,
 			f.faculty_name,
 			p.program_name,
 			g.short_name,
 			g.number_of_people,
-			g.exists_schedule
+			g.capacity,
+			g.exists_schedule,
+			g.created_at
 		FROM
 			public.groups AS g
 		INNER JOIN
 			public.programs AS p ON g.program_id = p.program_id
 		INNER JOIN
 			public.faculties AS f ON g.faculty_id = f.faculty_id
-	`
-
-	var conditions []string
-	var args []interface{}
-	var argCount int
+	`).
+		WhereIf(filter.Faculty != "", "f.faculty_name = ?", filter.Faculty).
+		WhereIf(filter.Program != "", "p.program_name = ?", filter.Program).
+		WhereIf(filter.FacultyIDs != nil, "g.faculty_id = ANY(?)", filter.FacultyIDs).
+		WhereIf(filter.ProgramIDs != nil, "g.program_id = ANY(?)", filter.ProgramIDs).
+		WhereIf(filter.AllowedFacultyIDs != nil, "g.faculty_id = ANY(?)", filter.AllowedFacultyIDs).
+		WhereIf(filter.ExistsSchedule != nil, "g.exists_schedule = ?", filter.ExistsSchedule).
+		WhereIf(filter.Search != "", "(g.short_name ILIKE ? OR g.short_name % ?)", "%"+escapeLike(filter.Search)+"%", filter.Search)
+
+	// the closest trigram matches go first unless the caller asked for an explicit order
+	if filter.Search != "" && filter.SortBy == "" {
+		builder.OrderBy("similarity(g.short_name, ?) DESC", filter.Search)
+	}
 
-	if filter.Faculty != "" {
-		conditions = append(conditions, fmt.Sprintf("f.faculty_name = $%d", argCount+1))
-		args = append(args, filter.Faculty)
-		argCount++
+	column, ok := summaryGroupsSort[filter.SortBy]
+	if !ok {
+		column = summaryGroupsSort[group.SortByName]
 	}
 
-	if filter.Program != "" {
-		conditions = append(conditions, fmt.Sprintf("p.program_name = $%d", argCount+1))
-		args = append(args, filter.Program)
-		argCount++
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
 	}
 
-	if len(conditions) > 0 {
-		sql += " WHERE " + strings.Join(conditions, " AND ")
+	builder.
+		OrderBy(column + " " + direction).
+		OrderBy("g.group_id " + direction).
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+
+	countSql, countArgs := builder.BuildCount()
+
+	var total int
+	if err := postgresql.Conn(ctx, g.replica).QueryRow(ctx, countSql, countArgs...).Scan(&total); err != nil {
+		g.logger.ErrorContext(ctx, "Failed to count summary groups",
+			"error", err,
+			"args", countArgs,
+		)
+		return nil, 0, err
 	}
 
-	rows, err := g.pool.Query(ctx, sql, args...)
+	sql, args := builder.Build()
+
+	rows, err := postgresql.Conn(ctx, g.replica).Query(ctx, sql, args...)
 	if err != nil {
-		g.logger.Error("Failed to get summary groups",
+		g.logger.ErrorContext(ctx, "Failed to get summary groups",
 			"error", err,
 			"args", args,
 		)
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -210,22 +233,26 @@ func (g *GroupRepository) GetSummaryGroups(ctx context.Context, filter group.Fil
 			&group.Program,
 			&group.ShortName,
 			&group.NumberOfPeople,
-			&group.ExistsSchedule)
+			&group.Capacity,
+			&group.ExistsSchedule,
+			&group.CreatedAt)
 
 		if err != nil {
-			g.logger.Error("Failed to scan row in GetSummaryGroups",
+			g.logger.ErrorContext(ctx, "Failed to scan row in GetSummaryGroups",
 				"error", err,
 			)
-			return nil, err
+			return nil, 0, err
 		}
 
 		groups = append(groups, group)
 	}
 
-	return groups, nil
+	return groups, total, nil
 }
 
 func (g *GroupRepository) GetDetailsGroupById(ctx context.Context, groupID uint64) (group.DetailsGroupDTO, error) {
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.GetDetailsGroupById")
+
 	sql := `
 		SELECT 
 			g.group_id,
@@ -234,6 +261,7 @@ func (g *GroupRepository) GetDetailsGroupById(ctx context.Context, groupID uint6
 			p.program_name,
 			g.short_name,
 			g.number_of_people,
+			g.capacity,
 			g.exists_schedule,
 			g.created_at
 		FROM
@@ -246,7 +274,7 @@ func (g *GroupRepository) GetDetailsGroupById(ctx context.Context, groupID uint6
 			group_id = $1
 		`
 
-	row := g.pool.QueryRow(ctx, sql, groupID)
+	row := postgresql.Conn(ctx, g.replica).QueryRow(ctx, sql, groupID)
 
 	var group group.DetailsGroupDTO
 	err := row.Scan(
@@ -256,12 +284,13 @@ func (g *GroupRepository) GetDetailsGroupById(ctx context.Context, groupID uint6
 		&group.Program,
 		&group.ShortName,
 		&group.NumberOfPeople,
+		&group.Capacity,
 		&group.ExistsSchedule,
 		&group.CreatedAt)
 
 	// TODO: если нет такой записи, то сделать варн
 	if err != nil {
-		g.logger.Error("Failed to get detail group with group id",
+		g.logger.ErrorContext(ctx, "Failed to get detail group with group id",
 			"error", err,
 			"group_id", groupID,
 		)
@@ -272,9 +301,25 @@ func (g *GroupRepository) GetDetailsGroupById(ctx context.Context, groupID uint6
 }
 
 func (g *GroupRepository) GetByShortName(ctx context.Context, shortname string) (group.Group, error) {
-	sql := `SELECT * FROM public.groups WHERE short_name = $1`
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.GetByShortName")
 
-	row := g.pool.QueryRow(ctx, sql, shortname)
+	sql := `
+		SELECT
+			group_id,
+			leader_id,
+			faculty_id,
+			program_id,
+			short_name,
+			exists_schedule,
+			number_of_people,
+			capacity,
+			created_at
+		FROM
+			public.groups
+		WHERE short_name = $1
+		`
+
+	row := postgresql.Conn(ctx, g.pool).QueryRow(ctx, sql, shortname)
 
 	var group group.Group
 
@@ -286,10 +331,11 @@ func (g *GroupRepository) GetByShortName(ctx context.Context, shortname string)
 		&group.ShortName,
 		&group.ExistsSchedule,
 		&group.NumberOfPeople,
+		&group.Capacity,
 		&group.CreatedAt)
 
 	if err != nil {
-		g.logger.Error("Failed to get group by shortname",
+		g.logger.ErrorContext(ctx, "Failed to get group by shortname",
 			"error", err,
 			"short_name", shortname,
 		)
@@ -300,15 +346,54 @@ func (g *GroupRepository) GetByShortName(ctx context.Context, shortname string)
 }
 
 func (g *GroupRepository) GetById(ctx context.Context, groupID uint64) (group.Group, error) {
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.GetById")
+
+	sql := `
+		SELECT
+			group_id,
+			leader_id,
+			faculty_id,
+			program_id,
+			short_name,
+			exists_schedule,
+			number_of_people,
+			capacity,
+			created_at
+		FROM
+			public.groups
+		WHERE group_id = $1
+		`
+
+	return g.getById(ctx, sql, groupID)
+}
+
+// GetByIdForUpdate locks the group row until the end of the transaction in ctx,
+// membership changes of one group are applied one at a time
+func (g *GroupRepository) GetByIdForUpdate(ctx context.Context, groupID uint64) (group.Group, error) {
+	ctx = postgresql.WithQueryName(ctx, "GroupRepository.GetByIdForUpdate")
+
 	sql := `
 		SELECT
-			*
+			group_id,
+			leader_id,
+			faculty_id,
+			program_id,
+			short_name,
+			exists_schedule,
+			number_of_people,
+			capacity,
+			created_at
 		FROM
 			public.groups
 		WHERE group_id = $1
+		FOR UPDATE
 		`
 
-	row := g.pool.QueryRow(ctx, sql, groupID)
+	return g.getById(ctx, sql, groupID)
+}
+
+func (g *GroupRepository) getById(ctx context.Context, sql string, groupID uint64) (group.Group, error) {
+	row := postgresql.Conn(ctx, g.pool).QueryRow(ctx, sql, groupID)
 
 	var group group.Group
 
@@ -320,10 +405,11 @@ func (g *GroupRepository) GetById(ctx context.Context, groupID uint64) (group.Gr
 		&group.ShortName,
 		&group.ExistsSchedule,
 		&group.NumberOfPeople,
+		&group.Capacity,
 		&group.CreatedAt)
 
 	if err != nil {
-		g.logger.Error("Failed to get group by id",
+		g.logger.ErrorContext(ctx, "Failed to get group by id",
 			"error", err,
 			"group_id", groupID,
 		)
@@ -331,5 +417,9 @@ func (g *GroupRepository) GetById(ctx context.Context, groupID uint64) (group.Gr
 	}
 
 	return group, nil
+}
 
+// escapeLike escapes the LIKE wildcards so user input matches literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
 }
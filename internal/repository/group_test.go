@@ -0,0 +1,261 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"slices"
+	"testing"
+)
+
+func TestGroupRepository_GetSummaryGroups(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	iit := faculty(t, repos, "ИИТ")
+	math := faculty(t, repos, "Математический факультет")
+
+	inFaculty := func(faculty edu.Faculty, shortName string) func(*group.Group) {
+		return func(g *group.Group) {
+			programs, err := repos.Edu.GetAllProgramsByFacultyId(ctx, faculty.FacultyID)
+			mustNoErr(t, err)
+
+			g.FacultyID = faculty.FacultyID
+			g.ProgramID = programs[0].ProgramID
+			g.ShortName = shortName
+		}
+	}
+
+	alpha, err := fixtures.Group(ctx, inFaculty(iit, "PI-101"))
+	mustNoErr(t, err)
+
+	beta, err := fixtures.Group(ctx, inFaculty(iit, "PI-102"))
+	mustNoErr(t, err)
+
+	gamma, err := fixtures.Group(ctx, inFaculty(math, "PM-201"))
+	mustNoErr(t, err)
+
+	_, err = repos.Group.AddMembers(ctx, beta.GroupID, 3)
+	mustNoErr(t, err)
+
+	_, err = fixtures.Schedule(ctx, gamma.GroupID)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name      string
+		filter    group.FilterDTO
+		want      []uint64
+		wantTotal int
+	}{
+		{
+			name:      "everything by name",
+			filter:    group.FilterDTO{},
+			want:      []uint64{alpha.GroupID, beta.GroupID, gamma.GroupID},
+			wantTotal: 3,
+		},
+		{
+			name:      "by faculty name",
+			filter:    group.FilterDTO{Faculty: iit.Name},
+			want:      []uint64{alpha.GroupID, beta.GroupID},
+			wantTotal: 2,
+		},
+		{
+			name:      "by faculty ids",
+			filter:    group.FilterDTO{FacultyIDs: []uint64{math.FacultyID}},
+			want:      []uint64{gamma.GroupID},
+			wantTotal: 1,
+		},
+		{
+			name:      "allowed faculties narrow other filters",
+			filter:    group.FilterDTO{FacultyIDs: []uint64{iit.FacultyID, math.FacultyID}, AllowedFacultyIDs: []uint64{math.FacultyID}},
+			want:      []uint64{gamma.GroupID},
+			wantTotal: 1,
+		},
+		{
+			name:      "with schedule",
+			filter:    group.FilterDTO{ExistsSchedule: ptr(true)},
+			want:      []uint64{gamma.GroupID},
+			wantTotal: 1,
+		},
+		{
+			name:      "search by substring",
+			filter:    group.FilterDTO{Search: "pi-10"},
+			want:      []uint64{alpha.GroupID, beta.GroupID},
+			wantTotal: 2,
+		},
+		{
+			name:      "search escapes like wildcards",
+			filter:    group.FilterDTO{Search: "%"},
+			want:      nil,
+			wantTotal: 0,
+		},
+		{
+			name:      "by size descending",
+			filter:    group.FilterDTO{SortBy: group.SortBySize, Desc: true},
+			want:      []uint64{beta.GroupID, gamma.GroupID, alpha.GroupID},
+			wantTotal: 3,
+		},
+		{
+			name:      "page",
+			filter:    group.FilterDTO{Limit: 1, Offset: 1},
+			want:      []uint64{beta.GroupID},
+			wantTotal: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, total, err := repos.Group.GetSummaryGroups(ctx, tt.filter)
+			mustNoErr(t, err)
+
+			var got []uint64
+			for _, g := range groups {
+				got = append(got, g.GroupID)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got groups %v, want %v", got, tt.want)
+			}
+
+			if total != tt.wantTotal {
+				t.Fatalf("got total %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestGroupRepository_AddMembers(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name    string
+		delta   int
+		want    int
+		wantErr bool
+	}{
+		{name: "join", delta: 1, want: 1},
+		{name: "join several", delta: 2, want: 3},
+		{name: "leave", delta: -1, want: 2},
+		{name: "below zero", delta: -3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repos.Group.AddMembers(ctx, created.GroupID, tt.delta)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			mustNoErr(t, err)
+
+			if got != tt.want {
+				t.Fatalf("got %d people, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupRepository_Lookup(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx, func(g *group.Group) { g.Capacity = ptr(25) })
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name    string
+		get     func() (group.Group, error)
+		wantErr error
+	}{
+		{
+			name: "by id",
+			get:  func() (group.Group, error) { return repos.Group.GetById(ctx, created.GroupID) },
+		},
+		{
+			name: "by short name",
+			get:  func() (group.Group, error) { return repos.Group.GetByShortName(ctx, created.ShortName) },
+		},
+		{
+			name: "for update outside a transaction",
+			get:  func() (group.Group, error) { return repos.Group.GetByIdForUpdate(ctx, created.GroupID) },
+		},
+		{
+			name:    "unknown id",
+			get:     func() (group.Group, error) { return repos.Group.GetById(ctx, created.GroupID+100) },
+			wantErr: pgx.ErrNoRows,
+		},
+		{
+			name:    "unknown short name",
+			get:     func() (group.Group, error) { return repos.Group.GetByShortName(ctx, "missing") },
+			wantErr: pgx.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && (got.GroupID != created.GroupID || got.Capacity == nil || *got.Capacity != 25) {
+				t.Fatalf("got %+v, want %+v", got, created)
+			}
+		})
+	}
+
+	details, err := repos.Group.GetDetailsGroupById(ctx, created.GroupID)
+	mustNoErr(t, err)
+
+	if details.Faculty == "" || details.Program == "" {
+		t.Fatalf("details miss reference names: %+v", details)
+	}
+}
+
+func TestGroupRepository_Constraints(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(*group.Group)
+		check  func(error) bool
+	}{
+		{
+			name:   "duplicate short name",
+			modify: func(g *group.Group) {},
+			check:  postgresql.IsUniqueViolation,
+		},
+		{
+			name:   "zero capacity",
+			modify: func(g *group.Group) { g.ShortName = "ZERO"; g.Capacity = ptr(0) },
+			check:  func(err error) bool { return err != nil },
+		},
+		{
+			name:   "unknown program",
+			modify: func(g *group.Group) { g.ShortName = "ORPHAN"; g.ProgramID = 1_000_000 },
+			check:  func(err error) bool { return err != nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity := created
+			tt.modify(&entity)
+
+			if _, err := repos.Group.Create(ctx, entity); !tt.check(err) {
+				t.Fatalf("unexpected result: %v", err)
+			}
+		})
+	}
+}
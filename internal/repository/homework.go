@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"log/slog"
+	"time"
+)
+
+type HomeworkRepository struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewHomeworkRepository(pool *pgxpool.Pool, logger *slog.Logger) *HomeworkRepository {
+	return &HomeworkRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+func (h *HomeworkRepository) Create(ctx context.Context, entity homework.Homework) (uint64, error) {
+	ctx = postgresql.WithQueryName(ctx, "HomeworkRepository.Create")
+
+	sql := `
+		INSERT INTO public.homework
+		(group_id, schedule_id, subject_name, description, due_date, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING homework_id
+		`
+
+	row := postgresql.Conn(ctx, h.pool).QueryRow(
+		ctx,
+		sql,
+		entity.GroupID,
+		entity.ScheduleID,
+		entity.SubjectName,
+		entity.Description,
+		entity.DueDate,
+		entity.CreatedBy,
+		entity.CreatedAt,
+		entity.UpdatedAt)
+
+	var homeworkID uint64
+
+	if err := row.Scan(&homeworkID); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to create homework",
+			"error", err,
+			"group_id", entity.GroupID,
+		)
+		return 0, err
+	}
+
+	return homeworkID, nil
+}
+
+func (h *HomeworkRepository) Update(ctx context.Context, entity homework.Homework) error {
+	ctx = postgresql.WithQueryName(ctx, "HomeworkRepository.Update")
+
+	sql := `
+		UPDATE public.homework
+		SET
+			schedule_id = $1,
+			subject_name = $2,
+			description = $3,
+			due_date = $4,
+			updated_at = $5
+		WHERE homework_id = $6
+		`
+
+	_, err := postgresql.Conn(ctx, h.pool).Exec(
+		ctx,
+		sql,
+		entity.ScheduleID,
+		entity.SubjectName,
+		entity.Description,
+		entity.DueDate,
+		entity.UpdatedAt,
+		entity.HomeworkID)
+
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Failed to update homework",
+			"error", err,
+			"homework_id", entity.HomeworkID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (h *HomeworkRepository) Delete(ctx context.Context, homeworkID uint64) error {
+	ctx = postgresql.WithQueryName(ctx, "HomeworkRepository.Delete")
+
+	sql := `DELETE FROM public.homework WHERE homework_id = $1`
+
+	_, err := postgresql.Conn(ctx, h.pool).Exec(ctx, sql, homeworkID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Failed to delete homework",
+			"error", err,
+			"homework_id", homeworkID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (h *HomeworkRepository) GetById(ctx context.Context, homeworkID uint64) (homework.Homework, error) {
+	ctx = postgresql.WithQueryName(ctx, "HomeworkRepository.GetById")
+
+	sql := `
+		SELECT
+			homework_id,
+			group_id,
+			schedule_id,
+			subject_name,
+			description,
+			due_date,
+			created_by,
+			created_at,
+			updated_at
+		FROM
+			public.homework
+		WHERE
+			homework_id = $1
+		`
+
+	row := postgresql.Conn(ctx, h.pool).QueryRow(ctx, sql, homeworkID)
+
+	var entity homework.Homework
+
+	err := row.Scan(
+		&entity.HomeworkID,
+		&entity.GroupID,
+		&entity.ScheduleID,
+		&entity.SubjectName,
+		&entity.Description,
+		&entity.DueDate,
+		&entity.CreatedBy,
+		&entity.CreatedAt,
+		&entity.UpdatedAt)
+
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			h.logger.ErrorContext(ctx, "Failed to get homework by id",
+				"error", err,
+				"homework_id", homeworkID,
+			)
+		}
+		return entity, err
+	}
+
+	return entity, nil
+}
+
+// GetAllByGroupId returns the homework of the group due in the period with the done flag of the user
+func (h *HomeworkRepository) GetAllByGroupId(ctx context.Context, groupID uint64, userID uint64, filter homework.FilterDTO) ([]homework.DetailsHomeworkDTO, error) {
+	ctx = postgresql.WithQueryName(ctx, "HomeworkRepository.GetAllByGroupId")
+
+	sql := `
+		SELECT
+			hw.homework_id,
+			hw.group_id,
+			hw.schedule_id,
+			hw.subject_name,
+			hw.description,
+			hw.due_date,
+			hw.created_by,
+			hw.created_at,
+			hw.updated_at,
+			EXISTS (
+				SELECT 1 FROM public.homework_done AS d
+				WHERE d.homework_id = hw.homework_id AND d.user_id = $2
+			)
+		FROM
+			public.homework AS hw
+		WHERE
+			hw.group_id = $1 AND hw.due_date BETWEEN $3 AND $4
+		ORDER BY
+			hw.due_date,
+			hw.homework_id
+		`
+
+	rows, err := postgresql.Conn(ctx, h.pool).Query(ctx, sql, groupID, userID, filter.From, filter.To)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Failed to get homework",
+			"error", err,
+			"group_id", groupID,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []homework.DetailsHomeworkDTO
+
+	for rows.Next() {
+		var item homework.DetailsHomeworkDTO
+		err = rows.Scan(
+			&item.HomeworkID,
+			&item.GroupID,
+			&item.ScheduleID,
+			&item.SubjectName,
+			&item.Description,
+			&item.DueDate,
+			&item.CreatedBy,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.Done)
+
+		if err != nil {
+			h.logger.ErrorContext(ctx, "Failed to scan row in GetAllByGroupId",
+				"error", err,
+			)
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (h *HomeworkRepository) MarkDone(ctx context.Context, homeworkID uint64, userID uint64, doneAt time.Time) error {
+	ctx = postgresql.WithQueryName(ctx, "HomeworkRepository.MarkDone")
+
+	sql := `
+		INSERT INTO public.homework_done
+			(homework_id, user_id, done_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		`
+
+	_, err := postgresql.Conn(ctx, h.pool).Exec(ctx, sql, homeworkID, userID, doneAt)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Failed to mark homework as done",
+			"error", err,
+			"homework_id", homeworkID,
+			"user_id", userID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (h *HomeworkRepository) UnmarkDone(ctx context.Context, homeworkID uint64, userID uint64) error {
+	ctx = postgresql.WithQueryName(ctx, "HomeworkRepository.UnmarkDone")
+
+	sql := `DELETE FROM public.homework_done WHERE homework_id = $1 AND user_id = $2`
+
+	_, err := postgresql.Conn(ctx, h.pool).Exec(ctx, sql, homeworkID, userID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Failed to unmark homework as done",
+			"error", err,
+			"homework_id", homeworkID,
+			"user_id", userID,
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHomeworkRepository_GetAllByGroupId(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	other, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	student, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	assign := func(groupID uint64, description string, dueDate time.Time) uint64 {
+		t.Helper()
+
+		homeworkID, err := repos.Homework.Create(ctx, homework.Homework{
+			GroupID:     groupID,
+			Description: description,
+			DueDate:     dueDate,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		})
+		mustNoErr(t, err)
+
+		return homeworkID
+	}
+
+	assign(created.GroupID, "monday", date(2026, time.October, 19))
+	done := assign(created.GroupID, "wednesday", date(2026, time.October, 21))
+	assign(created.GroupID, "monday again", date(2026, time.October, 19))
+	assign(created.GroupID, "next week", date(2026, time.October, 26))
+	assign(other.GroupID, "another group", date(2026, time.October, 20))
+
+	mustNoErr(t, repos.Homework.MarkDone(ctx, done, student.UserID, time.Now()))
+	mustNoErr(t, repos.Homework.MarkDone(ctx, done, student.UserID, time.Now()))
+
+	tests := []struct {
+		name   string
+		filter homework.FilterDTO
+		want   []string
+	}{
+		{
+			name:   "week ordered by due date",
+			filter: homework.FilterDTO{From: date(2026, time.October, 19), To: date(2026, time.October, 25)},
+			want:   []string{"monday", "monday again", "wednesday"},
+		},
+		{
+			name:   "inclusive bounds",
+			filter: homework.FilterDTO{From: date(2026, time.October, 21), To: date(2026, time.October, 26)},
+			want:   []string{"wednesday", "next week"},
+		},
+		{
+			name:   "empty range",
+			filter: homework.FilterDTO{From: date(2026, time.November, 1), To: date(2026, time.November, 7)},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := repos.Homework.GetAllByGroupId(ctx, created.GroupID, student.UserID, tt.filter)
+			mustNoErr(t, err)
+
+			var got []string
+			for _, item := range items {
+				got = append(got, item.Description)
+
+				if item.Done != (item.HomeworkID == done) {
+					t.Fatalf("%s: got done %v", item.Description, item.Done)
+				}
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got homework %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHomeworkRepository_Changes(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	student, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	entity := homework.Homework{
+		GroupID:     created.GroupID,
+		SubjectName: ptr("Алгебра"),
+		Description: "№ 1-10",
+		DueDate:     date(2026, time.October, 21),
+		CreatedBy:   &student.UserID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	entity.HomeworkID, err = repos.Homework.Create(ctx, entity)
+	mustNoErr(t, err)
+
+	week := homework.FilterDTO{From: date(2026, time.October, 19), To: date(2026, time.October, 25)}
+
+	tests := []struct {
+		name     string
+		run      func() error
+		want     string
+		wantDone bool
+		wantErr  error
+	}{
+		{
+			name: "update",
+			run: func() error {
+				entity.Description = "№ 1-20"
+				return repos.Homework.Update(ctx, entity)
+			},
+			want: "№ 1-20",
+		},
+		{
+			name:     "mark done",
+			run:      func() error { return repos.Homework.MarkDone(ctx, entity.HomeworkID, student.UserID, time.Now()) },
+			want:     "№ 1-20",
+			wantDone: true,
+		},
+		{
+			name: "unmark done",
+			run:  func() error { return repos.Homework.UnmarkDone(ctx, entity.HomeworkID, student.UserID) },
+			want: "№ 1-20",
+		},
+		{
+			name:    "delete",
+			run:     func() error { return repos.Homework.Delete(ctx, entity.HomeworkID) },
+			wantErr: pgx.ErrNoRows,
+		},
+	}
+
+	// the steps share the homework, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		mustNoErr(t, tt.run())
+
+		found, err := repos.Homework.GetById(ctx, entity.HomeworkID)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: got error %v, want %v", tt.name, err, tt.wantErr)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if found.Description != tt.want {
+			t.Fatalf("%s: got description %q, want %q", tt.name, found.Description, tt.want)
+		}
+
+		items, err := repos.Homework.GetAllByGroupId(ctx, created.GroupID, student.UserID, week)
+		mustNoErr(t, err)
+
+		if len(items) != 1 || items[0].Done != tt.wantDone {
+			t.Fatalf("%s: got %+v, want done %v", tt.name, items, tt.wantDone)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/lockout"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type LockoutRepository struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewLockoutRepository(pool *pgxpool.Pool, logger *slog.Logger) *LockoutRepository {
+	return &LockoutRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+func (l *LockoutRepository) GetByKey(ctx context.Context, key string) (lockout.Attempt, error) {
+	ctx = postgresql.WithQueryName(ctx, "LockoutRepository.GetByKey")
+
+	sql := `SELECT key, failures, locked_until, updated_at FROM public.login_attempts WHERE key = $1`
+
+	row := postgresql.Conn(ctx, l.pool).QueryRow(ctx, sql, key)
+
+	var attempt lockout.Attempt
+
+	err := row.Scan(
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LockedUntil,
+		&attempt.UpdatedAt)
+
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			l.logger.ErrorContext(ctx, "Failed to get login attempt",
+				"error", err,
+				"key", key,
+			)
+		}
+		return attempt, err
+	}
+
+	return attempt, nil
+}
+
+func (l *LockoutRepository) Increment(ctx context.Context, key string, now time.Time, resetBefore time.Time) (lockout.Attempt, error) {
+	ctx = postgresql.WithQueryName(ctx, "LockoutRepository.Increment")
+
+	sql := `
+		INSERT INTO public.login_attempts
+		    (key, failures, locked_until, updated_at)
+		VALUES ($1, 1, NULL, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN GREATEST(login_attempts.updated_at, COALESCE(login_attempts.locked_until, login_attempts.updated_at)) < $3 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			updated_at = $2
+		RETURNING key, failures, locked_until, updated_at
+		`
+
+	row := postgresql.Conn(ctx, l.pool).QueryRow(ctx, sql, key, now, resetBefore)
+
+	var attempt lockout.Attempt
+
+	err := row.Scan(
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LockedUntil,
+		&attempt.UpdatedAt)
+
+	if err != nil {
+		l.logger.ErrorContext(ctx, "Failed to increment login attempts",
+			"error", err,
+			"key", key,
+		)
+		return attempt, err
+	}
+
+	return attempt, nil
+}
+
+func (l *LockoutRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	ctx = postgresql.WithQueryName(ctx, "LockoutRepository.Lock")
+
+	sql := `UPDATE public.login_attempts SET locked_until = $1 WHERE key = $2`
+
+	_, err := postgresql.Conn(ctx, l.pool).Exec(ctx, sql, until, key)
+	if err != nil {
+		l.logger.ErrorContext(ctx, "Failed to lock key",
+			"error", err,
+			"key", key,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (l *LockoutRepository) Delete(ctx context.Context, key string) error {
+	ctx = postgresql.WithQueryName(ctx, "LockoutRepository.Delete")
+
+	sql := `DELETE FROM public.login_attempts WHERE key = $1`
+
+	_, err := postgresql.Conn(ctx, l.pool).Exec(ctx, sql, key)
+	if err != nil {
+		l.logger.ErrorContext(ctx, "Failed to delete login attempts",
+			"error", err,
+			"key", key,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (l *LockoutRepository) DeleteStale(ctx context.Context, resetBefore time.Time) (int64, error) {
+	ctx = postgresql.WithQueryName(ctx, "LockoutRepository.DeleteStale")
+
+	sql := `
+		DELETE FROM public.login_attempts
+		WHERE GREATEST(updated_at, COALESCE(locked_until, updated_at)) < $1
+		`
+
+	tag, err := postgresql.Conn(ctx, l.pool).Exec(ctx, sql, resetBefore)
+	if err != nil {
+		l.logger.ErrorContext(ctx, "Failed to delete stale login attempts",
+			"error", err,
+		)
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// MemoryLockoutRepository keeps attempts in process memory, suitable only for a single instance
+type MemoryLockoutRepository struct {
+	mu       sync.Mutex
+	attempts map[string]lockout.Attempt
+}
+
+func NewMemoryLockoutRepository() *MemoryLockoutRepository {
+	return &MemoryLockoutRepository{
+		attempts: make(map[string]lockout.Attempt),
+	}
+}
+
+func (m *MemoryLockoutRepository) GetByKey(_ context.Context, key string) (lockout.Attempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok {
+		return lockout.Attempt{}, pgx.ErrNoRows
+	}
+
+	return attempt, nil
+}
+
+func (m *MemoryLockoutRepository) Increment(_ context.Context, key string, now time.Time, resetBefore time.Time) (lockout.Attempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok || m.isStale(attempt, resetBefore) {
+		attempt = lockout.Attempt{Key: key}
+	}
+
+	attempt.Failures++
+	attempt.UpdatedAt = now
+
+	m.attempts[key] = attempt
+
+	for k, v := range m.attempts {
+		if m.isStale(v, resetBefore) {
+			delete(m.attempts, k)
+		}
+	}
+
+	return attempt, nil
+}
+
+func (m *MemoryLockoutRepository) Lock(_ context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok {
+		return nil
+	}
+
+	attempt.LockedUntil = &until
+	m.attempts[key] = attempt
+
+	return nil
+}
+
+func (m *MemoryLockoutRepository) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+
+	return nil
+}
+
+func (m *MemoryLockoutRepository) DeleteStale(_ context.Context, resetBefore time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+
+	for key, attempt := range m.attempts {
+		if m.isStale(attempt, resetBefore) {
+			delete(m.attempts, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func (m *MemoryLockoutRepository) isStale(attempt lockout.Attempt, resetBefore time.Time) bool {
+	lastSeen := attempt.UpdatedAt
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(lastSeen) {
+		lastSeen = *attempt.LockedUntil
+	}
+
+	return lastSeen.Before(resetBefore)
+}
@@ -0,0 +1,121 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"testing"
+	"time"
+)
+
+func TestLockoutRepository_Increment(t *testing.T) {
+	ctx, repos, _ := setup(t)
+
+	const key = "email:student@classflow.test"
+
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	window := 15 * time.Minute
+
+	tests := []struct {
+		name string
+		at   time.Time
+		lock *time.Time
+		want int
+	}{
+		{name: "first failure", at: start, want: 1},
+		{name: "failure within the window", at: start.Add(time.Minute), want: 2},
+		{name: "failure while locked", at: start.Add(2 * time.Minute), lock: ptr(start.Add(time.Hour)), want: 3},
+		{name: "lock extends the window", at: start.Add(time.Hour + 10*time.Minute), want: 4},
+		{name: "failure after the window", at: start.Add(2 * time.Hour), want: 1},
+	}
+
+	// the steps share the key, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		attempt, err := repos.Lockout.Increment(ctx, key, tt.at, tt.at.Add(-window))
+		mustNoErr(t, err)
+
+		if attempt.Failures != tt.want {
+			t.Fatalf("%s: got %d failures, want %d", tt.name, attempt.Failures, tt.want)
+		}
+
+		if tt.lock != nil {
+			mustNoErr(t, repos.Lockout.Lock(ctx, key, *tt.lock))
+		}
+	}
+
+	attempt, err := repos.Lockout.GetByKey(ctx, key)
+	mustNoErr(t, err)
+
+	if attempt.LockedUntil == nil || !attempt.LockedUntil.Equal(start.Add(time.Hour)) {
+		t.Fatalf("got locked until %v, want %v", attempt.LockedUntil, start.Add(time.Hour))
+	}
+
+	mustNoErr(t, repos.Lockout.Delete(ctx, key))
+
+	if _, err = repos.Lockout.GetByKey(ctx, key); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows, got %v", err)
+	}
+}
+
+func TestLockoutRepository_LockInOtherZone(t *testing.T) {
+	ctx, repos, _ := setup(t)
+
+	const key = "ip:10.0.0.1"
+
+	until := time.Date(2026, 10, 19, 15, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	_, err := repos.Lockout.Increment(ctx, key, until, until)
+	mustNoErr(t, err)
+	mustNoErr(t, repos.Lockout.Lock(ctx, key, until))
+
+	attempt, err := repos.Lockout.GetByKey(ctx, key)
+	mustNoErr(t, err)
+
+	// the lock ends at the same instant whatever the zone of the caller
+	if attempt.LockedUntil == nil || !attempt.LockedUntil.Equal(until) {
+		t.Fatalf("got locked until %v, want %v", attempt.LockedUntil, until)
+	}
+}
+
+func TestLockoutRepository_DeleteStale(t *testing.T) {
+	ctx, repos, _ := setup(t)
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		key       string
+		updatedAt time.Time
+		lock      *time.Time
+		stale     bool
+	}{
+		{key: "ip:10.0.0.1", updatedAt: now.Add(-time.Hour), stale: true},
+		{key: "ip:10.0.0.2", updatedAt: now.Add(-time.Minute), stale: false},
+		{key: "ip:10.0.0.3", updatedAt: now.Add(-time.Hour), lock: ptr(now.Add(time.Hour)), stale: false},
+	}
+
+	for _, tt := range tests {
+		_, err := repos.Lockout.Increment(ctx, tt.key, tt.updatedAt, tt.updatedAt)
+		mustNoErr(t, err)
+
+		if tt.lock != nil {
+			mustNoErr(t, repos.Lockout.Lock(ctx, tt.key, *tt.lock))
+		}
+	}
+
+	deleted, err := repos.Lockout.DeleteStale(ctx, now.Add(-15*time.Minute))
+	mustNoErr(t, err)
+
+	if deleted != 1 {
+		t.Fatalf("got %d deleted attempts, want 1", deleted)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			_, err := repos.Lockout.GetByKey(ctx, tt.key)
+			if stale := errors.Is(err, pgx.ErrNoRows); stale != tt.stale {
+				t.Fatalf("got stale %v, want %v, error %v", stale, tt.stale, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	"github.com/tclutin/classflow-api/internal/repository"
+	"github.com/tclutin/classflow-api/internal/testutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Run(m))
+}
+
+// setup empties the shared database and returns repositories and fixtures on top of it
+func setup(t *testing.T) (context.Context, *repository.Repositories, *testutil.Fixtures) {
+	t.Helper()
+
+	pg := testutil.Database(t)
+	repos := repository.NewRepositories(pg.Pool, pg.Pool, testutil.Logger())
+
+	return context.Background(), repos, testutil.NewFixtures(repos)
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// faculty finds a seeded faculty by name
+func faculty(t *testing.T, repos *repository.Repositories, name string) edu.Faculty {
+	t.Helper()
+
+	faculties, err := repos.Edu.GetAllFaculty(context.Background())
+	mustNoErr(t, err)
+
+	for _, faculty := range faculties {
+		if faculty.Name == name {
+			return faculty
+		}
+	}
+
+	t.Fatalf("no seeded faculty %q", name)
+
+	return edu.Faculty{}
+}
+
+// date returns midnight UTC of the day, the way lesson dates and deadlines are stored
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
 	"log/slog"
 )
 
@@ -19,15 +21,21 @@ func NewMemberRepository(pool *pgxpool.Pool, logger *slog.Logger) *MemberReposit
 	}
 }
 
-func (m *MemberRepository) CreateTx(ctx context.Context, tx pgx.Tx, userID uint64, groupId uint64) (uint64, error) {
+func (m *MemberRepository) Create(ctx context.Context, userID uint64, groupId uint64) (uint64, error) {
+	ctx = postgresql.WithQueryName(ctx, "MemberRepository.Create")
+
 	sql := `INSERT INTO public.members (user_id, group_id) VALUES ($1, $2) RETURNING member_id`
 
-	row := tx.QueryRow(ctx, sql, userID, groupId)
+	row := postgresql.Conn(ctx, m.pool).QueryRow(ctx, sql, userID, groupId)
 
 	var memberID uint64
 
 	if err := row.Scan(&memberID); err != nil {
-		m.logger.Error("Failed to create member",
+		if postgresql.IsUniqueViolation(err) {
+			return 0, err
+		}
+
+		m.logger.ErrorContext(ctx, "Failed to create member",
 			"error", err,
 			"userID", userID,
 			"groupId", groupId,
@@ -38,33 +46,44 @@ func (m *MemberRepository) CreateTx(ctx context.Context, tx pgx.Tx, userID uint6
 	return memberID, nil
 }
 
-func (m *MemberRepository) DeleteTx(ctx context.Context, tx pgx.Tx, userId uint64) error {
+// Delete returns pgx.ErrNoRows when the user is not a member, e.g. after a concurrent leave
+func (m *MemberRepository) Delete(ctx context.Context, userId uint64) error {
+	ctx = postgresql.WithQueryName(ctx, "MemberRepository.Delete")
+
 	sql := `DELETE FROM public.members WHERE user_id = $1`
 
-	_, err := tx.Exec(ctx, sql, userId)
+	tag, err := postgresql.Conn(ctx, m.pool).Exec(ctx, sql, userId)
 
 	if err != nil {
-		m.logger.Error("Failed to delete member",
+		m.logger.ErrorContext(ctx, "Failed to delete member",
 			"error", err,
 			"user_id", userId,
 		)
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
 func (m *MemberRepository) GetGroupIdByUserId(ctx context.Context, userID uint64) (uint64, error) {
+	ctx = postgresql.WithQueryName(ctx, "MemberRepository.GetGroupIdByUserId")
+
 	sql := `SELECT group_id FROM public.members WHERE user_id = $1`
 
-	row := m.pool.QueryRow(ctx, sql, userID)
+	row := postgresql.Conn(ctx, m.pool).QueryRow(ctx, sql, userID)
 
 	var memberID uint64
 	if err := row.Scan(&memberID); err != nil {
-		m.logger.Error("Failed to get group ID for user",
-			"error", err,
-			"userID", userID,
-		)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			m.logger.ErrorContext(ctx, "Failed to get group ID for user",
+				"error", err,
+				"userID", userID,
+			)
+		}
 		return 0, err
 	}
 
@@ -0,0 +1,107 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"testing"
+)
+
+func TestMemberRepository(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	first, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	second, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	student, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name  string
+		run   func() error
+		check func(error) bool
+	}{
+		{
+			name: "join",
+			run: func() error {
+				_, err := repos.Member.Create(ctx, student.UserID, first.GroupID)
+				return err
+			},
+			check: func(err error) bool { return err == nil },
+		},
+		{
+			name: "join the same group again",
+			run: func() error {
+				_, err := repos.Member.Create(ctx, student.UserID, first.GroupID)
+				return err
+			},
+			check: postgresql.IsUniqueViolation,
+		},
+		{
+			name: "join another group",
+			run: func() error {
+				_, err := repos.Member.Create(ctx, student.UserID, second.GroupID)
+				return err
+			},
+			check: postgresql.IsUniqueViolation,
+		},
+		{
+			name: "group of the member",
+			run: func() error {
+				groupID, err := repos.Member.GetGroupIdByUserId(ctx, student.UserID)
+				if err == nil && groupID != first.GroupID {
+					return errors.New("wrong group")
+				}
+				return err
+			},
+			check: func(err error) bool { return err == nil },
+		},
+		{
+			name:  "leave",
+			run:   func() error { return repos.Member.Delete(ctx, student.UserID) },
+			check: func(err error) bool { return err == nil },
+		},
+		{
+			name:  "leave twice",
+			run:   func() error { return repos.Member.Delete(ctx, student.UserID) },
+			check: func(err error) bool { return errors.Is(err, pgx.ErrNoRows) },
+		},
+		{
+			name: "group of a former member",
+			run: func() error {
+				_, err := repos.Member.GetGroupIdByUserId(ctx, student.UserID)
+				return err
+			},
+			check: func(err error) bool { return errors.Is(err, pgx.ErrNoRows) },
+		},
+	}
+
+	// the steps share the member, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		if err := tt.run(); !tt.check(err) {
+			t.Fatalf("%s: unexpected result: %v", tt.name, err)
+		}
+	}
+}
+
+func TestMemberRepository_DeletedWithGroup(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	student, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	mustNoErr(t, fixtures.Member(ctx, student.UserID, created.GroupID))
+	mustNoErr(t, repos.Group.Delete(ctx, created.GroupID))
+
+	if _, err = repos.Member.GetGroupIdByUserId(ctx, student.UserID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows, got %v", err)
+	}
+}
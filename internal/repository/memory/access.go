@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"slices"
+)
+
+// AccessRepository starts without grants, add the ones a test relies on with AddGrants
+// the way the permission migrations insert them
+type AccessRepository struct {
+	store *Store
+}
+
+func NewAccessRepository(store *Store) *AccessRepository {
+	return &AccessRepository{
+		store: store,
+	}
+}
+
+func (a *AccessRepository) AddGrants(ctx context.Context, grants ...access.Grant) error {
+	return a.store.do(ctx, func(t *tables) error {
+		t.grants = append(t.grants, grants...)
+		return nil
+	})
+}
+
+func (a *AccessRepository) GetGrants(ctx context.Context, role string) ([]access.Grant, error) {
+	var grants []access.Grant
+
+	err := a.store.do(ctx, func(t *tables) error {
+		for _, grant := range t.grants {
+			if grant.Role == role {
+				grants = append(grants, grant)
+			}
+		}
+
+		return nil
+	})
+
+	return grants, err
+}
+
+// GetScopes adds the groups the user leads to the assigned scopes, like the UNION over groups.leader_id
+func (a *AccessRepository) GetScopes(ctx context.Context, userID uint64) ([]access.Scope, error) {
+	var scopes []access.Scope
+
+	err := a.store.do(ctx, func(t *tables) error {
+		scopes = slices.Clone(t.scopes[userID])
+
+		for _, groupID := range sortedKeys(t.groups) {
+			grp := t.groups[groupID]
+			if grp.LeaderID != nil && *grp.LeaderID == userID && !slices.Contains(scopes, access.Group(groupID)) {
+				scopes = append(scopes, access.Group(groupID))
+			}
+		}
+
+		return nil
+	})
+
+	return scopes, err
+}
+
+// CreateScope ignores a scope the user already has, like ON CONFLICT DO NOTHING
+func (a *AccessRepository) CreateScope(ctx context.Context, userID uint64, scope access.Scope) error {
+	return a.store.do(ctx, func(t *tables) error {
+		if !slices.Contains(t.scopes[userID], scope) {
+			t.scopes[userID] = append(t.scopes[userID], scope)
+		}
+
+		return nil
+	})
+}
+
+func (a *AccessRepository) DeleteScope(ctx context.Context, userID uint64, scope access.Scope) error {
+	return a.store.do(ctx, func(t *tables) error {
+		t.scopes[userID] = slices.DeleteFunc(t.scopes[userID], func(s access.Scope) bool {
+			return s == scope
+		})
+
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"slices"
+	"time"
+)
+
+type AnnouncementRepository struct {
+	store *Store
+}
+
+func NewAnnouncementRepository(store *Store) *AnnouncementRepository {
+	return &AnnouncementRepository{
+		store: store,
+	}
+}
+
+func (a *AnnouncementRepository) Create(ctx context.Context, entity announcement.Announcement) (uint64, error) {
+	err := a.store.do(ctx, func(t *tables) error {
+		entity.AnnouncementID = t.nextID()
+		t.posts[entity.AnnouncementID] = entity
+
+		return nil
+	})
+
+	return entity.AnnouncementID, err
+}
+
+func (a *AnnouncementRepository) Update(ctx context.Context, entity announcement.Announcement) error {
+	return a.store.do(ctx, func(t *tables) error {
+		if _, ok := t.posts[entity.AnnouncementID]; ok {
+			t.posts[entity.AnnouncementID] = entity
+		}
+
+		return nil
+	})
+}
+
+func (a *AnnouncementRepository) Delete(ctx context.Context, announcementID uint64) error {
+	return a.store.do(ctx, func(t *tables) error {
+		deletePost(t, announcementID)
+		return nil
+	})
+}
+
+func (a *AnnouncementRepository) GetById(ctx context.Context, announcementID uint64) (announcement.Announcement, error) {
+	return byId(ctx, a.store, func(t *tables) map[uint64]announcement.Announcement { return t.posts }, announcementID)
+}
+
+func (a *AnnouncementRepository) GetAllByGroupId(ctx context.Context, groupID uint64, userID uint64, filter announcement.FilterDTO) ([]announcement.DetailsAnnouncementDTO, int, error) {
+	var matched []announcement.DetailsAnnouncementDTO
+
+	err := a.store.do(ctx, func(t *tables) error {
+		for _, post := range t.posts {
+			if post.GroupID != groupID || (!filter.IncludeExpired && post.IsExpired(filter.Now)) {
+				continue
+			}
+
+			details := announcement.DetailsAnnouncementDTO{Announcement: post}
+
+			for key := range t.reads {
+				if key.ID == post.AnnouncementID {
+					details.ReadCount++
+					details.Read = details.Read || key.UserID == userID
+				}
+			}
+
+			matched = append(matched, details)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	slices.SortFunc(matched, func(x, y announcement.DetailsAnnouncementDTO) int {
+		if x.Pinned != y.Pinned {
+			if x.Pinned {
+				return -1
+			}
+			return 1
+		}
+
+		if c := y.CreatedAt.Compare(x.CreatedAt); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(y.AnnouncementID, x.AnnouncementID)
+	})
+
+	total := len(matched)
+	start := min(filter.Offset, total)
+	end := min(start+filter.Limit, total)
+
+	return matched[start:end], total, nil
+}
+
+func (a *AnnouncementRepository) MarkRead(ctx context.Context, announcementID uint64, userID uint64, readAt time.Time) error {
+	return a.store.do(ctx, func(t *tables) error {
+		if _, ok := t.posts[announcementID]; !ok {
+			return pgx.ErrNoRows
+		}
+
+		key := mark{ID: announcementID, UserID: userID}
+		if _, ok := t.reads[key]; !ok {
+			t.reads[key] = readAt
+		}
+
+		return nil
+	})
+}
+
+// deletePost removes the announcement together with its read receipts
+func deletePost(t *tables, announcementID uint64) {
+	delete(t.posts, announcementID)
+
+	for key := range t.reads {
+		if key.ID == announcementID {
+			delete(t.reads, key)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"slices"
+	"time"
+)
+
+type AttendanceRepository struct {
+	store *Store
+}
+
+func NewAttendanceRepository(store *Store) *AttendanceRepository {
+	return &AttendanceRepository{
+		store: store,
+	}
+}
+
+func (a *AttendanceRepository) Upsert(ctx context.Context, records []attendance.Record) error {
+	return a.store.do(ctx, func(t *tables) error {
+		for _, record := range records {
+			existing, ok := findRecord(t, record.ScheduleID, record.LessonDate, record.UserID)
+			if !ok {
+				record.RecordID = t.nextID()
+				t.attendance[record.RecordID] = record
+				continue
+			}
+
+			existing.Status = record.Status
+			existing.Source = record.Source
+			existing.MarkedBy = record.MarkedBy
+			existing.UpdatedAt = record.UpdatedAt
+			t.attendance[existing.RecordID] = existing
+		}
+
+		return nil
+	})
+}
+
+func (a *AttendanceRepository) GetSheet(ctx context.Context, groupID, scheduleID uint64, lessonDate time.Time) ([]attendance.SheetEntryDTO, error) {
+	var sheet []attendance.SheetEntryDTO
+
+	err := a.store.do(ctx, func(t *tables) error {
+		for _, m := range t.members {
+			if m.GroupID != groupID {
+				continue
+			}
+
+			u, ok := t.users[m.UserID]
+			if !ok {
+				continue
+			}
+
+			entry := attendance.SheetEntryDTO{
+				UserID:   m.UserID,
+				FullName: u.FullName,
+				Email:    u.Email,
+			}
+
+			if record, ok := findRecord(t, scheduleID, lessonDate, m.UserID); ok {
+				entry.Status = &record.Status
+			}
+
+			sheet = append(sheet, entry)
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(sheet, func(x, y attendance.SheetEntryDTO) int {
+		return cmp.Or(compareNames(x.FullName, y.FullName), cmp.Compare(x.UserID, y.UserID))
+	})
+
+	return sheet, err
+}
+
+// GetAllByUserId skips records whose lesson is missing, like the inner join of the postgres repository
+func (a *AttendanceRepository) GetAllByUserId(ctx context.Context, userID uint64, filter attendance.FilterDTO) ([]attendance.DetailsRecordDTO, error) {
+	var records []attendance.DetailsRecordDTO
+
+	err := a.store.do(ctx, func(t *tables) error {
+		for _, record := range t.attendance {
+			if record.UserID != userID {
+				continue
+			}
+
+			lesson, ok := lessonById(t, record.ScheduleID)
+			if !ok || !matchRecord(filter, record, lesson) {
+				continue
+			}
+
+			records = append(records, attendance.DetailsRecordDTO{
+				Record:      record,
+				SubjectName: lesson.SubjectName,
+				StartTime:   lesson.StartTime,
+			})
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(records, func(x, y attendance.DetailsRecordDTO) int {
+		return cmp.Or(x.LessonDate.Compare(y.LessonDate), cmp.Compare(x.StartTime, y.StartTime))
+	})
+
+	return records, err
+}
+
+func (a *AttendanceRepository) GetSummary(ctx context.Context, groupID uint64, filter attendance.FilterDTO) ([]attendance.SummaryDTO, error) {
+	type key struct {
+		UserID      uint64
+		SubjectName string
+	}
+
+	counts := make(map[key]*attendance.SummaryDTO)
+
+	err := a.store.do(ctx, func(t *tables) error {
+		for _, record := range t.attendance {
+			if record.GroupID != groupID {
+				continue
+			}
+
+			lesson, ok := lessonById(t, record.ScheduleID)
+			if !ok || !matchRecord(filter, record, lesson) {
+				continue
+			}
+
+			u, ok := t.users[record.UserID]
+			if !ok {
+				continue
+			}
+
+			k := key{UserID: record.UserID, SubjectName: lesson.SubjectName}
+
+			item, ok := counts[k]
+			if !ok {
+				item = &attendance.SummaryDTO{
+					UserID:      u.UserID,
+					FullName:    u.FullName,
+					Email:       u.Email,
+					SubjectName: lesson.SubjectName,
+				}
+				counts[k] = item
+			}
+
+			switch record.Status {
+			case attendance.Present:
+				item.Present++
+			case attendance.Absent:
+				item.Absent++
+			case attendance.Excused:
+				item.Excused++
+			}
+		}
+
+		return nil
+	})
+
+	var summary []attendance.SummaryDTO
+
+	for _, item := range counts {
+		summary = append(summary, *item)
+	}
+
+	slices.SortFunc(summary, func(x, y attendance.SummaryDTO) int {
+		return cmp.Or(
+			compareNames(x.FullName, y.FullName),
+			cmp.Compare(x.UserID, y.UserID),
+			cmp.Compare(x.SubjectName, y.SubjectName))
+	})
+
+	return summary, err
+}
+
+func (a *AttendanceRepository) CheckIn(ctx context.Context, record attendance.Record) error {
+	return a.store.do(ctx, func(t *tables) error {
+		if _, ok := findRecord(t, record.ScheduleID, record.LessonDate, record.UserID); ok {
+			return nil
+		}
+
+		record.RecordID = t.nextID()
+		t.attendance[record.RecordID] = record
+
+		return nil
+	})
+}
+
+func (a *AttendanceRepository) OpenWindow(ctx context.Context, window attendance.Window) (uint64, error) {
+	err := a.store.do(ctx, func(t *tables) error {
+		for _, existing := range t.windows {
+			if existing.ScheduleID == window.ScheduleID && existing.LessonDate.Equal(window.LessonDate) {
+				window.WindowID = existing.WindowID
+				window.CreatedAt = existing.CreatedAt
+				t.windows[window.WindowID] = window
+
+				return nil
+			}
+		}
+
+		window.WindowID = t.nextID()
+		t.windows[window.WindowID] = window
+
+		return nil
+	})
+
+	return window.WindowID, err
+}
+
+func (a *AttendanceRepository) CloseWindow(ctx context.Context, windowID uint64, closesAt time.Time) error {
+	return a.store.do(ctx, func(t *tables) error {
+		if window, ok := t.windows[windowID]; ok {
+			window.ClosesAt = closesAt
+			t.windows[windowID] = window
+		}
+
+		return nil
+	})
+}
+
+func (a *AttendanceRepository) GetWindowById(ctx context.Context, windowID uint64) (attendance.Window, error) {
+	return byId(ctx, a.store, func(t *tables) map[uint64]attendance.Window { return t.windows }, windowID)
+}
+
+func (a *AttendanceRepository) GetOpenWindowsByGroupId(ctx context.Context, groupID uint64, at time.Time) ([]attendance.Window, error) {
+	return all(ctx, a.store, func(t *tables) map[uint64]attendance.Window { return t.windows }, func(window attendance.Window) bool {
+		return window.GroupID == groupID && window.IsOpen(at)
+	})
+}
+
+func findRecord(t *tables, scheduleID uint64, lessonDate time.Time, userID uint64) (attendance.Record, bool) {
+	for _, record := range t.attendance {
+		if record.ScheduleID == scheduleID && record.UserID == userID && record.LessonDate.Equal(lessonDate) {
+			return record, true
+		}
+	}
+
+	return attendance.Record{}, false
+}
+
+func matchRecord(filter attendance.FilterDTO, record attendance.Record, lesson schedule.Schedule) bool {
+	if filter.From != nil && record.LessonDate.Before(*filter.From) {
+		return false
+	}
+
+	if filter.To != nil && record.LessonDate.After(*filter.To) {
+		return false
+	}
+
+	return filter.SubjectName == "" || filter.SubjectName == lesson.SubjectName
+}
+
+func lessonById(t *tables, scheduleID uint64) (schedule.Schedule, bool) {
+	for _, value := range t.schedules {
+		if value.ScheduleID == scheduleID {
+			return value, true
+		}
+	}
+
+	return schedule.Schedule{}, false
+}
+
+// compareNames orders missing names last, like NULL values in an ascending postgres sort
+func compareNames(x, y *string) int {
+	switch {
+	case x == nil && y == nil:
+		return 0
+	case x == nil:
+		return 1
+	case y == nil:
+		return -1
+	}
+
+	return cmp.Compare(*x, *y)
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"slices"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{
+		store: store,
+	}
+}
+
+func (a *AuditRepository) Create(ctx context.Context, record audit.Record) error {
+	return a.store.do(ctx, func(t *tables) error {
+		record.RecordID = t.nextID()
+		t.auditLog = append(t.auditLog, record)
+
+		return nil
+	})
+}
+
+// GetAll returns the newest records first
+func (a *AuditRepository) GetAll(ctx context.Context, filter audit.FilterDTO) ([]audit.Record, error) {
+	var records []audit.Record
+
+	err := a.store.do(ctx, func(t *tables) error {
+		for _, record := range slices.Backward(t.auditLog) {
+			switch {
+			case filter.ActorID != nil && (record.ActorID == nil || *record.ActorID != *filter.ActorID),
+				filter.TargetType != "" && record.TargetType != filter.TargetType,
+				filter.TargetID != nil && record.TargetID != *filter.TargetID,
+				filter.From != nil && record.CreatedAt.Before(*filter.From),
+				filter.To != nil && !record.CreatedAt.Before(*filter.To):
+				continue
+			}
+
+			records = append(records, record)
+
+			if filter.Limit > 0 && len(records) == filter.Limit {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return records, err
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+)
+
+// EduRepository serves reference data, it starts empty, fill it with the Add methods
+// the way seeds fill the database
+type EduRepository struct {
+	store *Store
+}
+
+func NewEduRepository(store *Store) *EduRepository {
+	return &EduRepository{
+		store: store,
+	}
+}
+
+func (e *EduRepository) AddFaculty(ctx context.Context, name string) (edu.Faculty, error) {
+	var faculty edu.Faculty
+
+	err := e.store.do(ctx, func(t *tables) error {
+		faculty = edu.Faculty{FacultyID: t.nextID(), Name: name}
+		t.faculties[faculty.FacultyID] = faculty
+
+		return nil
+	})
+
+	return faculty, err
+}
+
+func (e *EduRepository) AddProgram(ctx context.Context, facultyID uint64, name string) (edu.Program, error) {
+	var program edu.Program
+
+	err := e.store.do(ctx, func(t *tables) error {
+		program = edu.Program{ProgramID: t.nextID(), FacultyID: facultyID, Name: name}
+		t.programs[program.ProgramID] = program
+
+		return nil
+	})
+
+	return program, err
+}
+
+func (e *EduRepository) AddTypeOfSubject(ctx context.Context, name string) (edu.TypeOfSubject, error) {
+	var typeOfSubject edu.TypeOfSubject
+
+	err := e.store.do(ctx, func(t *tables) error {
+		typeOfSubject = edu.TypeOfSubject{TypeOfSubjectID: t.nextID(), Name: name}
+		t.types[typeOfSubject.TypeOfSubjectID] = typeOfSubject
+
+		return nil
+	})
+
+	return typeOfSubject, err
+}
+
+func (e *EduRepository) AddBuilding(ctx context.Context, building edu.Building) (edu.Building, error) {
+	err := e.store.do(ctx, func(t *tables) error {
+		building.BuildingID = t.nextID()
+		t.buildings[building.BuildingID] = building
+
+		return nil
+	})
+
+	return building, err
+}
+
+func (e *EduRepository) GetAllFaculty(ctx context.Context) ([]edu.Faculty, error) {
+	return all(ctx, e.store, func(t *tables) map[uint64]edu.Faculty { return t.faculties }, nil)
+}
+
+func (e *EduRepository) GetAllProgramsByFacultyId(ctx context.Context, facultyID uint64) ([]edu.Program, error) {
+	return all(ctx, e.store, func(t *tables) map[uint64]edu.Program { return t.programs }, func(program edu.Program) bool {
+		return program.FacultyID == facultyID
+	})
+}
+
+func (e *EduRepository) GetAllTypesOfSubject(ctx context.Context) ([]edu.TypeOfSubject, error) {
+	return all(ctx, e.store, func(t *tables) map[uint64]edu.TypeOfSubject { return t.types }, nil)
+}
+
+func (e *EduRepository) GetAllBuildings(ctx context.Context) ([]edu.Building, error) {
+	return all(ctx, e.store, func(t *tables) map[uint64]edu.Building { return t.buildings }, nil)
+}
+
+func (e *EduRepository) GetBuildingById(ctx context.Context, buildingID uint64) (edu.Building, error) {
+	return byId(ctx, e.store, func(t *tables) map[uint64]edu.Building { return t.buildings }, buildingID)
+}
+
+func (e *EduRepository) GetTypeOfSubjectById(ctx context.Context, typeOfSubjectId uint64) (edu.TypeOfSubject, error) {
+	return byId(ctx, e.store, func(t *tables) map[uint64]edu.TypeOfSubject { return t.types }, typeOfSubjectId)
+}
+
+func (e *EduRepository) GetProgramById(ctx context.Context, programID uint64) (edu.Program, error) {
+	return byId(ctx, e.store, func(t *tables) map[uint64]edu.Program { return t.programs }, programID)
+}
+
+func (e *EduRepository) GetFacultyById(ctx context.Context, facultyID uint64) (edu.Faculty, error) {
+	return byId(ctx, e.store, func(t *tables) map[uint64]edu.Faculty { return t.faculties }, facultyID)
+}
+
+// all returns the rows of a table in id order, match is optional
+func all[V any](ctx context.Context, store *Store, table func(t *tables) map[uint64]V, match func(V) bool) ([]V, error) {
+	var values []V
+
+	err := store.do(ctx, func(t *tables) error {
+		rows := table(t)
+
+		for _, id := range sortedKeys(rows) {
+			if match == nil || match(rows[id]) {
+				values = append(values, rows[id])
+			}
+		}
+
+		return nil
+	})
+
+	return values, err
+}
+
+func byId[V any](ctx context.Context, store *Store, table func(t *tables) map[uint64]V, id uint64) (V, error) {
+	var value V
+
+	err := store.do(ctx, func(t *tables) error {
+		row, ok := table(t)[id]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+
+		value = row
+
+		return nil
+	})
+
+	return value, err
+}
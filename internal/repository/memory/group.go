@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"slices"
+	"strings"
+)
+
+// checkViolation is the postgres error code of a failed CHECK constraint
+const checkViolation = "23514"
+
+type GroupRepository struct {
+	store *Store
+}
+
+func NewGroupRepository(store *Store) *GroupRepository {
+	return &GroupRepository{
+		store: store,
+	}
+}
+
+func (g *GroupRepository) Create(ctx context.Context, entity group.Group) (uint64, error) {
+	var groupID uint64
+
+	err := g.store.do(ctx, func(t *tables) error {
+		if shortNameTaken(t, entity.ShortName, 0) {
+			return uniqueError("groups_short_name_key")
+		}
+
+		groupID = t.nextID()
+		entity.GroupID = groupID
+		t.groups[groupID] = entity
+
+		return nil
+	})
+
+	return groupID, err
+}
+
+// Update keeps the number of people, it is changed by AddMembers only
+func (g *GroupRepository) Update(ctx context.Context, entity group.Group) error {
+	return g.store.do(ctx, func(t *tables) error {
+		current, ok := t.groups[entity.GroupID]
+		if !ok {
+			return nil
+		}
+
+		if shortNameTaken(t, entity.ShortName, entity.GroupID) {
+			return uniqueError("groups_short_name_key")
+		}
+
+		entity.NumberOfPeople = current.NumberOfPeople
+		t.groups[entity.GroupID] = entity
+
+		return nil
+	})
+}
+
+func (g *GroupRepository) AddMembers(ctx context.Context, groupID uint64, delta int) (int, error) {
+	var numberOfPeople int
+
+	err := g.store.do(ctx, func(t *tables) error {
+		entity, ok := t.groups[groupID]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+
+		if entity.NumberOfPeople+delta < 0 {
+			return &pgconn.PgError{Code: checkViolation, ConstraintName: "groups_number_of_people_check"}
+		}
+
+		entity.NumberOfPeople += delta
+		t.groups[groupID] = entity
+		numberOfPeople = entity.NumberOfPeople
+
+		return nil
+	})
+
+	return numberOfPeople, err
+}
+
+// Delete removes the members, the waitlist and the schedule of the group like the cascading foreign keys
+func (g *GroupRepository) Delete(ctx context.Context, groupID uint64) error {
+	return g.store.do(ctx, func(t *tables) error {
+		delete(t.groups, groupID)
+
+		for memberID, m := range t.members {
+			if m.GroupID == groupID {
+				delete(t.members, memberID)
+			}
+		}
+
+		for waitlistID, entry := range t.waitlist {
+			if entry.GroupID == groupID {
+				delete(t.waitlist, waitlistID)
+			}
+		}
+
+		t.schedules = slices.DeleteFunc(t.schedules, func(s schedule.Schedule) bool {
+			return s.GroupID == groupID
+		})
+
+		for announcementID, post := range t.posts {
+			if post.GroupID == groupID {
+				deletePost(t, announcementID)
+			}
+		}
+
+		for examID, exam := range t.exams {
+			if exam.GroupID == groupID {
+				delete(t.exams, examID)
+			}
+		}
+
+		for homeworkID, entity := range t.homework {
+			if entity.GroupID == groupID {
+				deleteHomework(t, homeworkID)
+			}
+		}
+
+		for recordID, record := range t.attendance {
+			if record.GroupID == groupID {
+				delete(t.attendance, recordID)
+			}
+		}
+
+		for windowID, window := range t.windows {
+			if window.GroupID == groupID {
+				delete(t.windows, windowID)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (g *GroupRepository) GetById(ctx context.Context, groupID uint64) (group.Group, error) {
+	return byId(ctx, g.store, func(t *tables) map[uint64]group.Group { return t.groups }, groupID)
+}
+
+// GetByIdForUpdate needs no row lock, transactions of the store are serialized
+func (g *GroupRepository) GetByIdForUpdate(ctx context.Context, groupID uint64) (group.Group, error) {
+	return g.GetById(ctx, groupID)
+}
+
+func (g *GroupRepository) GetByShortName(ctx context.Context, shortname string) (group.Group, error) {
+	var found group.Group
+
+	err := g.store.do(ctx, func(t *tables) error {
+		for _, entity := range t.groups {
+			if entity.ShortName == shortname {
+				found = entity
+				return nil
+			}
+		}
+
+		return pgx.ErrNoRows
+	})
+
+	return found, err
+}
+
+func (g *GroupRepository) GetDetailsGroupById(ctx context.Context, groupID uint64) (group.DetailsGroupDTO, error) {
+	var details group.DetailsGroupDTO
+
+	err := g.store.do(ctx, func(t *tables) error {
+		entity, ok := t.groups[groupID]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+
+		faculty, ok := t.faculties[entity.FacultyID]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+
+		program, ok := t.programs[entity.ProgramID]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+
+		details = group.DetailsGroupDTO{
+			GroupID:        entity.GroupID,
+			LeaderID:       entity.LeaderID,
+			Faculty:        faculty.Name,
+			Program:        program.Name,
+			ShortName:      entity.ShortName,
+			NumberOfPeople: entity.NumberOfPeople,
+			Capacity:       entity.Capacity,
+			ExistsSchedule: entity.ExistsSchedule,
+			CreatedAt:      entity.CreatedAt,
+		}
+
+		return nil
+	})
+
+	return details, err
+}
+
+// GetSummaryGroups matches search as a case-insensitive substring, the trigram similarity
+// of the postgres repository is not reproduced
+func (g *GroupRepository) GetSummaryGroups(ctx context.Context, filter group.FilterDTO) ([]group.SummaryGroupDTO, int, error) {
+	var (
+		groups []group.SummaryGroupDTO
+		total  int
+	)
+
+	err := g.store.do(ctx, func(t *tables) error {
+		var matched []group.SummaryGroupDTO
+
+		for _, entity := range t.groups {
+			faculty, ok := t.faculties[entity.FacultyID]
+			if !ok {
+				continue
+			}
+
+			program, ok := t.programs[entity.ProgramID]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case filter.Faculty != "" && faculty.Name != filter.Faculty,
+				filter.Program != "" && program.Name != filter.Program,
+				filter.FacultyIDs != nil && !slices.Contains(filter.FacultyIDs, entity.FacultyID),
+				filter.ProgramIDs != nil && !slices.Contains(filter.ProgramIDs, entity.ProgramID),
+				filter.AllowedFacultyIDs != nil && !slices.Contains(filter.AllowedFacultyIDs, entity.FacultyID),
+				filter.ExistsSchedule != nil && entity.ExistsSchedule != *filter.ExistsSchedule,
+				filter.Search != "" && !strings.Contains(strings.ToLower(entity.ShortName), strings.ToLower(filter.Search)):
+				continue
+			}
+
+			matched = append(matched, group.SummaryGroupDTO{
+				GroupID:        entity.GroupID,
+				Faculty:        faculty.Name,
+				Program:        program.Name,
+				ShortName:      entity.ShortName,
+				NumberOfPeople: entity.NumberOfPeople,
+				Capacity:       entity.Capacity,
+				ExistsSchedule: entity.ExistsSchedule,
+				CreatedAt:      entity.CreatedAt,
+			})
+		}
+
+		slices.SortFunc(matched, func(a, b group.SummaryGroupDTO) int {
+			order := cmp.Or(compareBy(filter.SortBy, a, b), cmp.Compare(a.GroupID, b.GroupID))
+			if filter.Desc {
+				return -order
+			}
+			return order
+		})
+
+		total = len(matched)
+
+		start := min(max(filter.Offset, 0), total)
+		end := total
+		if filter.Limit > 0 {
+			end = min(start+filter.Limit, total)
+		}
+
+		if start < end {
+			groups = matched[start:end]
+		}
+
+		return nil
+	})
+
+	return groups, total, err
+}
+
+func compareBy(sortBy string, a, b group.SummaryGroupDTO) int {
+	switch sortBy {
+	case group.SortBySize:
+		return cmp.Compare(a.NumberOfPeople, b.NumberOfPeople)
+	case group.SortByCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	default:
+		return cmp.Compare(a.ShortName, b.ShortName)
+	}
+}
+
+func shortNameTaken(t *tables, shortName string, exceptGroupID uint64) bool {
+	for groupID, entity := range t.groups {
+		if groupID != exceptGroupID && entity.ShortName == shortName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"slices"
+	"time"
+)
+
+type HomeworkRepository struct {
+	store *Store
+}
+
+func NewHomeworkRepository(store *Store) *HomeworkRepository {
+	return &HomeworkRepository{
+		store: store,
+	}
+}
+
+func (h *HomeworkRepository) Create(ctx context.Context, entity homework.Homework) (uint64, error) {
+	err := h.store.do(ctx, func(t *tables) error {
+		entity.HomeworkID = t.nextID()
+		t.homework[entity.HomeworkID] = entity
+
+		return nil
+	})
+
+	return entity.HomeworkID, err
+}
+
+func (h *HomeworkRepository) Update(ctx context.Context, entity homework.Homework) error {
+	return h.store.do(ctx, func(t *tables) error {
+		if _, ok := t.homework[entity.HomeworkID]; ok {
+			t.homework[entity.HomeworkID] = entity
+		}
+
+		return nil
+	})
+}
+
+func (h *HomeworkRepository) Delete(ctx context.Context, homeworkID uint64) error {
+	return h.store.do(ctx, func(t *tables) error {
+		deleteHomework(t, homeworkID)
+		return nil
+	})
+}
+
+func (h *HomeworkRepository) GetById(ctx context.Context, homeworkID uint64) (homework.Homework, error) {
+	return byId(ctx, h.store, func(t *tables) map[uint64]homework.Homework { return t.homework }, homeworkID)
+}
+
+func (h *HomeworkRepository) GetAllByGroupId(ctx context.Context, groupID uint64, userID uint64, filter homework.FilterDTO) ([]homework.DetailsHomeworkDTO, error) {
+	var items []homework.DetailsHomeworkDTO
+
+	err := h.store.do(ctx, func(t *tables) error {
+		for _, entity := range t.homework {
+			if entity.GroupID != groupID || entity.DueDate.Before(filter.From) || entity.DueDate.After(filter.To) {
+				continue
+			}
+
+			_, done := t.done[mark{ID: entity.HomeworkID, UserID: userID}]
+
+			items = append(items, homework.DetailsHomeworkDTO{Homework: entity, Done: done})
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(items, func(x, y homework.DetailsHomeworkDTO) int {
+		return cmp.Or(x.DueDate.Compare(y.DueDate), cmp.Compare(x.HomeworkID, y.HomeworkID))
+	})
+
+	return items, err
+}
+
+func (h *HomeworkRepository) MarkDone(ctx context.Context, homeworkID uint64, userID uint64, doneAt time.Time) error {
+	return h.store.do(ctx, func(t *tables) error {
+		if _, ok := t.homework[homeworkID]; !ok {
+			return pgx.ErrNoRows
+		}
+
+		key := mark{ID: homeworkID, UserID: userID}
+		if _, ok := t.done[key]; !ok {
+			t.done[key] = doneAt
+		}
+
+		return nil
+	})
+}
+
+func (h *HomeworkRepository) UnmarkDone(ctx context.Context, homeworkID uint64, userID uint64) error {
+	return h.store.do(ctx, func(t *tables) error {
+		delete(t.done, mark{ID: homeworkID, UserID: userID})
+		return nil
+	})
+}
+
+// deleteHomework removes the homework together with its done marks
+func deleteHomework(t *tables, homeworkID uint64) {
+	delete(t.homework, homeworkID)
+
+	for key := range t.done {
+		if key.ID == homeworkID {
+			delete(t.done, key)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+)
+
+type MemberRepository struct {
+	store *Store
+}
+
+func NewMemberRepository(store *Store) *MemberRepository {
+	return &MemberRepository{
+		store: store,
+	}
+}
+
+func (m *MemberRepository) Create(ctx context.Context, userID uint64, groupId uint64) (uint64, error) {
+	var memberID uint64
+
+	err := m.store.do(ctx, func(t *tables) error {
+		if _, ok := memberByUser(t, userID); ok {
+			return uniqueError("members_user_id_key")
+		}
+
+		memberID = t.nextID()
+		t.members[memberID] = member{
+			MemberID: memberID,
+			UserID:   userID,
+			GroupID:  groupId,
+		}
+
+		return nil
+	})
+
+	return memberID, err
+}
+
+func (m *MemberRepository) Delete(ctx context.Context, userId uint64) error {
+	return m.store.do(ctx, func(t *tables) error {
+		found, ok := memberByUser(t, userId)
+		if !ok {
+			return pgx.ErrNoRows
+		}
+
+		delete(t.members, found.MemberID)
+
+		return nil
+	})
+}
+
+func (m *MemberRepository) GetGroupIdByUserId(ctx context.Context, userID uint64) (uint64, error) {
+	var groupID uint64
+
+	err := m.store.do(ctx, func(t *tables) error {
+		found, ok := memberByUser(t, userID)
+		if !ok {
+			return pgx.ErrNoRows
+		}
+
+		groupID = found.GroupID
+
+		return nil
+	})
+
+	return groupID, err
+}
+
+func memberByUser(t *tables, userID uint64) (member, bool) {
+	for _, m := range t.members {
+		if m.UserID == userID {
+			return m, true
+		}
+	}
+
+	return member{}, false
+}
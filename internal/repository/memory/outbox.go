@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
+	"slices"
+	"time"
+)
+
+type OutboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{
+		store: store,
+	}
+}
+
+func (o *OutboxRepository) Create(ctx context.Context, event outbox.Event) error {
+	return o.store.do(ctx, func(t *tables) error {
+		event.EventID = t.nextID()
+		t.outboxQueue = append(t.outboxQueue, event)
+
+		return nil
+	})
+}
+
+func (o *OutboxRepository) GetPending(ctx context.Context, limit int) ([]outbox.Event, error) {
+	var events []outbox.Event
+
+	err := o.store.do(ctx, func(t *tables) error {
+		for _, event := range t.outboxQueue {
+			if event.ProcessedAt != nil {
+				continue
+			}
+
+			events = append(events, event)
+
+			if len(events) == limit {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return events, err
+}
+
+func (o *OutboxRepository) MarkProcessed(ctx context.Context, eventIDs []uint64, processedAt time.Time) error {
+	return o.store.do(ctx, func(t *tables) error {
+		for i, event := range t.outboxQueue {
+			if event.ProcessedAt == nil && slices.Contains(eventIDs, event.EventID) {
+				t.outboxQueue[i].ProcessedAt = &processedAt
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,39 @@
+package memory
+
+import "github.com/tclutin/classflow-api/internal/repository"
+
+// Repositories are the in-memory counterparts of repository.Repositories sharing one store,
+// pass them to the service constructors together with NewTxManager of the same store
+type Repositories struct {
+	User         *UserRepository
+	Group        *GroupRepository
+	Edu          *EduRepository
+	Member       *MemberRepository
+	Waitlist     *WaitlistRepository
+	Schedule     *ScheduleRepository
+	Lockout      *repository.MemoryLockoutRepository
+	Access       *AccessRepository
+	Audit        *AuditRepository
+	Outbox       *OutboxRepository
+	Announcement *AnnouncementRepository
+	Homework     *HomeworkRepository
+	Attendance   *AttendanceRepository
+}
+
+func NewRepositories(store *Store) *Repositories {
+	return &Repositories{
+		User:         NewUserRepository(store),
+		Group:        NewGroupRepository(store),
+		Edu:          NewEduRepository(store),
+		Member:       NewMemberRepository(store),
+		Waitlist:     NewWaitlistRepository(store),
+		Schedule:     NewScheduleRepository(store),
+		Lockout:      repository.NewMemoryLockoutRepository(),
+		Access:       NewAccessRepository(store),
+		Audit:        NewAuditRepository(store),
+		Outbox:       NewOutboxRepository(store),
+		Announcement: NewAnnouncementRepository(store),
+		Homework:     NewHomeworkRepository(store),
+		Attendance:   NewAttendanceRepository(store),
+	}
+}
@@ -0,0 +1,105 @@
+package memory_test
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	"github.com/tclutin/classflow-api/internal/repository/contract"
+	"github.com/tclutin/classflow-api/internal/repository/memory"
+	"testing"
+)
+
+// setup returns repositories of a fresh store holding the reference data seeds put in the database
+func setup(t *testing.T) (*memory.Store, *memory.Repositories, contract.ContentDeps) {
+	t.Helper()
+
+	ctx := context.Background()
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+
+	faculty, err := repos.Edu.AddFaculty(ctx, "Институт информационных технологий")
+	mustNoErr(t, err)
+
+	program, err := repos.Edu.AddProgram(ctx, faculty.FacultyID, "Программная инженерия")
+	mustNoErr(t, err)
+
+	_, err = repos.Edu.AddProgram(ctx, faculty.FacultyID, "Прикладная математика")
+	mustNoErr(t, err)
+
+	typeOfSubject, err := repos.Edu.AddTypeOfSubject(ctx, "Лекция")
+	mustNoErr(t, err)
+
+	building, err := repos.Edu.AddBuilding(ctx, edu.Building{
+		Name:      "Главный корпус",
+		Latitude:  55.75,
+		Longitude: 37.61,
+		Address:   "ул. Ленина, 1",
+	})
+	mustNoErr(t, err)
+
+	deps := contract.ContentDeps{
+		GroupDeps: contract.GroupDeps{
+			Groups:    repos.Group,
+			Members:   repos.Member,
+			Waitlist:  repos.Waitlist,
+			Users:     repos.User,
+			FacultyID: faculty.FacultyID,
+			ProgramID: program.ProgramID,
+		},
+		Schedules:       repos.Schedule,
+		Announcements:   repos.Announcement,
+		Homework:        repos.Homework,
+		Attendance:      repos.Attendance,
+		BuildingID:      building.BuildingID,
+		TypeOfSubjectID: typeOfSubject.TypeOfSubjectID,
+	}
+
+	return store, repos, deps
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUserRepository(t *testing.T) {
+	_, repos, _ := setup(t)
+	contract.UserRepository(t, repos.User)
+}
+
+func TestGroupRepository(t *testing.T) {
+	_, _, deps := setup(t)
+	contract.GroupRepository(t, deps.GroupDeps)
+}
+
+func TestTransactions(t *testing.T) {
+	store, repos, _ := setup(t)
+	contract.Transactions(t, memory.NewTxManager(store), repos.User)
+}
+
+func TestEduRepository(t *testing.T) {
+	_, repos, _ := setup(t)
+	contract.EduRepository(t, repos.Edu)
+}
+
+func TestScheduleRepository(t *testing.T) {
+	_, _, deps := setup(t)
+	contract.ScheduleRepository(t, deps)
+}
+
+func TestAnnouncementRepository(t *testing.T) {
+	_, _, deps := setup(t)
+	contract.AnnouncementRepository(t, deps)
+}
+
+func TestHomeworkRepository(t *testing.T) {
+	_, _, deps := setup(t)
+	contract.HomeworkRepository(t, deps)
+}
+
+func TestAttendanceRepository(t *testing.T) {
+	_, _, deps := setup(t)
+	contract.AttendanceRepository(t, deps)
+}
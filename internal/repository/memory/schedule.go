@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"slices"
+)
+
+type ScheduleRepository struct {
+	store *Store
+}
+
+func NewScheduleRepository(store *Store) *ScheduleRepository {
+	return &ScheduleRepository{
+		store: store,
+	}
+}
+
+func (s *ScheduleRepository) Create(ctx context.Context, schedules []schedule.Schedule) error {
+	return s.store.do(ctx, func(t *tables) error {
+		for _, value := range schedules {
+			value.ScheduleID = t.nextID()
+			t.schedules = append(t.schedules, value)
+		}
+
+		return nil
+	})
+}
+
+func (s *ScheduleRepository) GetById(ctx context.Context, scheduleID uint64) (schedule.Schedule, error) {
+	var lesson schedule.Schedule
+
+	err := s.store.do(ctx, func(t *tables) error {
+		for _, value := range t.schedules {
+			if value.ScheduleID == scheduleID {
+				lesson = value
+				return nil
+			}
+		}
+
+		return pgx.ErrNoRows
+	})
+
+	return lesson, err
+}
+
+// GetSchedulesByGroupId skips lessons whose type or building is missing, like the inner joins
+// of the postgres repository
+func (s *ScheduleRepository) GetSchedulesByGroupId(ctx context.Context, filter schedule.FilterDTO, groupID uint64) ([]schedule.DetailsScheduleDTO, error) {
+	var schedules []schedule.DetailsScheduleDTO
+
+	err := s.store.do(ctx, func(t *tables) error {
+		for _, value := range t.schedules {
+			if value.GroupID != groupID {
+				continue
+			}
+
+			if !value.EveryWeek && (filter.IsEven == "true" && !value.IsEven || filter.IsEven == "false" && value.IsEven) {
+				continue
+			}
+
+			typeOfSubject, ok := t.types[value.TypeOfSubjectID]
+			if !ok {
+				continue
+			}
+
+			building, ok := t.buildings[value.BuildingsID]
+			if !ok {
+				continue
+			}
+
+			schedules = append(schedules, schedule.DetailsScheduleDTO{
+				ScheduleID:  value.ScheduleID,
+				Type:        typeOfSubject.Name,
+				SubjectName: value.SubjectName,
+				Teacher:     value.Teacher,
+				Room:        value.Room,
+				IsEven:      value.IsEven,
+				EveryWeek:   value.EveryWeek,
+				DayOfWeek:   value.DayOfWeek,
+				StartTime:   value.StartTime,
+				EndTime:     value.EndTime,
+				Building:    building,
+			})
+		}
+
+		return nil
+	})
+
+	return schedules, err
+}
+
+func (s *ScheduleRepository) CreateExams(ctx context.Context, exams []schedule.Exam) error {
+	return s.store.do(ctx, func(t *tables) error {
+		for _, exam := range exams {
+			exam.ExamID = t.nextID()
+			t.exams[exam.ExamID] = exam
+		}
+
+		return nil
+	})
+}
+
+func (s *ScheduleRepository) UpdateExam(ctx context.Context, exam schedule.Exam) error {
+	return s.store.do(ctx, func(t *tables) error {
+		if _, ok := t.exams[exam.ExamID]; ok {
+			t.exams[exam.ExamID] = exam
+		}
+
+		return nil
+	})
+}
+
+func (s *ScheduleRepository) DeleteExam(ctx context.Context, examID uint64) error {
+	return s.store.do(ctx, func(t *tables) error {
+		delete(t.exams, examID)
+		return nil
+	})
+}
+
+func (s *ScheduleRepository) DeleteExamsByTerm(ctx context.Context, groupID uint64, term string) error {
+	return s.store.do(ctx, func(t *tables) error {
+		for examID, exam := range t.exams {
+			if exam.GroupID == groupID && exam.Term == term {
+				delete(t.exams, examID)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *ScheduleRepository) GetExamById(ctx context.Context, examID uint64) (schedule.Exam, error) {
+	return byId(ctx, s.store, func(t *tables) map[uint64]schedule.Exam { return t.exams }, examID)
+}
+
+func (s *ScheduleRepository) GetExamsByGroupId(ctx context.Context, filter schedule.ExamFilterDTO, groupID uint64) ([]schedule.DetailsExamDTO, error) {
+	var exams []schedule.DetailsExamDTO
+
+	err := s.store.do(ctx, func(t *tables) error {
+		for _, exam := range t.exams {
+			if exam.GroupID != groupID || filter.Term != "" && exam.Term != filter.Term {
+				continue
+			}
+
+			if filter.From != nil && exam.Date.Before(*filter.From) || filter.To != nil && exam.Date.After(*filter.To) {
+				continue
+			}
+
+			building, ok := t.buildings[exam.BuildingsID]
+			if !ok {
+				continue
+			}
+
+			exams = append(exams, schedule.DetailsExamDTO{
+				ExamID:      exam.ExamID,
+				Term:        exam.Term,
+				Kind:        exam.Kind,
+				SubjectName: exam.SubjectName,
+				Examiner:    exam.Examiner,
+				Room:        exam.Room,
+				Date:        exam.Date,
+				StartTime:   exam.StartTime,
+				EndTime:     exam.EndTime,
+				Building:    building,
+			})
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(exams, func(a, b schedule.DetailsExamDTO) int {
+		return cmp.Or(a.Date.Compare(b.Date), cmp.Compare(a.StartTime, b.StartTime), cmp.Compare(a.ExamID, b.ExamID))
+	})
+
+	return exams, err
+}
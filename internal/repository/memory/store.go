@@ -0,0 +1,178 @@
+// Package memory implements the repository interfaces of the domain packages in process memory,
+// so services can be exercised without a database. It mirrors the postgres repositories closely
+// enough to pass the same contract suites: missing rows are pgx.ErrNoRows and unique constraints
+// are reported as postgres unique violations
+package memory
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tclutin/classflow-api/internal/domain/access"
+	"github.com/tclutin/classflow-api/internal/domain/announcement"
+	"github.com/tclutin/classflow-api/internal/domain/attendance"
+	"github.com/tclutin/classflow-api/internal/domain/audit"
+	"github.com/tclutin/classflow-api/internal/domain/edu"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/homework"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// uniqueViolation is the postgres error code checked by postgresql.IsUniqueViolation
+const uniqueViolation = "23505"
+
+// mark is the primary key of a per-user flag on a record, like a read receipt of an announcement
+// or a done mark of a homework
+type mark struct {
+	ID     uint64
+	UserID uint64
+}
+
+type member struct {
+	MemberID uint64
+	UserID   uint64
+	GroupID  uint64
+}
+
+// tables is the whole state of the store, values are copied in and out, so a shallow clone
+// is enough to take a snapshot
+type tables struct {
+	seq         uint64
+	users       map[uint64]user.User
+	groups      map[uint64]group.Group
+	members     map[uint64]member
+	waitlist    map[uint64]group.WaitlistEntry
+	schedules   []schedule.Schedule
+	faculties   map[uint64]edu.Faculty
+	programs    map[uint64]edu.Program
+	types       map[uint64]edu.TypeOfSubject
+	buildings   map[uint64]edu.Building
+	grants      []access.Grant
+	scopes      map[uint64][]access.Scope
+	auditLog    []audit.Record
+	outboxQueue []outbox.Event
+	posts       map[uint64]announcement.Announcement
+	reads       map[mark]time.Time
+	homework    map[uint64]homework.Homework
+	done        map[mark]time.Time
+	exams       map[uint64]schedule.Exam
+	attendance  map[uint64]attendance.Record
+	windows     map[uint64]attendance.Window
+}
+
+func (t *tables) clone() tables {
+	return tables{
+		seq:         t.seq,
+		users:       maps.Clone(t.users),
+		groups:      maps.Clone(t.groups),
+		members:     maps.Clone(t.members),
+		waitlist:    maps.Clone(t.waitlist),
+		schedules:   slices.Clone(t.schedules),
+		faculties:   maps.Clone(t.faculties),
+		programs:    maps.Clone(t.programs),
+		types:       maps.Clone(t.types),
+		buildings:   maps.Clone(t.buildings),
+		grants:      slices.Clone(t.grants),
+		scopes:      cloneScopes(t.scopes),
+		auditLog:    slices.Clone(t.auditLog),
+		outboxQueue: slices.Clone(t.outboxQueue),
+		posts:       maps.Clone(t.posts),
+		reads:       maps.Clone(t.reads),
+		homework:    maps.Clone(t.homework),
+		done:        maps.Clone(t.done),
+		exams:       maps.Clone(t.exams),
+		attendance:  maps.Clone(t.attendance),
+		windows:     maps.Clone(t.windows),
+	}
+}
+
+// nextID hands out ids from one sequence shared by all tables, ids are unique and increasing
+func (t *tables) nextID() uint64 {
+	t.seq++
+	return t.seq
+}
+
+// Store holds the tables of all in-memory repositories, a transaction of TxManager covers
+// every repository built on the same store
+type Store struct {
+	// txMu serializes transactions, an operation outside a transaction runs as its own one
+	txMu sync.Mutex
+	mu   sync.Mutex
+	data tables
+}
+
+func NewStore() *Store {
+	return &Store{
+		data: tables{
+			users:      make(map[uint64]user.User),
+			groups:     make(map[uint64]group.Group),
+			members:    make(map[uint64]member),
+			waitlist:   make(map[uint64]group.WaitlistEntry),
+			faculties:  make(map[uint64]edu.Faculty),
+			programs:   make(map[uint64]edu.Program),
+			types:      make(map[uint64]edu.TypeOfSubject),
+			buildings:  make(map[uint64]edu.Building),
+			scopes:     make(map[uint64][]access.Scope),
+			posts:      make(map[uint64]announcement.Announcement),
+			reads:      make(map[mark]time.Time),
+			homework:   make(map[uint64]homework.Homework),
+			done:       make(map[mark]time.Time),
+			exams:      make(map[uint64]schedule.Exam),
+			attendance: make(map[uint64]attendance.Record),
+			windows:    make(map[uint64]attendance.Window),
+		},
+	}
+}
+
+func (s *Store) do(ctx context.Context, fn func(t *tables) error) error {
+	if !inTx(ctx) {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn(&s.data)
+}
+
+func (s *Store) snapshot() tables {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data.clone()
+}
+
+func (s *Store) restore(snapshot tables) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = snapshot
+}
+
+func uniqueError(constraint string) error {
+	return &pgconn.PgError{
+		Code:           uniqueViolation,
+		ConstraintName: constraint,
+		Message:        "duplicate key value violates unique constraint \"" + constraint + "\"",
+	}
+}
+
+func cloneScopes(scopes map[uint64][]access.Scope) map[uint64][]access.Scope {
+	cloned := make(map[uint64][]access.Scope, len(scopes))
+
+	for userID, values := range scopes {
+		cloned[userID] = slices.Clone(values)
+	}
+
+	return cloned
+}
+
+func sortedKeys[V any](m map[uint64]V) []uint64 {
+	return slices.Sorted(maps.Keys(m))
+}
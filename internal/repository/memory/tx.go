@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+)
+
+type txKey struct{}
+
+// TxManager is the in-memory counterpart of postgresql.TxManager. Transactions are serialized and
+// see their own writes, a failed one restores the store to the state it had when it started.
+// Nested calls join the outer transaction, the options are accepted and ignored
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{
+		store: store,
+	}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, _ ...postgresql.TxOption) error {
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+
+	m.store.txMu.Lock()
+	defer m.store.txMu.Unlock()
+
+	snapshot := m.store.snapshot()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		m.store.restore(snapshot)
+		return err
+	}
+
+	return nil
+}
+
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(bool)
+	return ok
+}
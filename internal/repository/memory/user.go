@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{
+		store: store,
+	}
+}
+
+func (u *UserRepository) Create(ctx context.Context, entity user.User) (uint64, error) {
+	var userID uint64
+
+	err := u.store.do(ctx, func(t *tables) error {
+		if entity.Email != nil && emailTaken(t, *entity.Email, 0) {
+			return uniqueError("users_email_key")
+		}
+
+		userID = t.nextID()
+		entity.UserID = userID
+		t.users[userID] = entity
+
+		return nil
+	})
+
+	return userID, err
+}
+
+func (u *UserRepository) Update(ctx context.Context, entity user.User) error {
+	return u.store.do(ctx, func(t *tables) error {
+		if _, ok := t.users[entity.UserID]; !ok {
+			return nil
+		}
+
+		if entity.Email != nil && emailTaken(t, *entity.Email, entity.UserID) {
+			return uniqueError("users_email_key")
+		}
+
+		t.users[entity.UserID] = entity
+
+		return nil
+	})
+}
+
+func (u *UserRepository) GetById(ctx context.Context, userID uint64) (user.User, error) {
+	return u.find(ctx, func(entity user.User) bool {
+		return entity.UserID == userID
+	})
+}
+
+func (u *UserRepository) GetByEmail(ctx context.Context, email string) (user.User, error) {
+	return u.find(ctx, func(entity user.User) bool {
+		return entity.Email != nil && *entity.Email == email
+	})
+}
+
+func (u *UserRepository) GetByTelegramChatId(ctx context.Context, telegramChatID int64) (user.User, error) {
+	return u.find(ctx, func(entity user.User) bool {
+		return entity.TelegramChatID != nil && *entity.TelegramChatID == telegramChatID
+	})
+}
+
+// find returns the match with the lowest id, like the postgres repository reading the first row
+func (u *UserRepository) find(ctx context.Context, match func(user.User) bool) (user.User, error) {
+	var found user.User
+
+	err := u.store.do(ctx, func(t *tables) error {
+		for _, userID := range sortedKeys(t.users) {
+			if match(t.users[userID]) {
+				found = t.users[userID]
+				return nil
+			}
+		}
+
+		return pgx.ErrNoRows
+	})
+
+	return found, err
+}
+
+func emailTaken(t *tables, email string, exceptUserID uint64) bool {
+	for userID, entity := range t.users {
+		if userID != exceptUserID && entity.Email != nil && *entity.Email == email {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+)
+
+type WaitlistRepository struct {
+	store *Store
+}
+
+func NewWaitlistRepository(store *Store) *WaitlistRepository {
+	return &WaitlistRepository{
+		store: store,
+	}
+}
+
+func (w *WaitlistRepository) Create(ctx context.Context, entry group.WaitlistEntry) (uint64, error) {
+	err := w.store.do(ctx, func(t *tables) error {
+		for _, existing := range t.waitlist {
+			if existing.UserID == entry.UserID {
+				return uniqueError("group_waitlist_user_id_key")
+			}
+		}
+
+		entry.WaitlistID = t.nextID()
+		t.waitlist[entry.WaitlistID] = entry
+
+		return nil
+	})
+
+	return entry.WaitlistID, err
+}
+
+func (w *WaitlistRepository) Position(ctx context.Context, groupID, waitlistID uint64) (int, error) {
+	var position int
+
+	err := w.store.do(ctx, func(t *tables) error {
+		for _, entry := range t.waitlist {
+			if entry.GroupID == groupID && entry.WaitlistID <= waitlistID {
+				position++
+			}
+		}
+
+		return nil
+	})
+
+	return position, err
+}
+
+func (w *WaitlistRepository) GetFirstByGroupId(ctx context.Context, groupID uint64) (group.WaitlistEntry, error) {
+	var first group.WaitlistEntry
+
+	err := w.store.do(ctx, func(t *tables) error {
+		for _, waitlistID := range sortedKeys(t.waitlist) {
+			if t.waitlist[waitlistID].GroupID == groupID {
+				first = t.waitlist[waitlistID]
+				return nil
+			}
+		}
+
+		return pgx.ErrNoRows
+	})
+
+	return first, err
+}
+
+func (w *WaitlistRepository) Delete(ctx context.Context, waitlistID uint64) error {
+	return w.store.do(ctx, func(t *tables) error {
+		delete(t.waitlist, waitlistID)
+		return nil
+	})
+}
+
+func (w *WaitlistRepository) DeleteByUserId(ctx context.Context, userID uint64) error {
+	return w.store.do(ctx, func(t *tables) error {
+		for waitlistID, entry := range t.waitlist {
+			if entry.UserID == userID {
+				delete(t.waitlist, waitlistID)
+				return nil
+			}
+		}
+
+		return pgx.ErrNoRows
+	})
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"log/slog"
+	"time"
+)
+
+type OutboxRepository struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool, logger *slog.Logger) *OutboxRepository {
+	return &OutboxRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+func (o *OutboxRepository) Create(ctx context.Context, event outbox.Event) error {
+	ctx = postgresql.WithQueryName(ctx, "OutboxRepository.Create")
+
+	sql := `INSERT INTO public.outbox (event_type, payload, created_at) VALUES ($1, $2, $3)`
+
+	_, err := postgresql.Conn(ctx, o.pool).Exec(ctx, sql, event.EventType, event.Payload, event.CreatedAt)
+
+	if err != nil {
+		o.logger.ErrorContext(ctx, "Failed to create outbox event",
+			"error", err,
+			"event_type", event.EventType,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (o *OutboxRepository) GetPending(ctx context.Context, limit int) ([]outbox.Event, error) {
+	ctx = postgresql.WithQueryName(ctx, "OutboxRepository.GetPending")
+
+	sql := `
+		SELECT
+			event_id,
+			event_type,
+			payload,
+			created_at,
+			processed_at
+		FROM
+			public.outbox
+		WHERE
+			processed_at IS NULL
+		ORDER BY
+			event_id
+		LIMIT $1
+		`
+
+	rows, err := postgresql.Conn(ctx, o.pool).Query(ctx, sql, limit)
+	if err != nil {
+		o.logger.ErrorContext(ctx, "Failed to get pending outbox events",
+			"error", err,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []outbox.Event
+
+	for rows.Next() {
+		var event outbox.Event
+		err = rows.Scan(
+			&event.EventID,
+			&event.EventType,
+			&event.Payload,
+			&event.CreatedAt,
+			&event.ProcessedAt)
+
+		if err != nil {
+			o.logger.ErrorContext(ctx, "Failed to scan outbox event row",
+				"error", err,
+			)
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (o *OutboxRepository) MarkProcessed(ctx context.Context, eventIDs []uint64, processedAt time.Time) error {
+	ctx = postgresql.WithQueryName(ctx, "OutboxRepository.MarkProcessed")
+
+	sql := `UPDATE public.outbox SET processed_at = $1 WHERE event_id = ANY($2) AND processed_at IS NULL`
+
+	_, err := postgresql.Conn(ctx, o.pool).Exec(ctx, sql, processedAt, eventIDs)
+
+	if err != nil {
+		o.logger.ErrorContext(ctx, "Failed to mark outbox events as processed",
+			"error", err,
+			"event_ids", eventIDs,
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"encoding/json"
+	"github.com/tclutin/classflow-api/internal/domain/outbox"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	ctx, repos, _ := setup(t)
+
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		mustNoErr(t, repos.Outbox.Create(ctx, outbox.Event{
+			EventType: outbox.GroupWaitlistPromoted,
+			Payload:   json.RawMessage(`{"group_id": 1}`),
+			CreatedAt: now,
+		}))
+	}
+
+	pending, err := repos.Outbox.GetPending(ctx, 10)
+	mustNoErr(t, err)
+
+	if len(pending) != 3 {
+		t.Fatalf("got %d pending events, want 3", len(pending))
+	}
+
+	ids := make([]uint64, 0, len(pending))
+	for _, event := range pending {
+		ids = append(ids, event.EventID)
+	}
+
+	tests := []struct {
+		name        string
+		acknowledge []uint64
+		limit       int
+		want        []uint64
+	}{
+		{name: "limit keeps publishing order", limit: 2, want: ids[:2]},
+		{name: "acknowledged events are not delivered", acknowledge: ids[1:2], limit: 10, want: []uint64{ids[0], ids[2]}},
+		{name: "acknowledging twice is harmless", acknowledge: ids[1:2], limit: 10, want: []uint64{ids[0], ids[2]}},
+		{name: "everything acknowledged", acknowledge: ids, limit: 10, want: nil},
+	}
+
+	// the steps share the events, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		if tt.acknowledge != nil {
+			mustNoErr(t, repos.Outbox.MarkProcessed(ctx, tt.acknowledge, time.Now()))
+		}
+
+		events, err := repos.Outbox.GetPending(ctx, tt.limit)
+		mustNoErr(t, err)
+
+		var got []uint64
+		for _, event := range events {
+			got = append(got, event.EventID)
+
+			if event.ProcessedAt != nil {
+				t.Fatalf("%s: pending event %d is processed", tt.name, event.EventID)
+			}
+		}
+
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("%s: got events %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,23 +2,42 @@ package repository
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/lockout"
 	"log/slog"
 )
 
 type Repositories struct {
-	User     *UserRepository
-	Group    *GroupRepository
-	Edu      *EduRepository
-	Member   *MemberRepository
-	Schedule *ScheduleRepository
+	User         *UserRepository
+	Group        *GroupRepository
+	Edu          *EduRepository
+	Member       *MemberRepository
+	Schedule     *ScheduleRepository
+	Lockout      lockout.Repository
+	Access       *AccessRepository
+	Audit        *AuditRepository
+	Waitlist     *WaitlistRepository
+	Outbox       *OutboxRepository
+	Announcement *AnnouncementRepository
+	Homework     *HomeworkRepository
+	Attendance   *AttendanceRepository
 }
 
-func NewRepositories(pool *pgxpool.Pool, logger *slog.Logger) *Repositories {
+// NewRepositories routes the edu catalogue, schedule and exam listings, group summaries and audit queries to the replica pool,
+// lookups that guard a write stay on the primary. Pass the primary pool when there is no replica
+func NewRepositories(pool *pgxpool.Pool, replica *pgxpool.Pool, logger *slog.Logger) *Repositories {
 	return &Repositories{
-		User:     NewUserRepository(pool, logger),
-		Group:    NewGroupRepository(pool, logger),
-		Edu:      NewEduRepository(pool, logger),
-		Member:   NewMemberRepository(pool, logger),
-		Schedule: NewScheduleRepository(pool, logger),
+		User:         NewUserRepository(pool, logger),
+		Group:        NewGroupRepository(pool, replica, logger),
+		Edu:          NewEduRepository(replica, logger),
+		Member:       NewMemberRepository(pool, logger),
+		Schedule:     NewScheduleRepository(pool, replica, logger),
+		Lockout:      NewLockoutRepository(pool, logger),
+		Access:       NewAccessRepository(pool, logger),
+		Audit:        NewAuditRepository(pool, replica, logger),
+		Waitlist:     NewWaitlistRepository(pool, logger),
+		Outbox:       NewOutboxRepository(pool, logger),
+		Announcement: NewAnnouncementRepository(pool, logger),
+		Homework:     NewHomeworkRepository(pool, logger),
+		Attendance:   NewAttendanceRepository(pool, logger),
 	}
 }
@@ -2,33 +2,40 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/sqlbuilder"
 	"log/slog"
 )
 
 type ScheduleRepository struct {
-	pool   *pgxpool.Pool
-	logger *slog.Logger
+	pool    *pgxpool.Pool
+	replica *pgxpool.Pool
+	logger  *slog.Logger
 }
 
-func NewScheduleRepository(pool *pgxpool.Pool, logger *slog.Logger) *ScheduleRepository {
+func NewScheduleRepository(pool *pgxpool.Pool, replica *pgxpool.Pool, logger *slog.Logger) *ScheduleRepository {
 	return &ScheduleRepository{
-		pool:   pool,
-		logger: logger,
+		pool:    pool,
+		replica: replica,
+		logger:  logger,
 	}
 }
 
-func (s *ScheduleRepository) CreateTx(ctx context.Context, tx pgx.Tx, schedule []schedule.Schedule) error {
+func (s *ScheduleRepository) Create(ctx context.Context, schedule []schedule.Schedule) error {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.Create")
+
 	sql := `
 		INSERT INTO public.schedule
-		(group_id, buildings_id, type_of_subject_id, subject_name, teacher, room, is_even, day_of_week, start_time, end_time, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		(group_id, buildings_id, type_of_subject_id, subject_name, teacher, room, is_even, every_week, day_of_week, start_time, end_time, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		`
 
 	for _, value := range schedule {
-		_, err := tx.Exec(
+		_, err := postgresql.Conn(ctx, s.pool).Exec(
 			ctx,
 			sql,
 			value.GroupID,
@@ -38,13 +45,14 @@ func (s *ScheduleRepository) CreateTx(ctx context.Context, tx pgx.Tx, schedule [
 			value.Teacher,
 			value.Room,
 			value.IsEven,
+			value.EveryWeek,
 			value.DayOfWeek,
 			value.StartTime,
 			value.EndTime,
 			value.CreatedAt)
 
 		if err != nil {
-			s.logger.Error("Failed to insert schedule",
+			s.logger.ErrorContext(ctx, "Failed to insert schedule",
 				"error", err,
 				"group_id", value.GroupID,
 				"subject_name", value.SubjectName,
@@ -57,14 +65,74 @@ func (s *ScheduleRepository) CreateTx(ctx context.Context, tx pgx.Tx, schedule [
 	return nil
 }
 
+func (s *ScheduleRepository) GetById(ctx context.Context, scheduleID uint64) (schedule.Schedule, error) {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.GetById")
+
+	sql := `
+		SELECT
+			schedule_id,
+			group_id,
+			buildings_id,
+			type_of_subject_id,
+			subject_name,
+			teacher,
+			room,
+			is_even,
+			every_week,
+			day_of_week,
+			start_time,
+			end_time,
+			created_at
+		FROM
+			public.schedule
+		WHERE
+			schedule_id = $1
+		`
+
+	row := postgresql.Conn(ctx, s.pool).QueryRow(ctx, sql, scheduleID)
+
+	var lesson schedule.Schedule
+
+	err := row.Scan(
+		&lesson.ScheduleID,
+		&lesson.GroupID,
+		&lesson.BuildingsID,
+		&lesson.TypeOfSubjectID,
+		&lesson.SubjectName,
+		&lesson.Teacher,
+		&lesson.Room,
+		&lesson.IsEven,
+		&lesson.EveryWeek,
+		&lesson.DayOfWeek,
+		&lesson.StartTime,
+		&lesson.EndTime,
+		&lesson.CreatedAt)
+
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.ErrorContext(ctx, "Failed to get schedule by id",
+				"error", err,
+				"schedule_id", scheduleID,
+			)
+		}
+		return lesson, err
+	}
+
+	return lesson, nil
+}
+
 func (s *ScheduleRepository) GetSchedulesByGroupId(ctx context.Context, filter schedule.FilterDTO, groupID uint64) ([]schedule.DetailsScheduleDTO, error) {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.GetSchedulesByGroupId")
+
 	sql := `
 		SELECT
+			s.schedule_id,
 			t.name,
 			s.subject_name,
 			s.teacher,
 			s.room,
 			s.is_even,
+			s.every_week,
 			s.day_of_week,
 			s.start_time,
 			s.end_time,
@@ -83,17 +151,18 @@ func (s *ScheduleRepository) GetSchedulesByGroupId(ctx context.Context, filter s
 			group_id = $1
 		`
 
+	// lessons of a single-week schedule take place in the weeks of both parities
 	if filter.IsEven == "true" {
-		sql += " AND s.is_even = true"
+		sql += " AND (s.is_even = true OR s.every_week)"
 	}
 
 	if filter.IsEven == "false" {
-		sql += " AND s.is_even = false"
+		sql += " AND (s.is_even = false OR s.every_week)"
 	}
 
-	rows, err := s.pool.Query(ctx, sql, groupID)
+	rows, err := postgresql.Conn(ctx, s.replica).Query(ctx, sql, groupID)
 	if err != nil {
-		s.logger.Error("Failed to execute query",
+		s.logger.ErrorContext(ctx, "Failed to execute query",
 			"error", err,
 			"group_id", groupID,
 		)
@@ -106,11 +175,13 @@ func (s *ScheduleRepository) GetSchedulesByGroupId(ctx context.Context, filter s
 	for rows.Next() {
 		var schedule schedule.DetailsScheduleDTO
 		err = rows.Scan(
+			&schedule.ScheduleID,
 			&schedule.Type,
 			&schedule.SubjectName,
 			&schedule.Teacher,
 			&schedule.Room,
 			&schedule.IsEven,
+			&schedule.EveryWeek,
 			&schedule.DayOfWeek,
 			&schedule.StartTime,
 			&schedule.EndTime,
@@ -121,7 +192,7 @@ func (s *ScheduleRepository) GetSchedulesByGroupId(ctx context.Context, filter s
 			&schedule.Building.Address)
 
 		if err != nil {
-			s.logger.Error("Failed to scan schedule row",
+			s.logger.ErrorContext(ctx, "Failed to scan schedule row",
 				"error", err,
 				"group_id", groupID,
 			)
@@ -133,3 +204,254 @@ func (s *ScheduleRepository) GetSchedulesByGroupId(ctx context.Context, filter s
 
 	return schedules, nil
 }
+
+func (s *ScheduleRepository) CreateExams(ctx context.Context, exams []schedule.Exam) error {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.CreateExams")
+
+	sql := `
+		INSERT INTO public.exams
+		(group_id, buildings_id, term, kind, subject_name, examiner, room, date, start_time, end_time, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`
+
+	for _, exam := range exams {
+		_, err := postgresql.Conn(ctx, s.pool).Exec(
+			ctx,
+			sql,
+			exam.GroupID,
+			exam.BuildingsID,
+			exam.Term,
+			exam.Kind,
+			exam.SubjectName,
+			exam.Examiner,
+			exam.Room,
+			exam.Date,
+			exam.StartTime,
+			exam.EndTime,
+			exam.CreatedAt,
+			exam.UpdatedAt)
+
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to insert exam",
+				"error", err,
+				"group_id", exam.GroupID,
+				"subject_name", exam.SubjectName,
+			)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ScheduleRepository) UpdateExam(ctx context.Context, exam schedule.Exam) error {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.UpdateExam")
+
+	sql := `
+		UPDATE public.exams
+		SET
+			buildings_id = $1,
+			kind = $2,
+			subject_name = $3,
+			examiner = $4,
+			room = $5,
+			date = $6,
+			start_time = $7,
+			end_time = $8,
+			updated_at = $9
+		WHERE exam_id = $10
+		`
+
+	_, err := postgresql.Conn(ctx, s.pool).Exec(
+		ctx,
+		sql,
+		exam.BuildingsID,
+		exam.Kind,
+		exam.SubjectName,
+		exam.Examiner,
+		exam.Room,
+		exam.Date,
+		exam.StartTime,
+		exam.EndTime,
+		exam.UpdatedAt,
+		exam.ExamID)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to update exam",
+			"error", err,
+			"exam_id", exam.ExamID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ScheduleRepository) DeleteExam(ctx context.Context, examID uint64) error {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.DeleteExam")
+
+	sql := `DELETE FROM public.exams WHERE exam_id = $1`
+
+	_, err := postgresql.Conn(ctx, s.pool).Exec(ctx, sql, examID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to delete exam",
+			"error", err,
+			"exam_id", examID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExamsByTerm clears the exam session of the group before it is uploaded again
+func (s *ScheduleRepository) DeleteExamsByTerm(ctx context.Context, groupID uint64, term string) error {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.DeleteExamsByTerm")
+
+	sql := `DELETE FROM public.exams WHERE group_id = $1 AND term = $2`
+
+	_, err := postgresql.Conn(ctx, s.pool).Exec(ctx, sql, groupID, term)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to delete exams",
+			"error", err,
+			"group_id", groupID,
+			"term", term,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ScheduleRepository) GetExamById(ctx context.Context, examID uint64) (schedule.Exam, error) {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.GetExamById")
+
+	sql := `
+		SELECT
+			exam_id,
+			group_id,
+			buildings_id,
+			term,
+			kind,
+			subject_name,
+			examiner,
+			room,
+			date,
+			start_time,
+			end_time,
+			created_at,
+			updated_at
+		FROM
+			public.exams
+		WHERE
+			exam_id = $1
+		`
+
+	row := postgresql.Conn(ctx, s.pool).QueryRow(ctx, sql, examID)
+
+	var exam schedule.Exam
+
+	err := row.Scan(
+		&exam.ExamID,
+		&exam.GroupID,
+		&exam.BuildingsID,
+		&exam.Term,
+		&exam.Kind,
+		&exam.SubjectName,
+		&exam.Examiner,
+		&exam.Room,
+		&exam.Date,
+		&exam.StartTime,
+		&exam.EndTime,
+		&exam.CreatedAt,
+		&exam.UpdatedAt)
+
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.ErrorContext(ctx, "Failed to get exam by id",
+				"error", err,
+				"exam_id", examID,
+			)
+		}
+		return exam, err
+	}
+
+	return exam, nil
+}
+
+func (s *ScheduleRepository) GetExamsByGroupId(ctx context.Context, filter schedule.ExamFilterDTO, groupID uint64) ([]schedule.DetailsExamDTO, error) {
+	ctx = postgresql.WithQueryName(ctx, "ScheduleRepository.GetExamsByGroupId")
+
+	sql, args := sqlbuilder.Select(`
+		SELECT
+			e.exam_id,
+			e.term,
+			e.kind,
+			e.subject_name,
+			e.examiner,
+			e.room,
+			e.date,
+			e.start_time,
+			e.end_time,
+			b.buildings_id,
+			b.name,
+			b.latitude,
+			b.longitude,
+			b.address
+		FROM
+			public.exams AS e
+		INNER JOIN
+			public.buildings AS b ON e.buildings_id = b.buildings_id
+	`).
+		Where("e.group_id = ?", groupID).
+		WhereIf(filter.Term != "", "e.term = ?", filter.Term).
+		WhereIf(filter.From != nil, "e.date >= ?", filter.From).
+		WhereIf(filter.To != nil, "e.date <= ?", filter.To).
+		OrderBy("e.date").
+		OrderBy("e.start_time").
+		OrderBy("e.exam_id").
+		Build()
+
+	rows, err := postgresql.Conn(ctx, s.replica).Query(ctx, sql, args...)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get exams",
+			"error", err,
+			"args", args,
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exams []schedule.DetailsExamDTO
+
+	for rows.Next() {
+		var exam schedule.DetailsExamDTO
+		err = rows.Scan(
+			&exam.ExamID,
+			&exam.Term,
+			&exam.Kind,
+			&exam.SubjectName,
+			&exam.Examiner,
+			&exam.Room,
+			&exam.Date,
+			&exam.StartTime,
+			&exam.EndTime,
+			&exam.Building.BuildingID,
+			&exam.Building.Name,
+			&exam.Building.Latitude,
+			&exam.Building.Longitude,
+			&exam.Building.Address)
+
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to scan exam row",
+				"error", err,
+				"group_id", groupID,
+			)
+			return nil, err
+		}
+
+		exams = append(exams, exam)
+	}
+
+	return exams, nil
+}
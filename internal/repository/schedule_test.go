@@ -0,0 +1,234 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScheduleRepository_GetSchedulesByGroupId(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	other, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	_, err = fixtures.Schedule(ctx, created.GroupID)
+	mustNoErr(t, err)
+
+	_, err = fixtures.Schedule(ctx, other.GroupID)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name   string
+		filter schedule.FilterDTO
+		odd    int
+		even   int
+	}{
+		{name: "both weeks", filter: schedule.FilterDTO{}, odd: 1, even: 1},
+		{name: "even week", filter: schedule.FilterDTO{IsEven: "true"}, even: 1},
+		{name: "odd week", filter: schedule.FilterDTO{IsEven: "false"}, odd: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lessons, err := repos.Schedule.GetSchedulesByGroupId(ctx, tt.filter, created.GroupID)
+			mustNoErr(t, err)
+
+			var odd, even int
+			for _, lesson := range lessons {
+				if lesson.IsEven {
+					even++
+				} else {
+					odd++
+				}
+
+				if lesson.Type == "" || lesson.Building.Name == "" {
+					t.Fatalf("lesson misses reference data: %+v", lesson)
+				}
+
+				found, err := repos.Schedule.GetById(ctx, lesson.ScheduleID)
+				mustNoErr(t, err)
+
+				if found.GroupID != created.GroupID {
+					t.Fatalf("got lesson of group %d, want %d", found.GroupID, created.GroupID)
+				}
+			}
+
+			if odd != tt.odd || even != tt.even {
+				t.Fatalf("got %d odd and %d even lessons, want %d and %d", odd, even, tt.odd, tt.even)
+			}
+		})
+	}
+}
+
+func TestScheduleRepository_Exams(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	buildings, err := repos.Edu.GetAllBuildings(ctx)
+	mustNoErr(t, err)
+
+	exam := func(term, kind, subject string, day time.Time, start string) schedule.Exam {
+		return schedule.Exam{
+			GroupID:     created.GroupID,
+			BuildingsID: buildings[0].BuildingID,
+			Term:        term,
+			Kind:        kind,
+			SubjectName: subject,
+			Examiner:    "Иванов И. И.",
+			Room:        "101",
+			Date:        day,
+			StartTime:   start,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+	}
+
+	mustNoErr(t, repos.Schedule.CreateExams(ctx, []schedule.Exam{
+		exam("2026-winter", schedule.ExamKindExam, "Алгебра", date(2027, time.January, 15), "09:00"),
+		exam("2026-winter", schedule.ExamKindConsultation, "Алгебра", date(2027, time.January, 14), "13:00"),
+		exam("2026-winter", schedule.ExamKindExam, "Геометрия", date(2027, time.January, 15), "08:00"),
+		exam("2027-summer", schedule.ExamKindRetake, "Алгебра", date(2027, time.June, 20), "10:00"),
+	}))
+
+	tests := []struct {
+		name   string
+		filter schedule.ExamFilterDTO
+		want   []string
+	}{
+		{
+			name:   "ordered by date and start time",
+			filter: schedule.ExamFilterDTO{},
+			want:   []string{schedule.ExamKindConsultation, schedule.ExamKindExam + " Геометрия", schedule.ExamKindExam + " Алгебра", schedule.ExamKindRetake},
+		},
+		{
+			name:   "by term",
+			filter: schedule.ExamFilterDTO{Term: "2027-summer"},
+			want:   []string{schedule.ExamKindRetake},
+		},
+		{
+			name:   "by inclusive dates",
+			filter: schedule.ExamFilterDTO{From: ptr(date(2027, time.January, 15)), To: ptr(date(2027, time.January, 15))},
+			want:   []string{schedule.ExamKindExam + " Геометрия", schedule.ExamKindExam + " Алгебра"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exams, err := repos.Schedule.GetExamsByGroupId(ctx, tt.filter, created.GroupID)
+			mustNoErr(t, err)
+
+			var got []string
+			for _, exam := range exams {
+				name := exam.Kind
+				if exam.Kind == schedule.ExamKindExam {
+					name += " " + exam.SubjectName
+				}
+				got = append(got, name)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got exams %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleRepository_ExamChanges(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	buildings, err := repos.Edu.GetAllBuildings(ctx)
+	mustNoErr(t, err)
+
+	mustNoErr(t, repos.Schedule.CreateExams(ctx, []schedule.Exam{{
+		GroupID:     created.GroupID,
+		BuildingsID: buildings[0].BuildingID,
+		Term:        "2026-winter",
+		Kind:        schedule.ExamKindExam,
+		SubjectName: "Алгебра",
+		Examiner:    "Иванов И. И.",
+		Room:        "101",
+		Date:        date(2027, time.January, 15),
+		StartTime:   "09:00",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}}))
+
+	exams, err := repos.Schedule.GetExamsByGroupId(ctx, schedule.ExamFilterDTO{}, created.GroupID)
+	mustNoErr(t, err)
+
+	examID := exams[0].ExamID
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+		check   func(schedule.Exam) bool
+	}{
+		{
+			name: "update",
+			run: func() error {
+				exam, err := repos.Schedule.GetExamById(ctx, examID)
+				if err != nil {
+					return err
+				}
+				exam.Room = "202"
+				exam.EndTime = ptr("12:00")
+				return repos.Schedule.UpdateExam(ctx, exam)
+			},
+			check: func(exam schedule.Exam) bool { return exam.Room == "202" && exam.EndTime != nil },
+		},
+		{
+			name: "end before start",
+			run: func() error {
+				exam, err := repos.Schedule.GetExamById(ctx, examID)
+				if err != nil {
+					return err
+				}
+				exam.EndTime = ptr("08:00")
+				if err = repos.Schedule.UpdateExam(ctx, exam); err == nil {
+					return errors.New("expected the check constraint to reject the exam")
+				}
+				return nil
+			},
+			check: func(exam schedule.Exam) bool { return exam.Room == "202" },
+		},
+		{
+			name:  "delete by another term",
+			run:   func() error { return repos.Schedule.DeleteExamsByTerm(ctx, created.GroupID, "2027-summer") },
+			check: func(exam schedule.Exam) bool { return true },
+		},
+		{
+			name:    "delete by term",
+			run:     func() error { return repos.Schedule.DeleteExamsByTerm(ctx, created.GroupID, "2026-winter") },
+			wantErr: pgx.ErrNoRows,
+		},
+	}
+
+	// the steps share the exam, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		mustNoErr(t, tt.run())
+
+		exam, err := repos.Schedule.GetExamById(ctx, examID)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: got error %v, want %v", tt.name, err, tt.wantErr)
+		}
+
+		if err == nil && !tt.check(exam) {
+			t.Fatalf("%s: unexpected exam %+v", tt.name, exam)
+		}
+	}
+}
@@ -2,9 +2,9 @@ package repository
 
 import (
 	"context"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
 	"log/slog"
 )
 
@@ -21,11 +21,13 @@ func NewUserRepository(pool *pgxpool.Pool, logger *slog.Logger) *UserRepository
 }
 
 func (u *UserRepository) Create(ctx context.Context, user user.User) (uint64, error) {
+	ctx = postgresql.WithQueryName(ctx, "UserRepository.Create")
+
 	sql := `INSERT INTO public.users (email, password_hash, role, fullname, telegram_username, telegram_chat, notification_delay, notifications_enabled, created_at)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 			RETURNING user_id`
 
-	row := u.pool.QueryRow(
+	row := postgresql.Conn(ctx, u.pool).QueryRow(
 		ctx,
 		sql,
 		user.Email,
@@ -41,7 +43,7 @@ func (u *UserRepository) Create(ctx context.Context, user user.User) (uint64, er
 	var userID uint64
 
 	if err := row.Scan(&userID); err != nil {
-		u.logger.Error("Failed to create user",
+		u.logger.ErrorContext(ctx, "Failed to create user",
 			"error", err,
 			"email", user.Email,
 			"role", user.Role,
@@ -53,47 +55,8 @@ func (u *UserRepository) Create(ctx context.Context, user user.User) (uint64, er
 }
 
 func (u *UserRepository) Update(ctx context.Context, user user.User) error {
-	sql := `
-		UPDATE
-			public.users
-		SET
-		    email = $1,
-		    password_hash = $2,
-		    role = $3,
-		    fullname = $4,
-		    telegram_chat = $5,
-		    telegram_username = $6,
-		    notification_delay = $7,
-		    notifications_enabled = $8
-		WHERE
-		    user_id = $9
-	`
+	ctx = postgresql.WithQueryName(ctx, "UserRepository.Update")
 
-	_, err := u.pool.Exec(
-		ctx,
-		sql,
-		user.Email,
-		user.PasswordHash,
-		user.Role,
-		user.FullName,
-		user.TelegramChatID,
-		user.TelegramUsername,
-		user.NotificationDelay,
-		user.NotificationsEnabled,
-		user.UserID)
-
-	if err != nil {
-		u.logger.Error("Failed to update user",
-			"error", err,
-			"userID", user.UserID,
-		)
-		return err
-	}
-
-	return nil
-}
-
-func (u *UserRepository) UpdateTx(ctx context.Context, tx pgx.Tx, user user.User) error {
 	sql := `
 		UPDATE
 			public.users
@@ -110,7 +73,7 @@ func (u *UserRepository) UpdateTx(ctx context.Context, tx pgx.Tx, user user.User
 		    user_id = $9
 	`
 
-	_, err := tx.Exec(
+	_, err := postgresql.Conn(ctx, u.pool).Exec(
 		ctx,
 		sql,
 		user.Email,
@@ -124,7 +87,7 @@ func (u *UserRepository) UpdateTx(ctx context.Context, tx pgx.Tx, user user.User
 		user.UserID)
 
 	if err != nil {
-		u.logger.Error("Failed to update user",
+		u.logger.ErrorContext(ctx, "Failed to update user",
 			"error", err,
 			"userID", user.UserID,
 		)
@@ -135,9 +98,11 @@ func (u *UserRepository) UpdateTx(ctx context.Context, tx pgx.Tx, user user.User
 }
 
 func (u *UserRepository) GetById(ctx context.Context, userID uint64) (user.User, error) {
+	ctx = postgresql.WithQueryName(ctx, "UserRepository.GetById")
+
 	sql := `SELECT * FROM public.users WHERE user_id = $1`
 
-	row := u.pool.QueryRow(ctx, sql, userID)
+	row := postgresql.Conn(ctx, u.pool).QueryRow(ctx, sql, userID)
 
 	var usr user.User
 	err := row.Scan(
@@ -154,7 +119,7 @@ func (u *UserRepository) GetById(ctx context.Context, userID uint64) (user.User,
 	)
 
 	if err != nil {
-		u.logger.Error("Failed to get user by ID",
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
 			"error", err,
 			"userID", userID,
 		)
@@ -165,9 +130,11 @@ func (u *UserRepository) GetById(ctx context.Context, userID uint64) (user.User,
 }
 
 func (u *UserRepository) GetByEmail(ctx context.Context, email string) (user.User, error) {
+	ctx = postgresql.WithQueryName(ctx, "UserRepository.GetByEmail")
+
 	sql := `SELECT * FROM public.users WHERE email = $1`
 
-	row := u.pool.QueryRow(ctx, sql, email)
+	row := postgresql.Conn(ctx, u.pool).QueryRow(ctx, sql, email)
 
 	var usr user.User
 	err := row.Scan(
@@ -184,7 +151,7 @@ func (u *UserRepository) GetByEmail(ctx context.Context, email string) (user.Use
 	)
 
 	if err != nil {
-		u.logger.Error("Failed to retrieve user by email",
+		u.logger.ErrorContext(ctx, "Failed to retrieve user by email",
 			"error", err,
 			"email", email,
 		)
@@ -195,9 +162,11 @@ func (u *UserRepository) GetByEmail(ctx context.Context, email string) (user.Use
 }
 
 func (u *UserRepository) GetByTelegramChatId(ctx context.Context, telegramChatID int64) (user.User, error) {
+	ctx = postgresql.WithQueryName(ctx, "UserRepository.GetByTelegramChatId")
+
 	sql := `SELECT * FROM public.users WHERE telegram_chat = $1`
 
-	row := u.pool.QueryRow(ctx, sql, telegramChatID)
+	row := postgresql.Conn(ctx, u.pool).QueryRow(ctx, sql, telegramChatID)
 
 	var usr user.User
 	err := row.Scan(
@@ -213,7 +182,7 @@ func (u *UserRepository) GetByTelegramChatId(ctx context.Context, telegramChatID
 		&usr.CreatedAt)
 
 	if err != nil {
-		u.logger.Error("Failed to retrieve user by Telegram Chat ID",
+		u.logger.ErrorContext(ctx, "Failed to retrieve user by Telegram Chat ID",
 			"error", err,
 			"telegramChatID", telegramChatID,
 		)
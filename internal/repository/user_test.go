@@ -0,0 +1,107 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"testing"
+)
+
+func TestUserRepository_Lookup(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	student, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	admin, err := fixtures.Admin(ctx)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name    string
+		get     func() (user.User, error)
+		want    uint64
+		wantErr error
+	}{
+		{
+			name: "by id",
+			get:  func() (user.User, error) { return repos.User.GetById(ctx, student.UserID) },
+			want: student.UserID,
+		},
+		{
+			name: "by email",
+			get:  func() (user.User, error) { return repos.User.GetByEmail(ctx, *admin.Email) },
+			want: admin.UserID,
+		},
+		{
+			name: "by telegram chat",
+			get:  func() (user.User, error) { return repos.User.GetByTelegramChatId(ctx, *student.TelegramChatID) },
+			want: student.UserID,
+		},
+		{
+			name:    "unknown id",
+			get:     func() (user.User, error) { return repos.User.GetById(ctx, admin.UserID+100) },
+			wantErr: pgx.ErrNoRows,
+		},
+		{
+			name:    "unknown email",
+			get:     func() (user.User, error) { return repos.User.GetByEmail(ctx, "nobody@classflow.test") },
+			wantErr: pgx.ErrNoRows,
+		},
+		{
+			name:    "unknown telegram chat",
+			get:     func() (user.User, error) { return repos.User.GetByTelegramChatId(ctx, -1) },
+			wantErr: pgx.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && got.UserID != tt.want {
+				t.Fatalf("got user %d, want %d", got.UserID, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserRepository_Update(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	student, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	fullname := "Петров П. П."
+	enabled := false
+
+	student.Role = user.Leader
+	student.FullName = &fullname
+	student.NotificationsEnabled = &enabled
+
+	mustNoErr(t, repos.User.Update(ctx, student))
+
+	got, err := repos.User.GetById(ctx, student.UserID)
+	mustNoErr(t, err)
+
+	if got.Role != user.Leader || *got.FullName != fullname || *got.NotificationsEnabled {
+		t.Fatalf("update was not saved: %+v", got)
+	}
+}
+
+func TestUserRepository_DuplicateEmail(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	admin, err := fixtures.Admin(ctx)
+	mustNoErr(t, err)
+
+	_, err = repos.User.Create(ctx, user.User{Email: admin.Email, Role: user.Admin, CreatedAt: admin.CreatedAt})
+	if !postgresql.IsUniqueViolation(err) {
+		t.Fatalf("expected a unique violation, got %v", err)
+	}
+}
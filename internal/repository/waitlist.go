@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"log/slog"
+)
+
+type WaitlistRepository struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewWaitlistRepository(pool *pgxpool.Pool, logger *slog.Logger) *WaitlistRepository {
+	return &WaitlistRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+func (w *WaitlistRepository) Create(ctx context.Context, entry group.WaitlistEntry) (uint64, error) {
+	ctx = postgresql.WithQueryName(ctx, "WaitlistRepository.Create")
+
+	sql := `INSERT INTO public.group_waitlist (group_id, user_id, created_at) VALUES ($1, $2, $3) RETURNING waitlist_id`
+
+	row := postgresql.Conn(ctx, w.pool).QueryRow(ctx, sql, entry.GroupID, entry.UserID, entry.CreatedAt)
+
+	var waitlistID uint64
+
+	if err := row.Scan(&waitlistID); err != nil {
+		if postgresql.IsUniqueViolation(err) {
+			return 0, err
+		}
+
+		w.logger.ErrorContext(ctx, "Failed to create waitlist entry",
+			"error", err,
+			"group_id", entry.GroupID,
+			"user_id", entry.UserID,
+		)
+		return 0, err
+	}
+
+	return waitlistID, nil
+}
+
+// Position counts the entries of the group queued up to and including the given one
+func (w *WaitlistRepository) Position(ctx context.Context, groupID, waitlistID uint64) (int, error) {
+	ctx = postgresql.WithQueryName(ctx, "WaitlistRepository.Position")
+
+	sql := `SELECT COUNT(*) FROM public.group_waitlist WHERE group_id = $1 AND waitlist_id <= $2`
+
+	row := postgresql.Conn(ctx, w.pool).QueryRow(ctx, sql, groupID, waitlistID)
+
+	var position int
+
+	if err := row.Scan(&position); err != nil {
+		w.logger.ErrorContext(ctx, "Failed to get waitlist position",
+			"error", err,
+			"group_id", groupID,
+			"waitlist_id", waitlistID,
+		)
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// GetFirstByGroupId returns pgx.ErrNoRows when nobody is waiting for the group
+func (w *WaitlistRepository) GetFirstByGroupId(ctx context.Context, groupID uint64) (group.WaitlistEntry, error) {
+	ctx = postgresql.WithQueryName(ctx, "WaitlistRepository.GetFirstByGroupId")
+
+	sql := `
+		SELECT
+			waitlist_id,
+			group_id,
+			user_id,
+			created_at
+		FROM
+			public.group_waitlist
+		WHERE
+			group_id = $1
+		ORDER BY
+			waitlist_id
+		LIMIT 1
+		`
+
+	row := postgresql.Conn(ctx, w.pool).QueryRow(ctx, sql, groupID)
+
+	var entry group.WaitlistEntry
+
+	err := row.Scan(
+		&entry.WaitlistID,
+		&entry.GroupID,
+		&entry.UserID,
+		&entry.CreatedAt)
+
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			w.logger.ErrorContext(ctx, "Failed to get first waitlist entry",
+				"error", err,
+				"group_id", groupID,
+			)
+		}
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+func (w *WaitlistRepository) Delete(ctx context.Context, waitlistID uint64) error {
+	ctx = postgresql.WithQueryName(ctx, "WaitlistRepository.Delete")
+
+	sql := `DELETE FROM public.group_waitlist WHERE waitlist_id = $1`
+
+	_, err := postgresql.Conn(ctx, w.pool).Exec(ctx, sql, waitlistID)
+
+	if err != nil {
+		w.logger.ErrorContext(ctx, "Failed to delete waitlist entry",
+			"error", err,
+			"waitlist_id", waitlistID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByUserId returns pgx.ErrNoRows when the user is not waiting for any group
+func (w *WaitlistRepository) DeleteByUserId(ctx context.Context, userID uint64) error {
+	ctx = postgresql.WithQueryName(ctx, "WaitlistRepository.DeleteByUserId")
+
+	sql := `DELETE FROM public.group_waitlist WHERE user_id = $1`
+
+	tag, err := postgresql.Conn(ctx, w.pool).Exec(ctx, sql, userID)
+
+	if err != nil {
+		w.logger.ErrorContext(ctx, "Failed to delete waitlist entry",
+			"error", err,
+			"user_id", userID,
+		)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
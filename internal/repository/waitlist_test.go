@@ -0,0 +1,141 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"testing"
+	"time"
+)
+
+func TestWaitlistRepository_Position(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx, func(g *group.Group) { g.Capacity = ptr(1) })
+	mustNoErr(t, err)
+
+	other, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	var entries []uint64
+
+	// entries of another group interleave with the ones of the full group and must not shift positions
+	for i, groupID := range []uint64{created.GroupID, other.GroupID, created.GroupID, created.GroupID} {
+		student, err := fixtures.Student(ctx)
+		mustNoErr(t, err)
+
+		waitlistID, err := repos.Waitlist.Create(ctx, group.WaitlistEntry{
+			GroupID:   groupID,
+			UserID:    student.UserID,
+			CreatedAt: time.Now().Add(time.Duration(i) * time.Second),
+		})
+		mustNoErr(t, err)
+
+		entries = append(entries, waitlistID)
+	}
+
+	tests := []struct {
+		name       string
+		groupID    uint64
+		waitlistID uint64
+		want       int
+	}{
+		{name: "first", groupID: created.GroupID, waitlistID: entries[0], want: 1},
+		{name: "after an entry of another group", groupID: created.GroupID, waitlistID: entries[2], want: 2},
+		{name: "last", groupID: created.GroupID, waitlistID: entries[3], want: 3},
+		{name: "only entry of another group", groupID: other.GroupID, waitlistID: entries[1], want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repos.Waitlist.Position(ctx, tt.groupID, tt.waitlistID)
+			mustNoErr(t, err)
+
+			if got != tt.want {
+				t.Fatalf("got position %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	first, err := repos.Waitlist.GetFirstByGroupId(ctx, created.GroupID)
+	mustNoErr(t, err)
+
+	if first.WaitlistID != entries[0] {
+		t.Fatalf("got first entry %d, want %d", first.WaitlistID, entries[0])
+	}
+}
+
+func TestWaitlistRepository_Delete(t *testing.T) {
+	ctx, repos, fixtures := setup(t)
+
+	created, err := fixtures.Group(ctx)
+	mustNoErr(t, err)
+
+	student, err := fixtures.Student(ctx)
+	mustNoErr(t, err)
+
+	entry := group.WaitlistEntry{GroupID: created.GroupID, UserID: student.UserID, CreatedAt: time.Now()}
+
+	waitlistID, err := repos.Waitlist.Create(ctx, entry)
+	mustNoErr(t, err)
+
+	tests := []struct {
+		name  string
+		run   func() error
+		check func(error) bool
+	}{
+		{
+			name: "wait for a second group",
+			run: func() error {
+				other, err := fixtures.Group(ctx)
+				if err != nil {
+					return err
+				}
+				_, err = repos.Waitlist.Create(ctx, group.WaitlistEntry{GroupID: other.GroupID, UserID: student.UserID, CreatedAt: time.Now()})
+				return err
+			},
+			check: postgresql.IsUniqueViolation,
+		},
+		{
+			name:  "delete by id",
+			run:   func() error { return repos.Waitlist.Delete(ctx, waitlistID) },
+			check: func(err error) bool { return err == nil },
+		},
+		{
+			name: "first of an empty waitlist",
+			run: func() error {
+				_, err := repos.Waitlist.GetFirstByGroupId(ctx, created.GroupID)
+				return err
+			},
+			check: func(err error) bool { return errors.Is(err, pgx.ErrNoRows) },
+		},
+		{
+			name: "wait again",
+			run: func() error {
+				_, err := repos.Waitlist.Create(ctx, entry)
+				return err
+			},
+			check: func(err error) bool { return err == nil },
+		},
+		{
+			name:  "delete by user",
+			run:   func() error { return repos.Waitlist.DeleteByUserId(ctx, student.UserID) },
+			check: func(err error) bool { return err == nil },
+		},
+		{
+			name:  "delete by user when not waiting",
+			run:   func() error { return repos.Waitlist.DeleteByUserId(ctx, student.UserID) },
+			check: func(err error) bool { return errors.Is(err, pgx.ErrNoRows) },
+		},
+	}
+
+	// the steps share the entry, so they run in order and stop at the first failure
+	for _, tt := range tests {
+		if err := tt.run(); !tt.check(err) {
+			t.Fatalf("%s: unexpected result: %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"github.com/tclutin/classflow-api/internal/domain/group"
+	"github.com/tclutin/classflow-api/internal/domain/schedule"
+	"github.com/tclutin/classflow-api/internal/domain/user"
+	"github.com/tclutin/classflow-api/internal/repository"
+	"github.com/tclutin/classflow-api/pkg/hash"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPassword is the password of users created by Fixtures.Admin
+const DefaultPassword = "password"
+
+// Fixtures creates entities through the repositories, every call gets unique names and chat ids,
+// so fixtures from one test never collide with another one sharing the database
+type Fixtures struct {
+	repos *repository.Repositories
+	seq   atomic.Int64
+}
+
+func NewFixtures(repos *repository.Repositories) *Fixtures {
+	return &Fixtures{
+		repos: repos,
+	}
+}
+
+// Student creates a telegram user, modify can adjust the entity before it is saved
+func (f *Fixtures) Student(ctx context.Context, modify ...func(*user.User)) (user.User, error) {
+	n := f.seq.Add(1)
+
+	fullname := fmt.Sprintf("Student %d", n)
+	username := fmt.Sprintf("student_%d", n)
+	chatID := 1_000_000 + n
+	delay := int64(15)
+	enabled := true
+
+	entity := user.User{
+		Role:                 user.Student,
+		FullName:             &fullname,
+		TelegramUsername:     &username,
+		TelegramChatID:       &chatID,
+		NotificationDelay:    &delay,
+		NotificationsEnabled: &enabled,
+		CreatedAt:            time.Now(),
+	}
+
+	return f.createUser(ctx, entity, modify)
+}
+
+// Admin creates an email user with the admin role and DefaultPassword
+func (f *Fixtures) Admin(ctx context.Context, modify ...func(*user.User)) (user.User, error) {
+	n := f.seq.Add(1)
+
+	passwordHash, err := hash.NewBcryptHash(DefaultPassword)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	email := fmt.Sprintf("admin%d@classflow.test", n)
+
+	entity := user.User{
+		Email:        &email,
+		PasswordHash: &passwordHash,
+		Role:         user.Admin,
+		CreatedAt:    time.Now(),
+	}
+
+	return f.createUser(ctx, entity, modify)
+}
+
+// Group creates an empty group in the first seeded faculty and program
+func (f *Fixtures) Group(ctx context.Context, modify ...func(*group.Group)) (group.Group, error) {
+	n := f.seq.Add(1)
+
+	faculties, err := f.repos.Edu.GetAllFaculty(ctx)
+	if err != nil || len(faculties) == 0 {
+		return group.Group{}, fmt.Errorf("no seeded faculties: %w", err)
+	}
+
+	programs, err := f.repos.Edu.GetAllProgramsByFacultyId(ctx, faculties[0].FacultyID)
+	if err != nil || len(programs) == 0 {
+		return group.Group{}, fmt.Errorf("no seeded programs: %w", err)
+	}
+
+	entity := group.Group{
+		FacultyID: faculties[0].FacultyID,
+		ProgramID: programs[0].ProgramID,
+		ShortName: fmt.Sprintf("GRP-%d", n),
+		CreatedAt: time.Now(),
+	}
+
+	for _, fn := range modify {
+		fn(&entity)
+	}
+
+	entity.GroupID, err = f.repos.Group.Create(ctx, entity)
+	if err != nil {
+		return group.Group{}, err
+	}
+
+	return entity, nil
+}
+
+// Member adds the user to the group and keeps the number of people in sync
+func (f *Fixtures) Member(ctx context.Context, userID, groupID uint64) error {
+	if _, err := f.repos.Member.Create(ctx, userID, groupID); err != nil {
+		return err
+	}
+
+	_, err := f.repos.Group.AddMembers(ctx, groupID, 1)
+
+	return err
+}
+
+// Schedule uploads one lesson on Monday of each week parity and marks the group as having a schedule
+func (f *Fixtures) Schedule(ctx context.Context, groupID uint64) ([]schedule.Schedule, error) {
+	buildings, err := f.repos.Edu.GetAllBuildings(ctx)
+	if err != nil || len(buildings) == 0 {
+		return nil, fmt.Errorf("no seeded buildings: %w", err)
+	}
+
+	types, err := f.repos.Edu.GetAllTypesOfSubject(ctx)
+	if err != nil || len(types) == 0 {
+		return nil, fmt.Errorf("no seeded types of subject: %w", err)
+	}
+
+	var schedules []schedule.Schedule
+
+	for _, isEven := range []bool{false, true} {
+		schedules = append(schedules, schedule.Schedule{
+			GroupID:         groupID,
+			BuildingsID:     buildings[0].BuildingID,
+			TypeOfSubjectID: types[0].TypeOfSubjectID,
+			SubjectName:     "Математический анализ",
+			Teacher:         "Иванов И. И.",
+			Room:            "101",
+			IsEven:          isEven,
+			DayOfWeek:       1,
+			StartTime:       "09:00",
+			EndTime:         "10:30",
+			CreatedAt:       time.Now(),
+		})
+	}
+
+	if err = f.repos.Schedule.Create(ctx, schedules); err != nil {
+		return nil, err
+	}
+
+	entity, err := f.repos.Group.GetById(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	entity.ExistsSchedule = true
+
+	if err = f.repos.Group.Update(ctx, entity); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (f *Fixtures) createUser(ctx context.Context, entity user.User, modify []func(*user.User)) (user.User, error) {
+	for _, fn := range modify {
+		fn(&entity)
+	}
+
+	userID, err := f.repos.User.Create(ctx, entity)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	entity.UserID = userID
+
+	return entity, nil
+}
@@ -0,0 +1,122 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/tclutin/classflow-api/internal/api"
+	"github.com/tclutin/classflow-api/internal/config"
+	"github.com/tclutin/classflow-api/internal/domain"
+	"github.com/tclutin/classflow-api/internal/repository"
+	"github.com/tclutin/classflow-api/pkg/client/postgresql"
+	"github.com/tclutin/classflow-api/pkg/health"
+	"github.com/tclutin/classflow-api/pkg/jwt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+const jwtSecret = "test-secret"
+
+// Server serves the API built by api.NewRouter on top of the test database, the same way the app wires it
+type Server struct {
+	*httptest.Server
+	Config       *config.Config
+	Repositories *repository.Repositories
+	Services     *domain.Services
+	Fixtures     *Fixtures
+	tokens       *jwt.TokenManager
+}
+
+// Config is the application config for tests, rate limiting is off and lockouts are kept in memory
+func Config() *config.Config {
+	return &config.Config{
+		Environment: "dev",
+		JWT: config.JWT{
+			Secret: jwtSecret,
+			Expire: time.Hour,
+		},
+		Lockout: config.Lockout{
+			Store:         config.MemoryStore,
+			MaxAttempts:   5,
+			IPMaxAttempts: 30,
+			Window:        15 * time.Minute,
+			BaseDelay:     30 * time.Second,
+			MaxDelay:      time.Hour,
+		},
+		RateLimit: config.RateLimit{
+			Enabled: false,
+		},
+		Tracing: config.Tracing{
+			ServiceName: "classflow-api-test",
+		},
+	}
+}
+
+// NewServer starts the server, close it with Close when the test is done
+func NewServer(pg *Postgres, cfg *config.Config) *Server {
+	logger := Logger()
+
+	repositories := repository.NewRepositories(pg.Pool, pg.Pool, logger)
+	if cfg.Lockout.Store == config.MemoryStore {
+		repositories.Lockout = repository.NewMemoryLockoutRepository()
+	}
+
+	tokens := jwt.MustLoadTokenManager(cfg.JWT.Secret)
+
+	services := domain.NewServices(logger, tokens, repositories, postgresql.NewTxManager(pg.Pool), cfg)
+
+	probes := health.New(time.Second)
+	probes.Register("postgres", pg.Pool.Ping)
+
+	return &Server{
+		Server:       httptest.NewServer(api.NewRouter(services, probes, cfg)),
+		Config:       cfg,
+		Repositories: repositories,
+		Services:     services,
+		Fixtures:     NewFixtures(repositories),
+		tokens:       tokens,
+	}
+}
+
+// Token issues an access token for the user without going through a login endpoint
+func (s *Server) Token(userID uint64) (string, error) {
+	return s.tokens.NewToken(userID, s.Config.JWT.Expire)
+}
+
+// Do sends body encoded as JSON to the API, token is sent as a bearer token when not empty
+func (s *Server) Do(ctx context.Context, method, path, token string, body any) (*http.Response, error) {
+	var reader io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.URL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return s.Client().Do(req)
+}
+
+// DecodeJSON reads and closes the response body
+func DecodeJSON(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
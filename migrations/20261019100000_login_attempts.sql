@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.login_attempts (
+    key TEXT PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.login_attempts;
+-- +goose StatementEnd
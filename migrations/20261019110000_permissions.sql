@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.roles (
+    role_name TEXT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS public.permissions (
+    permission_name TEXT PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.role_permissions (
+    role_name TEXT NOT NULL,
+    permission_name TEXT NOT NULL,
+    scope_type TEXT CHECK (scope_type IN ('faculty', 'group')),
+    PRIMARY KEY (role_name, permission_name),
+    FOREIGN KEY (role_name) REFERENCES public.roles (role_name) ON DELETE CASCADE,
+    FOREIGN KEY (permission_name) REFERENCES public.permissions (permission_name) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS public.user_scopes (
+    user_id BIGINT NOT NULL,
+    scope_type TEXT NOT NULL CHECK (scope_type IN ('faculty', 'group')),
+    scope_id BIGINT NOT NULL,
+    PRIMARY KEY (user_id, scope_type, scope_id),
+    FOREIGN KEY (user_id) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+INSERT INTO public.roles (role_name) VALUES ('admin'), ('leader'), ('student');
+
+INSERT INTO public.permissions (permission_name, description) VALUES
+    ('users:create', 'Create administrator accounts'),
+    ('group:create', 'Create groups'),
+    ('group:delete', 'Delete groups'),
+    ('group:join', 'Join a group'),
+    ('group:leave', 'Leave the current group'),
+    ('membership:read', 'Read the current group'),
+    ('members:manage', 'Manage members of a group'),
+    ('schedule:write', 'Upload group schedules'),
+    ('settings:write', 'Update own settings');
+
+INSERT INTO public.role_permissions (role_name, permission_name, scope_type) VALUES
+    ('admin', 'users:create', NULL),
+    ('admin', 'group:create', NULL),
+    ('admin', 'group:delete', NULL),
+    ('admin', 'members:manage', NULL),
+    ('admin', 'schedule:write', NULL),
+    ('leader', 'group:leave', NULL),
+    ('leader', 'membership:read', NULL),
+    ('leader', 'members:manage', 'group'),
+    ('leader', 'settings:write', NULL),
+    ('student', 'group:join', NULL),
+    ('student', 'group:leave', NULL),
+    ('student', 'membership:read', NULL),
+    ('student', 'settings:write', NULL);
+
+ALTER TABLE public.users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE public.users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES public.roles (role_name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.users DROP CONSTRAINT IF EXISTS users_role_fkey;
+ALTER TABLE public.users ADD CONSTRAINT users_role_check CHECK (role IN ('student', 'leader', 'admin'));
+
+DROP TABLE IF EXISTS public.user_scopes;
+DROP TABLE IF EXISTS public.role_permissions;
+DROP TABLE IF EXISTS public.permissions;
+DROP TABLE IF EXISTS public.roles;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO public.roles (role_name) VALUES ('faculty_admin');
+
+INSERT INTO public.permissions (permission_name, description) VALUES
+    ('admins:manage', 'Assign administrators to faculties');
+
+INSERT INTO public.role_permissions (role_name, permission_name, scope_type) VALUES
+    ('admin', 'admins:manage', NULL),
+    ('faculty_admin', 'group:create', 'faculty'),
+    ('faculty_admin', 'group:delete', 'faculty'),
+    ('faculty_admin', 'members:manage', 'faculty'),
+    ('faculty_admin', 'schedule:write', 'faculty');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE public.users SET role = 'admin' WHERE role = 'faculty_admin';
+DELETE FROM public.user_scopes WHERE scope_type = 'faculty';
+DELETE FROM public.roles WHERE role_name = 'faculty_admin';
+DELETE FROM public.permissions WHERE permission_name = 'admins:manage';
+-- +goose StatementEnd
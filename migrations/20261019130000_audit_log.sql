@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.audit_log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id BIGINT NOT NULL,
+    request_id TEXT,
+    ip TEXT,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON public.audit_log (actor_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_target_idx ON public.audit_log (target_type, target_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON public.audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION public.audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON public.audit_log
+    FOR EACH ROW EXECUTE FUNCTION public.audit_log_append_only();
+
+INSERT INTO public.permissions (permission_name, description) VALUES
+    ('audit:read', 'Read the audit log');
+
+INSERT INTO public.role_permissions (role_name, permission_name, scope_type) VALUES
+    ('admin', 'audit:read', NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM public.permissions WHERE permission_name = 'audit:read';
+DROP TABLE IF EXISTS public.audit_log;
+DROP FUNCTION IF EXISTS public.audit_log_append_only();
+-- +goose StatementEnd
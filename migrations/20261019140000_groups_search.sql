@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS groups_short_name_trgm_idx ON public.groups USING gin (short_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS groups_faculty_id_idx ON public.groups (faculty_id);
+CREATE INDEX IF NOT EXISTS groups_program_id_idx ON public.groups (program_id);
+CREATE INDEX IF NOT EXISTS groups_created_at_idx ON public.groups (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS groups_created_at_idx;
+DROP INDEX IF EXISTS groups_program_id_idx;
+DROP INDEX IF EXISTS groups_faculty_id_idx;
+DROP INDEX IF EXISTS groups_short_name_trgm_idx;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE public.groups g
+SET number_of_people = (SELECT COUNT(*) FROM public.members m WHERE m.group_id = g.group_id);
+
+ALTER TABLE public.groups
+    ADD CONSTRAINT groups_number_of_people_check CHECK (number_of_people >= 0);
+
+CREATE INDEX IF NOT EXISTS members_group_id_idx ON public.members (group_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS members_group_id_idx;
+
+ALTER TABLE public.groups DROP CONSTRAINT IF EXISTS groups_number_of_people_check;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.groups
+    ADD COLUMN IF NOT EXISTS capacity INT CHECK (capacity > 0);
+
+CREATE TABLE IF NOT EXISTS public.group_waitlist (
+    waitlist_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL UNIQUE REFERENCES public.users (user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS group_waitlist_group_idx ON public.group_waitlist (group_id, waitlist_id);
+
+CREATE TABLE IF NOT EXISTS public.outbox (
+    event_id BIGSERIAL PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    processed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON public.outbox (event_id) WHERE processed_at IS NULL;
+
+INSERT INTO public.permissions (permission_name, description) VALUES
+    ('outbox:consume', 'Read and acknowledge outbox events');
+
+INSERT INTO public.role_permissions (role_name, permission_name, scope_type) VALUES
+    ('admin', 'outbox:consume', NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM public.permissions WHERE permission_name = 'outbox:consume';
+DROP TABLE IF EXISTS public.outbox;
+DROP TABLE IF EXISTS public.group_waitlist;
+ALTER TABLE public.groups DROP COLUMN IF EXISTS capacity;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.announcements (
+    announcement_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    author_id BIGINT REFERENCES public.users (user_id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    attachment_url TEXT,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS announcements_group_idx ON public.announcements (group_id, pinned DESC, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS public.announcement_reads (
+    announcement_id BIGINT NOT NULL REFERENCES public.announcements (announcement_id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES public.users (user_id) ON DELETE CASCADE,
+    read_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY (announcement_id, user_id)
+);
+
+INSERT INTO public.permissions (permission_name, description) VALUES
+    ('announcements:write', 'Post, edit and delete group announcements');
+
+INSERT INTO public.role_permissions (role_name, permission_name, scope_type) VALUES
+    ('admin', 'announcements:write', NULL),
+    ('faculty_admin', 'announcements:write', 'faculty'),
+    ('leader', 'announcements:write', 'group');
+
+-- leaders are scoped to the group they lead
+INSERT INTO public.user_scopes (user_id, scope_type, scope_id)
+SELECT leader_id, 'group', group_id FROM public.groups WHERE leader_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM public.permissions WHERE permission_name = 'announcements:write';
+DROP TABLE IF EXISTS public.announcement_reads;
+DROP TABLE IF EXISTS public.announcements;
+-- +goose StatementEnd
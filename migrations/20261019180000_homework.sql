@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.homework (
+    homework_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    schedule_id BIGINT REFERENCES public.schedule (schedule_id) ON DELETE SET NULL,
+    subject_name TEXT,
+    description TEXT NOT NULL,
+    due_date DATE NOT NULL,
+    created_by BIGINT REFERENCES public.users (user_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS homework_group_due_idx ON public.homework (group_id, due_date);
+
+CREATE TABLE IF NOT EXISTS public.homework_done (
+    homework_id BIGINT NOT NULL REFERENCES public.homework (homework_id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES public.users (user_id) ON DELETE CASCADE,
+    done_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY (homework_id, user_id)
+);
+
+INSERT INTO public.permissions (permission_name, description) VALUES
+    ('homework:write', 'Assign, edit and delete group homework');
+
+INSERT INTO public.role_permissions (role_name, permission_name, scope_type) VALUES
+    ('admin', 'homework:write', NULL),
+    ('faculty_admin', 'homework:write', 'faculty'),
+    ('leader', 'homework:write', 'group');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM public.permissions WHERE permission_name = 'homework:write';
+DROP TABLE IF EXISTS public.homework_done;
+DROP TABLE IF EXISTS public.homework;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.exams (
+    exam_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    buildings_id BIGINT NOT NULL REFERENCES public.buildings (buildings_id),
+    term TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('exam', 'consultation', 'retake')),
+    subject_name TEXT NOT NULL,
+    examiner TEXT NOT NULL,
+    room TEXT NOT NULL,
+    date DATE NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    CHECK (end_time IS NULL OR end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS exams_group_date_idx ON public.exams (group_id, date);
+CREATE INDEX IF NOT EXISTS exams_group_term_idx ON public.exams (group_id, term);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.exams;
+-- +goose StatementEnd
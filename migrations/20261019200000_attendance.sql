@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.attendance (
+    attendance_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    schedule_id BIGINT NOT NULL REFERENCES public.schedule (schedule_id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES public.users (user_id) ON DELETE CASCADE,
+    lesson_date DATE NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('present', 'absent', 'excused')),
+    marked_by BIGINT REFERENCES public.users (user_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (schedule_id, lesson_date, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS attendance_group_date_idx ON public.attendance (group_id, lesson_date);
+CREATE INDEX IF NOT EXISTS attendance_user_date_idx ON public.attendance (user_id, lesson_date);
+
+INSERT INTO public.permissions (permission_name, description) VALUES
+    ('attendance:write', 'Mark attendance of group members'),
+    ('attendance:export', 'Export attendance summaries');
+
+INSERT INTO public.role_permissions (role_name, permission_name, scope_type) VALUES
+    ('admin', 'attendance:write', NULL),
+    ('faculty_admin', 'attendance:write', 'faculty'),
+    ('leader', 'attendance:write', 'group'),
+    ('admin', 'attendance:export', NULL),
+    ('faculty_admin', 'attendance:export', 'faculty');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM public.permissions WHERE permission_name IN ('attendance:write', 'attendance:export');
+DROP TABLE IF EXISTS public.attendance;
+-- +goose StatementEnd
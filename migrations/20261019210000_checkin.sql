@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.attendance
+    ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'checkin'));
+
+CREATE TABLE IF NOT EXISTS public.checkin_windows (
+    window_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    schedule_id BIGINT NOT NULL REFERENCES public.schedule (schedule_id) ON DELETE CASCADE,
+    lesson_date DATE NOT NULL,
+    secret BYTEA NOT NULL,
+    radius INT CHECK (radius > 0),
+    opened_by BIGINT REFERENCES public.users (user_id) ON DELETE SET NULL,
+    opens_at TIMESTAMP NOT NULL,
+    closes_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (schedule_id, lesson_date),
+    CHECK (closes_at > opens_at)
+);
+
+CREATE INDEX IF NOT EXISTS checkin_windows_group_closes_idx ON public.checkin_windows (group_id, closes_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.checkin_windows;
+ALTER TABLE public.attendance DROP COLUMN IF EXISTS source;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- group scopes of leaders are derived from groups.leader_id, the rows copied from it go stale once the leader changes
+DELETE FROM public.user_scopes WHERE scope_type = 'group';
+
+CREATE INDEX IF NOT EXISTS groups_leader_idx ON public.groups (leader_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS public.groups_leader_idx;
+
+INSERT INTO public.user_scopes (user_id, scope_type, scope_id)
+SELECT leader_id, 'group', group_id FROM public.groups WHERE leader_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
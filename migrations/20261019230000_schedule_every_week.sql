@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- lessons uploaded as a single week run every week, whatever their is_even
+ALTER TABLE public.schedule ADD COLUMN IF NOT EXISTS every_week BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- groups that uploaded one week got every lesson stored with the same parity
+UPDATE public.schedule AS s SET every_week = TRUE
+WHERE NOT EXISTS (
+    SELECT 1 FROM public.schedule AS o WHERE o.group_id = s.group_id AND o.is_even <> s.is_even
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.schedule DROP COLUMN IF EXISTS every_week;
+-- +goose StatementEnd
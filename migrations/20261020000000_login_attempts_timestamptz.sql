@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the stored values are read in the session time zone, the one current_timestamp wrote them in
+ALTER TABLE public.login_attempts
+    ALTER COLUMN locked_until TYPE TIMESTAMPTZ,
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.login_attempts
+    ALTER COLUMN locked_until TYPE TIMESTAMP,
+    ALTER COLUMN updated_at TYPE TIMESTAMP;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the stored values are read in the session time zone, the one current_timestamp wrote them in
+ALTER TABLE public.checkin_windows
+    ALTER COLUMN opens_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN closes_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.checkin_windows
+    ALTER COLUMN opens_at TYPE TIMESTAMP,
+    ALTER COLUMN closes_at TYPE TIMESTAMP,
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+-- +goose StatementEnd
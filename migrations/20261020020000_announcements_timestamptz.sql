@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the stored values are read in the session time zone, the one current_timestamp wrote them in
+ALTER TABLE public.announcements
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE public.announcement_reads
+    ALTER COLUMN read_at TYPE TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.announcement_reads
+    ALTER COLUMN read_at TYPE TIMESTAMP;
+
+ALTER TABLE public.announcements
+    ALTER COLUMN expires_at TYPE TIMESTAMP,
+    ALTER COLUMN created_at TYPE TIMESTAMP,
+    ALTER COLUMN updated_at TYPE TIMESTAMP;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the stored values are read in the session time zone, the one current_timestamp wrote them in,
+-- the column type change rewrites the table without firing the append-only trigger
+ALTER TABLE public.audit_log ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.audit_log ALTER COLUMN created_at TYPE TIMESTAMP;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO public.roles (role_name) VALUES ('former_admin');
+
+-- faculty admins whose last faculty was revoked used to become students, the audit log tells them apart
+UPDATE public.users AS u SET role = 'former_admin'
+WHERE u.role = 'student' AND u.email IS NOT NULL AND (
+    SELECT a.after ->> 'role'
+    FROM public.audit_log AS a
+    WHERE a.target_type = 'user'
+      AND a.target_id = u.user_id
+      AND a.action IN ('admin.faculty.assign', 'admin.faculty.revoke')
+    ORDER BY a.created_at DESC, a.audit_id DESC
+    LIMIT 1
+) = 'student';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE public.users SET role = 'student' WHERE role = 'former_admin';
+DELETE FROM public.roles WHERE role_name = 'former_admin';
+-- +goose StatementEnd
@@ -2,35 +2,152 @@ package postgresql
 
 import (
 	"context"
+	"fmt"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"log/slog"
-	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
-const (
-	maxRetries = 5
-)
+// Config describes a connection pool, zero values keep the pgxpool defaults
+type Config struct {
+	Host     string
+	Port     string
+	Database string
+	User     string
+	Password string
+	SSLMode  string
 
-func NewPool(ctx context.Context, dsn string) *pgxpool.Pool {
-	for i := 0; i < maxRetries; i++ {
-		pool, err := pgxpool.New(ctx, dsn)
-		if err != nil {
-			slog.Error("failed to connect to the database", "retry_count", i+1, "error", err)
-			time.Sleep(3 * time.Second)
-			continue
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	ConnectTimeout    time.Duration
+	StatementTimeout  time.Duration
+
+	// ConnectAttempts and RetryDelay control startup retries, the delay doubles after every attempt up to MaxRetryDelay
+	ConnectAttempts int
+	RetryDelay      time.Duration
+	MaxRetryDelay   time.Duration
+}
+
+// NewPool connects and pings the database, retrying with backoff while it is still starting up
+func NewPool(ctx context.Context, cfg Config, logger *slog.Logger) (*pgxpool.Pool, error) {
+	poolConfig, err := cfg.poolConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database config: %w", err)
+	}
+
+	attempts := max(cfg.ConnectAttempts, 1)
+	delay := cfg.RetryDelay
+
+	for attempt := 1; ; attempt++ {
+		pool, err := connect(ctx, poolConfig)
+		if err == nil {
+			return pool, nil
 		}
 
-		if err = pool.Ping(ctx); err != nil {
-			slog.Error("failed to ping database, retrying...", "retry_count", i+1, "error", err)
-			time.Sleep(3 * time.Second)
+		if attempt >= attempts {
+			return nil, fmt.Errorf("failed to connect to the database after %d attempts: %w", attempt, err)
+		}
+
+		logger.Warn("Failed to connect to the database, retrying...",
+			"host", cfg.Host,
+			"attempt", attempt,
+			"retry_in", delay,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, cfg.MaxRetryDelay)
+	}
+}
+
+func connect(ctx context.Context, config *pgxpool.Config) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+func (c Config) poolConfig() (*pgxpool.Config, error) {
+	config, err := pgxpool.ParseConfig(c.connString())
+	if err != nil {
+		return nil, err
+	}
+
+	if c.MaxConns > 0 {
+		config.MaxConns = c.MaxConns
+	}
+	if c.MinConns > 0 {
+		config.MinConns = c.MinConns
+	}
+	if c.MaxConnLifetime > 0 {
+		config.MaxConnLifetime = c.MaxConnLifetime
+	}
+	if c.MaxConnIdleTime > 0 {
+		config.MaxConnIdleTime = c.MaxConnIdleTime
+	}
+	if c.HealthCheckPeriod > 0 {
+		config.HealthCheckPeriod = c.HealthCheckPeriod
+	}
+	if c.ConnectTimeout > 0 {
+		config.ConnConfig.ConnectTimeout = c.ConnectTimeout
+	}
+	if c.StatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10)
+	}
+
+	config.ConnConfig.Tracer = NewTracer()
+
+	return config, nil
+}
+
+// connString builds a keyword/value connection string with every value quoted,
+// so credentials may contain any characters
+func (c Config) connString() string {
+	params := []struct {
+		key   string
+		value string
+	}{
+		{"host", c.Host},
+		{"port", c.Port},
+		{"dbname", c.Database},
+		{"user", c.User},
+		{"password", c.Password},
+		{"sslmode", c.SSLMode},
+	}
+
+	var parts []string
+
+	for _, param := range params {
+		if param.value == "" {
 			continue
 		}
 
-		return pool
+		parts = append(parts, param.key+"="+quote(param.value))
 	}
 
-	slog.Error("max retries reached, unable to connect to the database")
-	os.Exit(1)
-	return nil
+	return strings.Join(parts, " ")
+}
+
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+
+	return "'" + value + "'"
 }
@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+const tracerName = "github.com/tclutin/classflow-api/pkg/client/postgresql"
+
+// Tracer is a pgx.QueryTracer producing a client span per query named after the statement given with WithQueryName,
+// pgx caches statements under generated names, so those are not meaningful enough to be span names.
+// Queries without a name, e.g. BEGIN and COMMIT of a transaction, are named after the SQL operation
+type Tracer struct{}
+
+type queryNameKey struct{}
+
+// WithQueryName names the spans of the queries run with ctx, repositories pass the method running them
+func WithQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+func (t *Tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBQueryText(data.SQL),
+		semconv.DBOperationName(operationName(data.SQL)),
+	}
+
+	if conn != nil {
+		attrs = append(attrs, semconv.DBNamespace(conn.Config().Database))
+	}
+
+	ctx, _ = otel.Tracer(tracerName).Start(ctx, spanName(ctx, data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+
+	return ctx
+}
+
+func (t *Tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+}
+
+func spanName(ctx context.Context, sql string) string {
+	if name, ok := ctx.Value(queryNameKey{}).(string); ok {
+		return name
+	}
+
+	return operationName(sql)
+}
+
+func operationName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "query"
+	}
+
+	return strings.ToUpper(fields[0])
+}
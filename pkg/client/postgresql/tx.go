@@ -0,0 +1,139 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+const (
+	uniqueViolation      = "23505"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+const (
+	defaultTxAttempts = 3
+	txRetryDelay      = 20 * time.Millisecond
+)
+
+// Querier is implemented by both the pool and a transaction
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// Conn returns the transaction started by TxManager.WithinTx if ctx carries one, otherwise the pool,
+// so repositories take part in a transaction without knowing about it
+func Conn(ctx context.Context, pool *pgxpool.Pool) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return pool
+}
+
+type txOptions struct {
+	pgx.TxOptions
+	attempts int
+}
+
+type TxOption func(*txOptions)
+
+// WithIsoLevel sets the isolation level, the default is read committed
+func WithIsoLevel(level pgx.TxIsoLevel) TxOption {
+	return func(o *txOptions) {
+		o.IsoLevel = level
+	}
+}
+
+// WithAttempts overrides how many times a transaction is run when it fails with a serialization failure or a deadlock
+func WithAttempts(attempts int) TxOption {
+	return func(o *txOptions) {
+		o.attempts = max(attempts, 1)
+	}
+}
+
+// TxManager runs a function within a transaction carried in the context
+type TxManager struct {
+	pool *pgxpool.Pool
+}
+
+func NewTxManager(pool *pgxpool.Pool) *TxManager {
+	return &TxManager{
+		pool: pool,
+	}
+}
+
+// WithinTx commits when fn returns nil and rolls back otherwise. A call inside another transaction joins it,
+// options and retries apply to the outermost call only. The whole fn is run again on retryable errors,
+// so it must not have side effects outside the database
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	options := txOptions{attempts: defaultTxAttempts}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var err error
+
+	for attempt := 1; attempt <= options.attempts; attempt++ {
+		err = m.run(ctx, options.TxOptions, fn)
+		if err == nil || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		}
+	}
+
+	return err
+}
+
+func (m *TxManager) run(ctx context.Context, options pgx.TxOptions, fn func(ctx context.Context) error) error {
+	tx, err := m.pool.BeginTx(ctx, options)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected
+}
+
+// IsUniqueViolation reports whether err was caused by a unique constraint
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,23 @@
+// Package geo holds helpers for coordinates in degrees of latitude and longitude
+package geo
+
+import "math"
+
+// earthRadius is the mean radius of the Earth in meters
+const earthRadius = 6371000
+
+// Distance returns the great-circle distance between two points in meters by the haversine formula
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := radians(lat1)
+	phi2 := radians(lat2)
+	dPhi := radians(lat2 - lat1)
+	dLambda := radians(lon2 - lon1)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc reports a dependency as down by returning an error
+type CheckFunc func(ctx context.Context) error
+
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Health runs readiness checks and tracks whether the application is shutting down
+type Health struct {
+	checks       map[string]CheckFunc
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func New(timeout time.Duration) *Health {
+	return &Health{
+		checks:  make(map[string]CheckFunc),
+		timeout: timeout,
+	}
+}
+
+// Register must be called before the probes are served
+func (h *Health) Register(name string, check CheckFunc) {
+	h.checks[name] = check
+}
+
+// SetShuttingDown makes readiness fail so load balancers stop sending traffic before the server drains
+func (h *Health) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *Health) Ready(ctx context.Context) Report {
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]CheckResult, len(h.checks)+1),
+	}
+
+	if h.shuttingDown.Load() {
+		report.Status = StatusDown
+		report.Checks["shutdown"] = CheckResult{Status: StatusDown, Error: "application is shutting down"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check(ctx)
+
+			result := CheckResult{Status: StatusUp, Duration: time.Since(start).String()}
+			if err != nil {
+				result.Status = StatusDown
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = result
+			if err != nil {
+				report.Status = StatusDown
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,120 @@
+// Package ical writes calendars in the iCalendar format (RFC 5545) understood by calendar apps
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of an encoded calendar
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength is the limit of a content line in octets, longer lines are folded
+const maxLineLength = 75
+
+const (
+	dateTimeLayout = "20060102T150405"
+	utcLayout      = "20060102T150405Z"
+)
+
+// Event is a VEVENT, Start and End are written as floating local times, the calendar app shows them
+// in the time zone of the user as is
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+	Categories  []string
+}
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Encode writes the calendar, stamp is the DTSTAMP of every event
+func (c Calendar) Encode(w io.Writer, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:"+escape(c.ProdID))
+	writeLine(bw, "CALSCALE:GREGORIAN")
+
+	if c.Name != "" {
+		writeLine(bw, "X-WR-CALNAME:"+escape(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+escape(event.UID))
+		writeLine(bw, "DTSTAMP:"+stamp.UTC().Format(utcLayout))
+		writeLine(bw, "DTSTART:"+event.Start.Format(dateTimeLayout))
+
+		if !event.End.IsZero() {
+			writeLine(bw, "DTEND:"+event.End.Format(dateTimeLayout))
+		}
+
+		writeLine(bw, "SUMMARY:"+escape(event.Summary))
+
+		if event.Location != "" {
+			writeLine(bw, "LOCATION:"+escape(event.Location))
+		}
+
+		if event.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escape(event.Description))
+		}
+
+		if len(event.Categories) > 0 {
+			categories := make([]string, 0, len(event.Categories))
+			for _, category := range event.Categories {
+				categories = append(categories, escape(category))
+			}
+
+			writeLine(bw, "CATEGORIES:"+strings.Join(categories, ","))
+		}
+
+		writeLine(bw, "END:VEVENT")
+	}
+
+	writeLine(bw, "END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// writeLine terminates the line with CRLF and folds it at maxLineLength octets
+// without splitting a multibyte character
+func writeLine(w *bufio.Writer, line string) {
+	length := 0
+
+	for _, r := range line {
+		size := len(string(r))
+
+		if length+size > maxLineLength {
+			_, _ = w.WriteString("\r\n ")
+			length = 1
+		}
+
+		_, _ = w.WriteRune(r)
+		length += size
+	}
+
+	_, _ = w.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
@@ -0,0 +1,155 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/sync/errgroup"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Component is a part of the application with its own lifetime. Run blocks until the component
+// is stopped and may be nil for components that only need cleanup, Stop may be nil for components
+// that finish as soon as the context passed to Run is canceled
+type Component struct {
+	Name string
+	Run  func(ctx context.Context) error
+	Stop func(ctx context.Context) error
+}
+
+type running struct {
+	component Component
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// Runner starts components in the order they were added and stops them in reverse order,
+// so dependencies such as the database pool are added first and closed last
+type Runner struct {
+	components      []Component
+	shutdownTimeout time.Duration
+	logger          *slog.Logger
+}
+
+func NewRunner(shutdownTimeout time.Duration, logger *slog.Logger) *Runner {
+	return &Runner{
+		shutdownTimeout: shutdownTimeout,
+		logger:          logger,
+	}
+}
+
+func (r *Runner) Add(components ...Component) {
+	r.components = append(r.components, components...)
+}
+
+// Run starts every component and blocks until ctx is canceled or a component fails,
+// then stops all of them within the shutdown timeout
+func (r *Runner) Run(ctx context.Context) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	started := make([]running, 0, len(r.components))
+
+	for _, component := range r.components {
+		componentCtx, cancel := context.WithCancel(context.Background())
+
+		state := running{
+			component: component,
+			cancel:    cancel,
+			done:      make(chan struct{}),
+		}
+		started = append(started, state)
+
+		if component.Run == nil {
+			close(state.done)
+			continue
+		}
+
+		r.logger.Info("Starting component", "component", component.Name)
+
+		group.Go(func() error {
+			defer close(state.done)
+
+			if err := component.Run(componentCtx); err != nil {
+				return fmt.Errorf("%s: %w", component.Name, err)
+			}
+
+			return nil
+		})
+	}
+
+	<-groupCtx.Done()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), r.shutdownTimeout)
+	defer cancel()
+
+	var stopErrs []error
+
+	for i := len(started) - 1; i >= 0; i-- {
+		if err := r.stop(stopCtx, started[i]); err != nil {
+			stopErrs = append(stopErrs, err)
+		}
+	}
+
+	return errors.Join(group.Wait(), errors.Join(stopErrs...))
+}
+
+func (r *Runner) stop(ctx context.Context, state running) error {
+	r.logger.Info("Stopping component", "component", state.component.Name)
+
+	var err error
+	if state.component.Stop != nil {
+		err = state.component.Stop(ctx)
+	}
+
+	state.cancel()
+
+	select {
+	case <-state.done:
+	case <-ctx.Done():
+		return fmt.Errorf("%s: did not stop in time: %w", state.component.Name, ctx.Err())
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", state.component.Name, err)
+	}
+
+	return nil
+}
+
+// HTTPServer runs the server until it is shut down, http.ErrServerClosed is the normal result of Shutdown
+func HTTPServer(name string, server *http.Server) Component {
+	return Component{
+		Name: name,
+		Run: func(_ context.Context) error {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+		Stop: server.Shutdown,
+	}
+}
+
+// Every calls fn once per interval until the component is stopped, errors are logged and do not stop the loop
+func Every(name string, interval time.Duration, logger *slog.Logger, fn func(ctx context.Context) error) Component {
+	return Component{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+					if err := fn(ctx); err != nil && ctx.Err() == nil {
+						logger.ErrorContext(ctx, "Background job failed", "component", name, "error", err)
+					}
+				}
+			}
+		},
+	}
+}
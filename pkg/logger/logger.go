@@ -32,11 +32,11 @@ func New(environment string, filepath string) *slog.Logger {
 	}
 
 	if environment == prod {
-		logger = slog.New(slog.NewJSONHandler(writer, optsProd))
+		logger = slog.New(NewContextHandler(slog.NewJSONHandler(writer, optsProd)))
 	}
 
 	if environment == dev {
-		logger = slog.New(slog.NewTextHandler(os.Stdout, optsDev))
+		logger = slog.New(NewContextHandler(slog.NewTextHandler(os.Stdout, optsDev)))
 	}
 
 	return logger
@@ -0,0 +1,77 @@
+// Package otp generates short numeric one-time codes, a code is the HOTP value (RFC 4226) of the
+// time step, so it rotates every period like a TOTP code (RFC 6238)
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// SecretSize is the length of a secret made by NewSecret in bytes
+const SecretSize = 32
+
+type Generator struct {
+	Digits int
+	Period time.Duration
+}
+
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, SecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to read random secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// Step is the number of the period t falls in
+func (g Generator) Step(t time.Time) uint64 {
+	return uint64(t.Unix() / int64(g.Period/time.Second))
+}
+
+// Expires returns when the code valid at t rotates
+func (g Generator) Expires(t time.Time) time.Time {
+	return time.Unix(int64(g.Step(t)+1)*int64(g.Period/time.Second), 0)
+}
+
+func (g Generator) Code(secret []byte, t time.Time) string {
+	return g.code(secret, g.Step(t))
+}
+
+// Verify accepts the code of the period t falls in and of the skew periods before it,
+// so a code typed in just before the rotation is still valid
+func (g Generator) Verify(secret []byte, code string, t time.Time, skew int) bool {
+	step := g.Step(t)
+
+	for i := 0; i <= skew && uint64(i) <= step; i++ {
+		if subtle.ConstantTimeCompare([]byte(g.code(secret, step-uint64(i))), []byte(code)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g Generator) code(secret []byte, step uint64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], step)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < g.Digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", g.Digits, value%modulo)
+}
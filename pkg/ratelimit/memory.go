@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time
+}
+
+// MemoryStore is a process-local Store, every replica keeps its own buckets
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (m *MemoryStore) Take(_ context.Context, key string, rule Rule) (Result, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Burst), updatedAt: now}
+		m.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = math.Min(float64(rule.Burst), b.tokens+elapsed*rule.Rate)
+	b.updatedAt = now
+
+	result := Result{Limit: rule.Burst}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rule.Rate)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((float64(rule.Burst) - b.tokens) / rule.Rate)
+	b.fullAt = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// sweep drops buckets that have refilled completely, they are indistinguishable from new ones
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, b := range m.buckets {
+		if now.After(b.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+
+	m.lastSweep = now
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
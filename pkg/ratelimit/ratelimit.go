@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	PrincipalUser      = "user"
+	PrincipalAPIKey    = "api_key"
+	PrincipalAnonymous = "anonymous"
+)
+
+// Rule describes a token bucket: Rate tokens are added per second up to Burst, zero values disable limiting
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+func (r Rule) Unlimited() bool {
+	return r.Rate <= 0 || r.Burst <= 0
+}
+
+// Policy holds separate rules for every kind of principal
+type Policy struct {
+	User      Rule
+	APIKey    Rule
+	Anonymous Rule
+}
+
+func (p Policy) Rule(kind string) Rule {
+	switch kind {
+	case PrincipalUser:
+		return p.User
+	case PrincipalAPIKey:
+		return p.APIKey
+	default:
+		return p.Anonymous
+	}
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps bucket state, implementations must apply Take atomically so that several replicas can share it
+type Store interface {
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+type Principal struct {
+	Kind string
+	ID   string
+}
+
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+	apiKeys  [][]byte
+}
+
+func NewLimiter(store Store, policies map[string]Policy, apiKeys []string) *Limiter {
+	limiter := &Limiter{
+		store:    store,
+		policies: policies,
+	}
+
+	for _, key := range apiKeys {
+		if key != "" {
+			limiter.apiKeys = append(limiter.apiKeys, []byte(key))
+		}
+	}
+
+	return limiter
+}
+
+// Resolve picks the principal of a request: a known API key first, then an authenticated user, then the client IP
+func (l *Limiter) Resolve(apiKey string, userID string, ip string) Principal {
+	if apiKey != "" && l.isKnownAPIKey(apiKey) {
+		sum := sha256.Sum256([]byte(apiKey))
+		return Principal{Kind: PrincipalAPIKey, ID: hex.EncodeToString(sum[:8])}
+	}
+
+	if userID != "" {
+		return Principal{Kind: PrincipalUser, ID: userID}
+	}
+
+	return Principal{Kind: PrincipalAnonymous, ID: ip}
+}
+
+func (l *Limiter) Take(ctx context.Context, policyName string, principal Principal) (Result, error) {
+	policy, ok := l.policies[policyName]
+	if !ok {
+		return Result{Allowed: true}, nil
+	}
+
+	rule := policy.Rule(principal.Kind)
+	if rule.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	return l.store.Take(ctx, policyName+":"+principal.Kind+":"+principal.ID, rule)
+}
+
+func (l *Limiter) isKnownAPIKey(apiKey string) bool {
+	for _, key := range l.apiKeys {
+		if subtle.ConstantTimeCompare(key, []byte(apiKey)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
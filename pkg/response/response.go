@@ -1,9 +1,37 @@
 package response
 
-type APIError struct {
-	Error string `json:"error"`
+import "net/http"
+
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix is prepended to the error code to build the problem type URI
+const ProblemTypePrefix = "urn:classflow:problem:"
+
+// Problem is an RFC 7807 problem details object extended with a stable code, the request ID and field errors
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
-func NewAPIError(error string) APIError {
-	return APIError{Error: error}
+func NewProblem(status int, code string, detail string, fields ...FieldError) Problem {
+	return Problem{
+		Type:   ProblemTypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Errors: fields,
+	}
 }
@@ -0,0 +1,128 @@
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+type clause struct {
+	sql  string
+	args []any
+}
+
+// SelectBuilder appends WHERE, ORDER BY and LIMIT/OFFSET clauses to a base query
+// and numbers the positional arguments, clauses use ? as an argument placeholder
+type SelectBuilder struct {
+	base       string
+	conditions []clause
+	orderBy    []clause
+	limit      *int
+	offset     *int
+}
+
+func Select(base string) *SelectBuilder {
+	return &SelectBuilder{
+		base: base,
+	}
+}
+
+func (b *SelectBuilder) Where(condition string, args ...any) *SelectBuilder {
+	b.conditions = append(b.conditions, clause{sql: condition, args: args})
+	return b
+}
+
+// WhereIf adds the condition only when ok is true, which keeps optional filters on one line
+func (b *SelectBuilder) WhereIf(ok bool, condition string, args ...any) *SelectBuilder {
+	if !ok {
+		return b
+	}
+
+	return b.Where(condition, args...)
+}
+
+func (b *SelectBuilder) OrderBy(expression string, args ...any) *SelectBuilder {
+	b.orderBy = append(b.orderBy, clause{sql: expression, args: args})
+	return b
+}
+
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = &limit
+	return b
+}
+
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.offset = &offset
+	return b
+}
+
+func (b *SelectBuilder) Build() (string, []any) {
+	var sb strings.Builder
+	var args []any
+
+	sb.WriteString(b.base)
+	b.writeWhere(&sb, &args)
+
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		b.writeJoined(&sb, &args, b.orderBy, ", ")
+	}
+
+	if b.limit != nil {
+		args = append(args, *b.limit)
+		sb.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+	}
+
+	if b.offset != nil {
+		args = append(args, *b.offset)
+		sb.WriteString(" OFFSET $" + strconv.Itoa(len(args)))
+	}
+
+	return sb.String(), args
+}
+
+// BuildCount returns a query counting the rows matched by the conditions, ignoring ordering and paging
+func (b *SelectBuilder) BuildCount() (string, []any) {
+	var sb strings.Builder
+	var args []any
+
+	sb.WriteString("SELECT COUNT(*) FROM (")
+	sb.WriteString(b.base)
+	b.writeWhere(&sb, &args)
+	sb.WriteString(") AS counted")
+
+	return sb.String(), args
+}
+
+func (b *SelectBuilder) writeWhere(sb *strings.Builder, args *[]any) {
+	if len(b.conditions) == 0 {
+		return
+	}
+
+	sb.WriteString(" WHERE ")
+	b.writeJoined(sb, args, b.conditions, " AND ")
+}
+
+func (b *SelectBuilder) writeJoined(sb *strings.Builder, args *[]any, clauses []clause, sep string) {
+	for i, c := range clauses {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+
+		bind(sb, args, c)
+	}
+}
+
+// bind replaces every ? in the clause with the next positional argument
+func bind(sb *strings.Builder, args *[]any, c clause) {
+	next := 0
+	for _, r := range c.sql {
+		if r == '?' && next < len(c.args) {
+			*args = append(*args, c.args[next])
+			sb.WriteString("$" + strconv.Itoa(len(*args)))
+			next++
+			continue
+		}
+
+		sb.WriteRune(r)
+	}
+}
@@ -0,0 +1,34 @@
+-- Reference data for faculties, programs, types of subject and buildings.
+-- Every statement must be idempotent, seeds run on every start after migrations.
+-- The initial migration inserts the first of these rows as well, they are skipped here then.
+
+INSERT INTO public.faculties (faculty_name) VALUES
+    ('ИИТ'),
+    ('Математический факультет'),
+    ('Другое')
+ON CONFLICT (faculty_name) DO NOTHING;
+
+INSERT INTO public.programs (faculty_id, program_name)
+SELECT f.faculty_id, p.program_name
+FROM (VALUES
+    ('ИИТ', 'Программная инженерия'),
+    ('ИИТ', 'Прикладная информатика'),
+    ('Математический факультет', 'Прикладная математика'),
+    ('Другое', 'Другое')
+) AS p (faculty_name, program_name)
+JOIN public.faculties f ON f.faculty_name = p.faculty_name
+ON CONFLICT (program_name) DO NOTHING;
+
+INSERT INTO public.type_of_subject (name) VALUES
+    ('Лекция'),
+    ('Практика'),
+    ('Лабораторная работа'),
+    ('Другое')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO public.buildings (name, latitude, longitude, address) VALUES
+    ('1 корпус', 55.177292, 61.319480, 'ул. Братьев Кашириных, 129, Челябинск'),
+    ('2 корпус', 55.180179, 61.328064, 'ул. Молодогвардейцев, 70Б, Челябинск'),
+    ('3 корпус', 55.187305, 61.403047, 'ул. Проспект Победы, 162В, Челябинск'),
+    ('4 корпус', 55.180200, 61.335155, 'ул. Молодогвардейцев, 57А, Челябинск')
+ON CONFLICT (name) DO NOTHING;